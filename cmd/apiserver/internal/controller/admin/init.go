@@ -106,4 +106,5 @@ func Regist(e gin.IRouter) {
 	registToolset(w)
 	registWorkflow(w)
 	registLabel(w)
+	registScore(w)
 }
@@ -0,0 +1,182 @@
+package admin
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/tool"
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/toolimpl"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+)
+
+// simulateScore godoc
+// @Summary      模拟评分权重调整
+// @Description  以工具实例的形式运行评分模拟：在最新轮次的评分配置上替换权重和阈值，在内存中重新计算评分，不写入数据库。与线上排名的前 N 名差异以 json 输出到实例日志中
+// @Tags         score
+// @Accept       json
+// @Produce      json
+// @Param        data  body      model.ScoreSimulationReq  true  "替换的权重和阈值"
+// @Success      202   {object}  model.ToolInstanceHistoryDTO
+// @Failure      400   {object}  string
+// @Failure      500   {object}  string
+// @Router       /admin/score/simulate [post]
+func simulateScore(c *gin.Context) {
+	var req model.ScoreSimulationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	s, err := toolimpl.NewScoreSimulation(storage.GetDefaultAppDatabaseContext(), 0, &req)
+	var invalid *toolimpl.InvalidSimulationError
+	if errors.As(err, &invalid) {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to prepare score simulation: " + err.Error()})
+		return
+	}
+	args, err := s.Args(&req)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to prepare score simulation: " + err.Error()})
+		return
+	}
+
+	username, _, _ := getUser(c)
+	inst, err := tool.CreateAndRun(toolimpl.ScoreSimulationTool, args, username)
+	if err != nil {
+		logger.Errorf("Failed to start score simulation: %v", err)
+		c.JSON(500, gin.H{"error": "Failed to start score simulation: " + err.Error()})
+		return
+	}
+	c.JSON(202, model.ToToolInstanceHistoryDTO(tool.RunningInstanceToHistory(inst)))
+}
+
+// getScoreRound godoc
+// @Summary      查询评分轮次
+// @Description  查询评分轮次的配置、增量信息、异常比例以及是否被阻止发布
+// @Tags         score
+// @Produce      json
+// @Param        round  path      int  true  "轮次"
+// @Success      200    {object}  model.ScoreRoundDTO
+// @Failure      400    {object}  string
+// @Failure      404    {object}  string
+// @Failure      500    {object}  string
+// @Router       /admin/score/rounds/{round} [get]
+func getScoreRound(c *gin.Context) {
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid round"})
+		return
+	}
+
+	r, err := repository.NewScoreRoundRepository(storage.GetDefaultAppDatabaseContext()).GetByRound(round)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to query score round: " + err.Error()})
+		return
+	}
+	if r == nil {
+		c.JSON(404, gin.H{"error": "Score round not found"})
+		return
+	}
+	c.JSON(200, model.ScoreRoundDOToDTO(r))
+}
+
+// getScoreAnomalies godoc
+// @Summary      查询分数异常
+// @Description  分页查询某一轮次相对上一轮的分数异常变化，按 z-score 绝对值降序
+// @Tags         score
+// @Produce      json
+// @Param        round      path      int     true   "轮次"
+// @Param        dimension  query     string  false  "维度（dist_score, lang_score, git_score）"
+// @Param        skip       query     int     false  "跳过数量"
+// @Param        take       query     int     false  "返回数量"
+// @Success      200        {object}  model.PageDTO[model.ScoreAnomalyDTO]
+// @Failure      400        {object}  string
+// @Failure      500        {object}  string
+// @Router       /admin/score/rounds/{round}/anomalies [get]
+func getScoreAnomalies(c *gin.Context) {
+	type Q struct {
+		Skip      int    `form:"skip"`
+		Take      int    `form:"take"`
+		Dimension string `form:"dimension"`
+	}
+	var q = Q{
+		Skip: 0,
+		Take: 100,
+	}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid round"})
+		return
+	}
+
+	repo := repository.NewScoreAnomalyRepository(storage.GetDefaultAppDatabaseContext())
+	cnt, err := repo.CountByRound(round, q.Dimension)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to count score anomalies: " + err.Error()})
+		return
+	}
+	items, err := repo.QueryByRound(round, q.Dimension, q.Skip, q.Take)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to query score anomalies: " + err.Error()})
+		return
+	}
+	anomalies := lo.Map(slices.Collect(items), func(i *repository.ScoreAnomaly, _ int) *model.ScoreAnomalyDTO {
+		return model.ScoreAnomalyDOToDTO(i)
+	})
+
+	c.JSON(200, model.NewPageDTO(cnt, q.Skip, q.Take, anomalies))
+}
+
+// updateScoreRoundBlocked godoc
+// @Summary      阻止或恢复发布评分轮次
+// @Description  手动阻止或恢复发布某一轮次的分数，被阻止的轮次不会出现在排名和查询结果中
+// @Tags         score
+// @Accept       json
+// @Param        round  path      int                                true  "轮次"
+// @Param        data   body      model.UpdateScoreRoundBlockedReq  true  "是否阻止发布"
+// @Success      204    {object}  nil
+// @Failure      400    {object}  string
+// @Failure      500    {object}  string
+// @Router       /admin/score/rounds/{round}/blocked [put]
+func updateScoreRoundBlocked(c *gin.Context) {
+	var req model.UpdateScoreRoundBlockedReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid round"})
+		return
+	}
+
+	err = repository.NewScoreRoundRepository(storage.GetDefaultAppDatabaseContext()).InsertOrUpdate(&repository.ScoreRound{
+		Round:   &round,
+		Blocked: req.Blocked,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update score round: " + err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+func registScore(g gin.IRoutes) {
+	g.POST("/score/simulate", simulateScore)
+	g.GET("/score/rounds/:round", getScoreRound)
+	g.GET("/score/rounds/:round/anomalies", getScoreAnomalies)
+	g.PUT("/score/rounds/:round/blocked", updateScoreRoundBlocked)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/sbom"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 	"github.com/gin-gonic/gin"
@@ -168,6 +169,13 @@ func resultHandler(c *gin.Context) {
 		return
 	}
 
+	embeds, err := repository.NewGitRelationshipRepository(storage.GetDefaultAppDatabaseContext()).QueryEmbeds(*result.GitLink)
+	if err != nil {
+		logger.Error("Error occurred when querying embeds", err)
+		c.JSON(500, "Error occurred when querying embeds")
+		return
+	}
+
 	ret := model.ResultDOToDTO(result)
 	ret.GitDetail = lo.Map(slices.Collect(gitDetails), func(v *repository.ResultGitDetail, i int) model.ResultGitMetadataDTO {
 		return *model.ResultGitDetailDOToDTO(v)
@@ -178,10 +186,151 @@ func resultHandler(c *gin.Context) {
 	ret.DistDetail = lo.Map(slices.Collect(distDetails), func(v *repository.ResultDistDetail, i int) model.ResultDistDetailDTO {
 		return *model.ResultDistDetailDOToDTO(v)
 	})
+	ret.Embeds = lo.Map(slices.Collect(embeds), func(v *repository.GitRelationship, i int) model.ResultEmbedDTO {
+		return *model.GitEmbedDOToDTO(v)
+	})
 
 	c.JSON(200, ret)
 }
 
+// @Summary Explain a score
+// @Description Get the normalized value, weight and contribution of every
+// @Description metric and dimension of a score, as a tree
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ScoreExplainDTO
+// @Failure 404 {object} string
+// @Router /results/{scoreid}/explain [get]
+// @Param scoreid path int true "Score ID"
+func resultExplainHandler(c *gin.Context) {
+	r := repository.NewResultRepository(storage.GetDefaultAppDatabaseContext())
+
+	scoreidStr := c.Param("scoreid")
+	scoreid, err := strconv.Atoi(scoreidStr)
+
+	if err != nil {
+		c.JSON(400, "Invalid query parameters")
+		return
+	}
+
+	result, err := r.GetByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying result", err)
+		c.JSON(500, "Error occurred when querying result")
+		return
+	}
+	if result == nil {
+		c.JSON(404, "Score not found")
+		return
+	}
+
+	breakdown, err := r.QueryBreakdownByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying score breakdown", err)
+		c.JSON(500, "Error occurred when querying score breakdown")
+		return
+	}
+
+	c.JSON(200, model.ScoreBreakdownDOToExplainDTO(result, slices.Collect(breakdown)))
+}
+
+// @Summary Get the monthly activity of a score
+// @Description Get the commits, merge commits, active authors, new
+// @Description contributors and active organizations of every month of
+// @Description the repository of a score
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ResultActivityDTO
+// @Failure 404 {object} string
+// @Router /results/{scoreid}/activity [get]
+// @Param scoreid path int true "Score ID"
+func resultActivityHandler(c *gin.Context) {
+	r := repository.NewResultRepository(storage.GetDefaultAppDatabaseContext())
+
+	scoreidStr := c.Param("scoreid")
+	scoreid, err := strconv.Atoi(scoreidStr)
+
+	if err != nil {
+		c.JSON(400, "Invalid query parameters")
+		return
+	}
+
+	result, err := r.GetByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying result", err)
+		c.JSON(500, "Error occurred when querying result")
+		return
+	}
+	if result == nil {
+		c.JSON(404, "Score not found")
+		return
+	}
+
+	ar := repository.NewGitActivityRepository(storage.GetDefaultAppDatabaseContext())
+	activity, err := ar.QueryByLink(*result.GitLink)
+	if err != nil {
+		logger.Error("Error occurred when querying activity", err)
+		c.JSON(500, "Error occurred when querying activity")
+		return
+	}
+
+	c.JSON(200, model.GitActivityDOToDTO(result, slices.Collect(activity)))
+}
+
+// @Summary Get the SBOM of a score
+// @Description Get the packages declared by the manifests of the
+// @Description repository of a score and their dependencies, with purls,
+// @Description as a CycloneDX 1.5 or SPDX 2.3 JSON document
+// @Produce json
+// @Success 200 {object} object
+// @Failure 404 {object} string
+// @Router /results/{scoreid}/sbom [get]
+// @Param scoreid path int true "Score ID"
+// @Param format query string false "cyclonedx (default) or spdx"
+func resultSBOMHandler(c *gin.Context) {
+	r := repository.NewResultRepository(storage.GetDefaultAppDatabaseContext())
+
+	scoreidStr := c.Param("scoreid")
+	scoreid, err := strconv.Atoi(scoreidStr)
+	format := c.DefaultQuery("format", sbom.FormatCycloneDX)
+
+	if err != nil || (format != sbom.FormatCycloneDX && format != sbom.FormatSPDX) {
+		c.JSON(400, "Invalid query parameters")
+		return
+	}
+
+	result, err := r.GetByScoreID(scoreid)
+	if err != nil {
+		logger.Error("Error occurred when querying result", err)
+		c.JSON(500, "Error occurred when querying result")
+		return
+	}
+	if result == nil {
+		c.JSON(404, "Score not found")
+		return
+	}
+
+	doc, err := sbom.Load(storage.GetDefaultAppDatabaseContext(), *result.GitLink)
+	if err != nil {
+		logger.Error("Error occurred when querying manifests", err)
+		c.JSON(500, "Error occurred when querying manifests")
+		return
+	}
+
+	data, err := sbom.Marshal(doc, format)
+	if err != nil {
+		logger.Error("Error occurred when generating SBOM", err)
+		c.JSON(500, "Error occurred when generating SBOM")
+		return
+	}
+
+	contentType := "application/vnd.cyclonedx+json"
+	if format == sbom.FormatSPDX {
+		contentType = "application/spdx+json"
+	}
+	c.Data(200, contentType, data)
+}
+
 // @Summary Get ranking results
 // @Description Get ranking results, optionally including all details
 // @Accept json
@@ -288,6 +437,9 @@ func cacheRankingPeriodically() {
 func registResult(e gin.IRouter) {
 	e.GET("/results", resultsHandler)
 	e.GET("/results/:scoreid", resultHandler)
+	e.GET("/results/:scoreid/explain", resultExplainHandler)
+	e.GET("/results/:scoreid/activity", resultActivityHandler)
+	e.GET("/results/:scoreid/sbom", resultSBOMHandler)
 	e.GET("/histories", historiesHandler)
 	e.GET("/rankings", rankingHandler)
 
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// serveMissingScore requests url from handler registered at route, with a
+// database without any score.
+func serveMissingScore(t *testing.T, route string, handler gin.HandlerFunc, url string) *httptest.ResponseRecorder {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	defer db.Close()
+	storage.SetDefaultAppDatabaseContext(storage.NewAppDatabaseWithDb(db))
+
+	mock.ExpectQuery("from all_gitlinks_cache").
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"git_link", "score_id"}))
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.GET(route, handler)
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	return w
+}
+
+func TestResultActivityMissingScore(t *testing.T) {
+	w := serveMissingScore(t, "/results/:scoreid/activity", resultActivityHandler, "/results/42/activity")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestResultSBOMMissingScore(t *testing.T) {
+	w := serveMissingScore(t, "/results/:scoreid/sbom", resultSBOMHandler, "/results/42/sbom?format=spdx")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -1,27 +1,56 @@
 package model
 
 import (
+	"cmp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
 )
 
 type ResultGitMetadataDTO struct {
-	License          *[]string  `json:"license"`
-	Language         *[]string  `json:"language"`
-	CreatedSince     *time.Time `json:"createdSince"`
-	UpdatedSince     *time.Time `json:"updatedSince"`
-	ContributorCount *int       `json:"contributorCount"`
-	OrgCount         *int       `json:"orgCount"`
-	CommitFrequency  *float64   `json:"commitFrequency"`
-	UpdateTime       *time.Time `json:"updateTime"`
+	License *[]string `json:"license"`
+	// SPDX expression combining the licenses
+	LicenseExpression *string    `json:"licenseExpression"`
+	Language          *[]string  `json:"language"`
+	CreatedSince      *time.Time `json:"createdSince"`
+	UpdatedSince      *time.Time `json:"updatedSince"`
+	ContributorCount  *int       `json:"contributorCount"`
+	OrgCount          *int       `json:"orgCount"`
+	CommitFrequency   *float64   `json:"commitFrequency"`
+	BusFactor         *int       `json:"busFactor"`
+	TopAuthorShare    *float64   `json:"topAuthorShare"`
+	ActiveMaintainers *int       `json:"activeMaintainers"`
+	// release cadence, lastRelease is null without releases
+	ReleasesLastYear      *int       `json:"releasesLastYear"`
+	MedianReleaseInterval *float64   `json:"medianReleaseInterval"`
+	LastRelease           *time.Time `json:"lastRelease"`
+	SignedReleaseRatio    *float64   `json:"signedReleaseRatio"`
+	UpdateTime            *time.Time `json:"updateTime"`
+	// nil if hygiene signals are not collected for the metrics
+	Hygiene *ResultGitHygieneDTO `json:"hygiene"`
+}
+
+type ResultGitHygieneDTO struct {
+	SecurityPolicy    bool     `json:"securityPolicy"`
+	CodeOwners        bool     `json:"codeOwners"`
+	Fuzzing           bool     `json:"fuzzing"`
+	CISystems         []string `json:"ciSystems"`
+	DependencyBots    []string `json:"dependencyBots"`
+	SignedCommitRatio *float64 `json:"signedCommitRatio"`
+	SignedTagRatio    *float64 `json:"signedTagRatio"`
 }
 
 type ResultLangDetailDTO struct {
-	Type          *int       `json:"type"`
-	LangEcoImpact *float64   `json:"langEcoImpact"`
-	DepCount      *int       `json:"depCount"`
-	UpdateTime    *time.Time `json:"updateTime"`
+	Type *int `json:"type"`
+	// registry name, e.g. npm or rubygems
+	TypeName        string     `json:"typeName"`
+	LangEcoImpact   *float64   `json:"langEcoImpact"`
+	LangEcoPageRank *float64   `json:"langEcoPageRank"`
+	DepCount        *int       `json:"depCount"`
+	UpdateTime      *time.Time `json:"updateTime"`
 }
 
 type ResultDistDetailDTO struct {
@@ -32,17 +61,66 @@ type ResultDistDetailDTO struct {
 	UpdateTime *time.Time `json:"updateTime"`
 }
 
+// ResultEmbedDTO is a repository whose code is in the repository of a
+// score.
+type ResultEmbedDTO struct {
+	GitLink string `json:"link"`
+	// submodule or vendored
+	Source  string  `json:"source"`
+	Path    *string `json:"path"`
+	Version *string `json:"version"`
+}
+
 type ResultDTO struct {
-	ScoreID     *int                   `json:"scoreID"`
-	GitLink     string                 `json:"link"`
-	GitScore    *float64               `json:"gitScore"`
-	GitDetail   []ResultGitMetadataDTO `json:"gitDetail"`
-	LangDetail  []ResultLangDetailDTO  `json:"langDetail"`
-	DistDetail  []ResultDistDetailDTO  `json:"distDetail"`
-	DistroScore *float64               `json:"distroScore"`
-	LangScore   *float64               `json:"langScore"`
-	Score       *float64               `json:"score"`
-	UpdateTime  *time.Time             `json:"updateTime"`
+	ScoreID    *int                   `json:"scoreID"`
+	GitLink    string                 `json:"link"`
+	GitScore   *float64               `json:"gitScore"`
+	GitDetail  []ResultGitMetadataDTO `json:"gitDetail"`
+	LangDetail []ResultLangDetailDTO  `json:"langDetail"`
+	DistDetail []ResultDistDetailDTO  `json:"distDetail"`
+	// repositories embedded as submodules or vendored copies
+	Embeds      []ResultEmbedDTO `json:"embeds"`
+	DistroScore *float64         `json:"distroScore"`
+	LangScore   *float64         `json:"langScore"`
+	Score       *float64         `json:"score"`
+	UpdateTime  *time.Time       `json:"updateTime"`
+	// SPDX expression of the licenses of the repository
+	License *string `json:"license"`
+}
+
+// ScoreExplainNodeDTO is a node of the score tree. The root is the final
+// score, its children are the dimensions and their children the metrics.
+type ScoreExplainNodeDTO struct {
+	Name         string                `json:"name"`
+	Value        *float64              `json:"value"`
+	Normalized   *float64              `json:"normalized"`
+	Weight       *float64              `json:"weight"`
+	Contribution *float64              `json:"contribution"`
+	Children     []ScoreExplainNodeDTO `json:"children"`
+}
+
+type ScoreExplainDTO struct {
+	ScoreID *int                `json:"scoreID"`
+	GitLink string              `json:"link"`
+	Tree    ScoreExplainNodeDTO `json:"tree"`
+}
+
+type ResultActivityMonthDTO struct {
+	// YYYY-MM
+	Month           string `json:"month"`
+	Commits         int    `json:"commits"`
+	MergeCommits    int    `json:"mergeCommits"`
+	ActiveAuthors   int    `json:"activeAuthors"`
+	NewContributors int    `json:"newContributors"`
+	ActiveOrgs      int    `json:"activeOrgs"`
+}
+
+// ResultActivityDTO is the monthly activity of the repository of a score,
+// from its first commit to the month it is last collected.
+type ResultActivityDTO struct {
+	ScoreID *int                     `json:"scoreID"`
+	GitLink string                   `json:"link"`
+	Months  []ResultActivityMonthDTO `json:"months"`
 }
 
 type RankingResultDTO struct {
@@ -59,28 +137,62 @@ func ResultDOToDTO(r *repository.Result) *ResultDTO {
 		LangScore:   *r.LangScore,
 		Score:       *r.Score,
 		UpdateTime:  *r.UpdateTime,
+		License:     lo.FromPtr(r.License),
 	}
 }
 
 func ResultGitDetailDOToDTO(r *repository.ResultGitDetail) *ResultGitMetadataDTO {
+	var hygiene *ResultGitHygieneDTO
+	if *r.SecurityPolicy != nil {
+		hygiene = &ResultGitHygieneDTO{
+			SecurityPolicy:    **r.SecurityPolicy,
+			CodeOwners:        *r.CodeOwners != nil && **r.CodeOwners,
+			Fuzzing:           *r.Fuzzing != nil && **r.Fuzzing,
+			CISystems:         []string{},
+			DependencyBots:    []string{},
+			SignedCommitRatio: *r.SignedCommitRatio,
+			SignedTagRatio:    *r.SignedTagRatio,
+		}
+		if *r.CISystems != nil {
+			hygiene.CISystems = append(hygiene.CISystems, **r.CISystems...)
+		}
+		if *r.DependencyBots != nil {
+			hygiene.DependencyBots = append(hygiene.DependencyBots, **r.DependencyBots...)
+		}
+	}
 	return &ResultGitMetadataDTO{
-		License:          (*[]string)(*r.License),
-		Language:         (*[]string)(*r.Language),
-		CreatedSince:     *r.CreatedSince,
-		UpdatedSince:     *r.UpdatedSince,
-		ContributorCount: *r.ContributorCount,
-		OrgCount:         *r.OrgCount,
-		CommitFrequency:  *r.CommitFrequency,
-		UpdateTime:       *r.UpdateTime,
+		License:               (*[]string)(*r.License),
+		LicenseExpression:     *r.LicenseExpression,
+		Language:              (*[]string)(*r.Language),
+		CreatedSince:          *r.CreatedSince,
+		UpdatedSince:          *r.UpdatedSince,
+		ContributorCount:      *r.ContributorCount,
+		OrgCount:              *r.OrgCount,
+		CommitFrequency:       *r.CommitFrequency,
+		BusFactor:             *r.BusFactor,
+		TopAuthorShare:        *r.TopAuthorShare,
+		ActiveMaintainers:     *r.ActiveMaintainers,
+		ReleasesLastYear:      *r.ReleasesLastYear,
+		MedianReleaseInterval: *r.MedianReleaseInterval,
+		LastRelease:           *r.LastRelease,
+		SignedReleaseRatio:    *r.SignedReleaseRatio,
+		UpdateTime:            *r.UpdateTime,
+		Hygiene:               hygiene,
 	}
 }
 
 func ResultLangDetailDOToDTO(r *repository.ResultLangDetail) *ResultLangDetailDTO {
+	var typeName string
+	if *r.Type != nil {
+		typeName = repository.LangEcosystemType(**r.Type).String()
+	}
 	return &ResultLangDetailDTO{
-		Type:          *r.Type,
-		LangEcoImpact: *r.LangEcoImpact,
-		DepCount:      *r.DepCount,
-		UpdateTime:    *r.UpdateTime,
+		Type:            *r.Type,
+		TypeName:        typeName,
+		LangEcoImpact:   *r.LangEcoImpact,
+		LangEcoPageRank: *r.LangEcoPageRank,
+		DepCount:        *r.DepCount,
+		UpdateTime:      *r.UpdateTime,
 	}
 }
 
@@ -94,6 +206,34 @@ func ResultDistDetailDOToDTO(r *repository.ResultDistDetail) *ResultDistDetailDT
 	}
 }
 
+func GitEmbedDOToDTO(r *repository.GitRelationship) *ResultEmbedDTO {
+	return &ResultEmbedDTO{
+		GitLink: *r.Togitlink,
+		Source:  *r.Source,
+		Path:    *r.Path,
+		Version: *r.Version,
+	}
+}
+
+func GitActivityDOToDTO(r *repository.Result, activity []*repository.GitActivity) *ResultActivityDTO {
+	months := make([]ResultActivityMonthDTO, 0, len(activity))
+	for _, a := range activity {
+		months = append(months, ResultActivityMonthDTO{
+			Month:           a.Month.Format("2006-01"),
+			Commits:         *a.Commits,
+			MergeCommits:    *a.MergeCommits,
+			ActiveAuthors:   *a.ActiveAuthors,
+			NewContributors: *a.NewContributors,
+			ActiveOrgs:      *a.ActiveOrgs,
+		})
+	}
+	return &ResultActivityDTO{
+		ScoreID: *r.ScoreID,
+		GitLink: *r.GitLink,
+		Months:  months,
+	}
+}
+
 func RankingDOToDTO(r *repository.RankingResult) *RankingResultDTO {
 	return &RankingResultDTO{
 		ResultDTO: *ResultDOToDTO(&repository.Result{
@@ -104,7 +244,54 @@ func RankingDOToDTO(r *repository.RankingResult) *RankingResultDTO {
 			LangScore:  r.LangScore,
 			Score:      r.Score,
 			UpdateTime: r.UpdateTime,
+			License:    r.License,
 		}),
 		Ranking: *r.Ranking,
 	}
 }
+
+func ScoreBreakdownDOToExplainDTO(r *repository.Result, breakdown []*repository.ScoreBreakdown) *ScoreExplainDTO {
+	children := make(map[string][]*repository.ScoreBreakdown)
+	for _, b := range breakdown {
+		children[*b.Dimension] = append(children[*b.Dimension], b)
+	}
+
+	var build func(name string) []ScoreExplainNodeDTO
+	build = func(name string) []ScoreExplainNodeDTO {
+		nodes := make([]ScoreExplainNodeDTO, 0, len(children[name]))
+		for _, b := range children[name] {
+			nodes = append(nodes, ScoreExplainNodeDTO{
+				Name:         *b.Metric,
+				Value:        b.Value,
+				Normalized:   b.Normalized,
+				Weight:       b.Weight,
+				Contribution: b.Contribution,
+				Children:     build(*b.Metric),
+			})
+		}
+		// largest contribution first, unknown ones last, then by name
+		slices.SortFunc(nodes, func(a, b ScoreExplainNodeDTO) int {
+			switch {
+			case a.Contribution == nil && b.Contribution != nil:
+				return 1
+			case a.Contribution != nil && b.Contribution == nil:
+				return -1
+			case a.Contribution != nil && *a.Contribution != *b.Contribution:
+				return cmp.Compare(*b.Contribution, *a.Contribution)
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		return nodes
+	}
+
+	return &ScoreExplainDTO{
+		ScoreID: *r.ScoreID,
+		GitLink: *r.GitLink,
+		Tree: ScoreExplainNodeDTO{
+			Name:         "score",
+			Value:        *r.Score,
+			Contribution: *r.Score,
+			Children:     build("score"),
+		},
+	}
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/score"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type ScoreSimulationReq struct {
+	// dimension -> metric -> weight, replaces the weight in the profile of
+	// the live round
+	Weights map[string]map[string]float64 `json:"weights"`
+	// normalization -> dimension -> metric -> threshold, replaces the
+	// threshold in the profile of the live round
+	Thresholds    map[string]map[string]map[string]float64 `json:"thresholds"`
+	Normalization string                                   `json:"normalization"`
+	TopN          int                                      `json:"topN"`
+}
+
+type RankChangeDTO struct {
+	GitLink        string  `json:"gitLink"`
+	LiveRank       *int    `json:"liveRank"`
+	LiveScore      float64 `json:"liveScore"`
+	SimulatedRank  *int    `json:"simulatedRank"`
+	SimulatedScore float64 `json:"simulatedScore"`
+	Delta          int     `json:"delta"`
+}
+
+type ScoreSimulationDTO struct {
+	Round       int              `json:"round"`
+	TopN        int              `json:"topN"`
+	ProfileHash string           `json:"profileHash"`
+	Top         []*RankChangeDTO `json:"top"`
+	Entered     []*RankChangeDTO `json:"entered"`
+	Left        []*RankChangeDTO `json:"left"`
+	// nil if the correlation is undefined, e.g. all scores are equal
+	KendallTau *float64 `json:"kendallTau"`
+	Spearman   *float64 `json:"spearman"`
+}
+
+func rankOrNil(r int) *int {
+	if r == 0 {
+		return nil
+	}
+	return &r
+}
+
+func finiteOrNil(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func RankChangeToDTO(c *score.RankChange) *RankChangeDTO {
+	return &RankChangeDTO{
+		GitLink:        c.GitLink,
+		LiveRank:       rankOrNil(c.LiveRank),
+		LiveScore:      c.LiveScore,
+		SimulatedRank:  rankOrNil(c.SimulatedRank),
+		SimulatedScore: c.SimulatedScore,
+		Delta:          c.Delta,
+	}
+}
+
+func RankingDiffToDTO(diff *score.RankingDiff, profileHash string) *ScoreSimulationDTO {
+	toDTOs := func(changes []*score.RankChange) []*RankChangeDTO {
+		ret := make([]*RankChangeDTO, 0, len(changes))
+		for _, c := range changes {
+			ret = append(ret, RankChangeToDTO(c))
+		}
+		return ret
+	}
+	return &ScoreSimulationDTO{
+		Round:       diff.Round,
+		TopN:        diff.TopN,
+		ProfileHash: profileHash,
+		Top:         toDTOs(diff.Top),
+		Entered:     toDTOs(diff.Entered),
+		Left:        toDTOs(diff.Left),
+		KendallTau:  finiteOrNil(diff.KendallTau),
+		Spearman:    finiteOrNil(diff.Spearman),
+	}
+}
+
+type ScoreRoundDTO struct {
+	Round          int        `json:"round"`
+	ProfileName    *string    `json:"profileName"`
+	ProfileHash    *string    `json:"profileHash"`
+	Normalization  *string    `json:"normalization"`
+	Incremental    *bool      `json:"incremental"`
+	Recomputed     *int       `json:"recomputed"`
+	CarriedForward *int       `json:"carriedForward"`
+	ComparedLinks  *int       `json:"comparedLinks"`
+	AnomalousLinks *int       `json:"anomalousLinks"`
+	AnomalyRate    float64    `json:"anomalyRate"`
+	Blocked        bool       `json:"blocked"`
+	StartTime      *time.Time `json:"startTime"`
+	UpdateTime     *time.Time `json:"updateTime"`
+}
+
+type ScoreAnomalyDTO struct {
+	ID         int64      `json:"id"`
+	Round      int        `json:"round"`
+	GitLink    string     `json:"gitLink"`
+	Dimension  string     `json:"dimension"`
+	Previous   *float64   `json:"previous"`
+	Current    *float64   `json:"current"`
+	Delta      *float64   `json:"delta"`
+	ZScore     *float64   `json:"zScore"`
+	CreateTime *time.Time `json:"createTime"`
+}
+
+type UpdateScoreRoundBlockedReq struct {
+	Blocked *bool `json:"blocked" binding:"required"`
+}
+
+func ScoreRoundDOToDTO(r *repository.ScoreRound) *ScoreRoundDTO {
+	if r == nil {
+		return nil
+	}
+	return &ScoreRoundDTO{
+		Round:          *r.Round,
+		ProfileName:    r.ProfileName,
+		ProfileHash:    r.ProfileHash,
+		Normalization:  r.Normalization,
+		Incremental:    r.Incremental,
+		Recomputed:     r.Recomputed,
+		CarriedForward: r.CarriedForward,
+		ComparedLinks:  r.ComparedLinks,
+		AnomalousLinks: r.AnomalousLinks,
+		AnomalyRate:    score.AnomalyRate(r),
+		Blocked:        r.Blocked != nil && *r.Blocked,
+		StartTime:      r.StartTime,
+		UpdateTime:     r.UpdateTime,
+	}
+}
+
+func ScoreAnomalyDOToDTO(a *repository.ScoreAnomaly) *ScoreAnomalyDTO {
+	if a == nil {
+		return nil
+	}
+	return &ScoreAnomalyDTO{
+		ID:         *a.ID,
+		Round:      *a.Round,
+		GitLink:    *a.GitLink,
+		Dimension:  *a.Dimension,
+		Previous:   a.Previous,
+		Current:    a.Current,
+		Delta:      a.Delta,
+		ZScore:     a.ZScore,
+		CreateTime: a.CreateTime,
+	}
+}
@@ -0,0 +1,192 @@
+package toolimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/model"
+	"github.com/HUSTSecLab/OpenSift/cmd/apiserver/internal/tool"
+	"github.com/HUSTSecLab/OpenSift/pkg/score"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+)
+
+// ScoreSimulationTool re-scores a round from its recorded inputs with
+// replaced weights and thresholds and prints the top N diff against the
+// live ranking as json. It is also launched by POST /admin/score/simulate.
+var ScoreSimulationTool = &tool.Tool{
+	ID:          "10670d96-0fb0-45ce-b2e1-de036f997beb",
+	Name:        "评分模拟",
+	Description: "使用替换后的权重和阈值，基于某一轮次评分时记录的输入数据在内存中重新计算评分，以 json 输出与线上排名的前 N 名差异，不写入数据库。同一时间只能运行一个模拟。",
+	Group:       "评分工具",
+	Args: []tool.ToolArg{
+		{Name: "round", Type: tool.ToolArgTypeInt,
+			Description: "轮次，为 0 时使用最新发布的轮次", Default: 0},
+		{Name: "weights", Type: tool.ToolArgTypeString,
+			Description: "替换的权重，json 格式：维度 -> 指标 -> 权重", Default: "{}"},
+		{Name: "thresholds", Type: tool.ToolArgTypeString,
+			Description: "替换的阈值，json 格式：归一化方式 -> 维度 -> 指标 -> 阈值", Default: "{}"},
+		{Name: "normalization", Type: tool.ToolArgTypeString,
+			Description: "归一化方式，为空时使用该轮次的归一化方式", Default: ""},
+		{Name: "topN", Type: tool.ToolArgTypeInt,
+			Description: "比较的前 N 名", Default: 100},
+	},
+	Run: tool.CanioalizeWrapper(scoreSimulationImpl),
+}
+
+// MaxScoreSimulationTopN is the largest top N a simulation compares.
+const MaxScoreSimulationTopN = 5000
+
+// a simulation keeps a score of every link in memory, so only one runs at
+// a time
+var scoreSimulationMu sync.Mutex
+
+// ScoreSimulation is a simulation request with the profile it is run
+// with.
+type ScoreSimulation struct {
+	Round         int
+	Profile       *score.Profile
+	Normalization string
+	TopN          int
+}
+
+// NewScoreSimulation applies the overrides of req to the profile of round,
+// or of the latest published round if round is 0, and validates the
+// result.
+func NewScoreSimulation(ac storage.AppDatabaseContext, round int, req *model.ScoreSimulationReq) (*ScoreSimulation, error) {
+	topN := req.TopN
+	if topN == 0 {
+		topN = 100
+	}
+	if topN < 0 || topN > MaxScoreSimulationTopN {
+		return nil, &InvalidSimulationError{fmt.Errorf("topN must be between 1 and %d", MaxScoreSimulationTopN)}
+	}
+	if round == 0 {
+		r, err := score.LiveRound(ac)
+		if err != nil {
+			return nil, err
+		}
+		if r == 0 {
+			return nil, fmt.Errorf("no round is published")
+		}
+		round = r
+	}
+	base, err := score.RoundProfile(ac, round)
+	if err != nil {
+		return nil, err
+	}
+	profile := score.SimulationProfile(base, req.Weights, req.Thresholds)
+	if req.Normalization != "" {
+		profile.Normalization = req.Normalization
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, &InvalidSimulationError{fmt.Errorf("invalid weights or thresholds: %w", err)}
+	}
+	return &ScoreSimulation{Round: round, Profile: profile, Normalization: profile.Normalization, TopN: topN}, nil
+}
+
+// InvalidSimulationError is returned if the request is invalid, e.g. the
+// overrides make the profile invalid.
+type InvalidSimulationError struct {
+	Err error
+}
+
+func (e *InvalidSimulationError) Error() string {
+	return "invalid simulation: " + e.Err.Error()
+}
+
+func (e *InvalidSimulationError) Unwrap() error {
+	return e.Err
+}
+
+// Args returns the arguments of ScoreSimulationTool for the simulation.
+func (s *ScoreSimulation) Args(req *model.ScoreSimulationReq) (map[string]any, error) {
+	weights, err := json.Marshal(req.Weights)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := json.Marshal(req.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"round":         s.Round,
+		"weights":       string(weights),
+		"thresholds":    string(thresholds),
+		"normalization": s.Normalization,
+		"topN":          s.TopN,
+	}, nil
+}
+
+func scoreSimulationImpl(args map[string]any, in io.Reader, out io.Writer, errOut io.Writer, kill chan int) error {
+	if !scoreSimulationMu.TryLock() {
+		return fmt.Errorf("another score simulation is running")
+	}
+	defer scoreSimulationMu.Unlock()
+
+	req := &model.ScoreSimulationReq{}
+	round, err := intArg(args, "round")
+	if err != nil {
+		return err
+	}
+	if req.TopN, err = intArg(args, "topN"); err != nil {
+		return err
+	}
+	if err := jsonArg(args, "weights", &req.Weights); err != nil {
+		return err
+	}
+	if err := jsonArg(args, "thresholds", &req.Thresholds); err != nil {
+		return err
+	}
+	req.Normalization, _ = args["normalization"].(string)
+
+	ac := storage.GetDefaultAppDatabaseContext()
+	s, err := NewScoreSimulation(ac, round, req)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Simulating round %d with normalization %s, profile %s\n", s.Round, s.Normalization, s.Profile.Hash())
+
+	diff, err := score.Simulate(ac, s.Round, s.Profile, s.Normalization, s.TopN)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(model.RankingDiffToDTO(diff, s.Profile.Hash()), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(content))
+	return nil
+}
+
+// intArg reads an int argument, which is a float64 if the arguments are
+// decoded from json.
+func intArg(args map[string]any, name string) (int, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("argument %s must be an integer", name)
+	}
+}
+
+// jsonArg decodes a string argument in json into v.
+func jsonArg(args map[string]any, name string, v any) error {
+	content, _ := args[name].(string)
+	if content == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(content), v); err != nil {
+		return fmt.Errorf("argument %s: %w", name, err)
+	}
+	return nil
+}
+
+func init() {
+	tool.RegistTool(ScoreSimulationTool)
+}
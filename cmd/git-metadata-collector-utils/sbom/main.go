@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	collector "github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/sbom"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/spf13/pflag"
+)
+
+var (
+	flagFormat = pflag.String("format", sbom.FormatCycloneDX, "format of the SBOM, cyclonedx or spdx")
+	flagOutput = pflag.StringP("output", "o", "", "file to write the SBOM to, stdout if empty")
+	flagPath   = pflag.String("path", "", "local repository to parse instead of the stored collection results of the git link")
+)
+
+func main() {
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+
+	link := pflag.Arg(0)
+	var doc *sbom.Document
+	if *flagPath != "" {
+		r, err := collector.Open(*flagPath)
+		if err != nil {
+			logger.Fatalf("Failed to open %s: %v", *flagPath, err)
+		}
+		repo, err := git.ParseRepo(r)
+		if err != nil {
+			logger.Fatalf("Failed to parse %s: %v", *flagPath, err)
+		}
+		if link == "" {
+			link = langcollector.NormalizeGitLink(repo.URL)
+		}
+		if link == "" {
+			link = repo.URL
+		}
+		doc = sbom.FromRepo(link, repo)
+	} else {
+		if link == "" {
+			logger.Fatal("Usage: sbom [--format cyclonedx|spdx] [-o file] <git link> | --path <repository> [git link]")
+		}
+		var err error
+		doc, err = sbom.Load(storage.GetDefaultAppDatabaseContext(), link)
+		if err != nil {
+			logger.Fatalf("Failed to load %s: %v", link, err)
+		}
+	}
+
+	data, err := sbom.Marshal(doc, *flagFormat)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	out := os.Stdout
+	if *flagOutput != "" {
+		out, err = os.Create(*flagOutput)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer out.Close()
+	}
+	if _, err := out.Write(append(data, '\n')); err != nil {
+		logger.Fatal(err)
+	}
+}
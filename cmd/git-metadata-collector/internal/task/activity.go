@@ -0,0 +1,27 @@
+package task
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// recordActivity replaces the monthly activity of the repository.
+func recordActivity(gitLink string, repo *git.Repo) {
+	data := lo.Map(repo.Activity, func(a git.MonthlyActivity, _ int) *repository.GitActivity {
+		return &repository.GitActivity{
+			Month:           lo.ToPtr(a.Month),
+			Commits:         lo.ToPtr(a.Commits),
+			MergeCommits:    lo.ToPtr(a.MergeCommits),
+			ActiveAuthors:   lo.ToPtr(a.ActiveAuthors),
+			NewContributors: lo.ToPtr(a.NewContributors),
+			ActiveOrgs:      lo.ToPtr(a.ActiveOrgs),
+		}
+	})
+	ar := repository.NewGitActivityRepository(storage.GetDefaultAppDatabaseContext())
+	if err := ar.Replace(gitLink, data); err != nil {
+		logger.Errorf("Inserting activity of %s Failed: %v", gitLink, err)
+	}
+}
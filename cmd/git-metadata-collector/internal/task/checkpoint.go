@@ -0,0 +1,45 @@
+package task
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+// loadCheckpoint returns the log checkpoint of the last collection of the
+// repository, or nil to walk the full log.
+func loadCheckpoint(gitLink string) *git.LogCheckpoint {
+	cr := repository.NewGitLogCheckpointRepository(storage.GetDefaultAppDatabaseContext())
+	data, err := cr.GetByLink(gitLink)
+	if err != nil || data == nil || data.Checkpoint == nil {
+		return nil
+	}
+	var cp git.LogCheckpoint
+	if err := cp.UnmarshalBinary(*data.Checkpoint); err != nil {
+		logger.Warnf("Decoding log checkpoint of %s failed: %v", gitLink, err)
+		return nil
+	}
+	return &cp
+}
+
+// recordCheckpoint saves the log checkpoint of the repository for the next
+// collection.
+func recordCheckpoint(gitLink string, repo *git.Repo) {
+	if repo.Checkpoint == nil {
+		return
+	}
+	data, err := repo.Checkpoint.MarshalBinary()
+	if err != nil {
+		logger.Errorf("Encoding log checkpoint of %s failed: %v", gitLink, err)
+		return
+	}
+	cr := repository.NewGitLogCheckpointRepository(storage.GetDefaultAppDatabaseContext())
+	err = cr.InsertOrUpdate(&repository.GitLogCheckpoint{
+		GitLink:    &gitLink,
+		Checkpoint: &data,
+	})
+	if err != nil {
+		logger.Errorf("Inserting log checkpoint of %s Failed: %v", gitLink, err)
+	}
+}
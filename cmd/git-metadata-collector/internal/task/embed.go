@@ -0,0 +1,33 @@
+package task
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/samber/lo"
+)
+
+// embedsToDO converts the embeds of the repository into relationships,
+// one per embedded repository and kind, the first by path.
+func embedsToDO(gitLink string, repo *git.Repo) []*repository.GitRelationship {
+	embeds := lo.Filter(repo.Embeds, func(e *git.Embed, _ int) bool { return e.GitLink != gitLink })
+	embeds = lo.UniqBy(embeds, func(e *git.Embed) [2]string { return [2]string{e.GitLink, e.Kind} })
+	return lo.Map(embeds, func(e *git.Embed, _ int) *repository.GitRelationship {
+		return &repository.GitRelationship{
+			Togitlink: lo.ToPtr(e.GitLink),
+			Source:    lo.ToPtr(e.Kind),
+			Path:      sqlutil.ToNullable(e.Path),
+			Version:   sqlutil.ToData(lo.EmptyableToPtr(e.Version)),
+		}
+	})
+}
+
+// recordEmbeds replaces the repositories embedded by the repository.
+func recordEmbeds(gitLink string, repo *git.Repo) {
+	gr := repository.NewGitRelationshipRepository(storage.GetDefaultAppDatabaseContext())
+	if err := gr.ReplaceEmbeds(gitLink, embedsToDO(gitLink, repo)); err != nil {
+		logger.Errorf("Inserting embeds of %s Failed: %v", gitLink, err)
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// optional returns nil for empty or blank values, e.g. the placeholder
+// version of packages without one.
+func optional(s string) **string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return sqlutil.ToNullable(s)
+}
+
+func manifestsToDO(repo *git.Repo) []*repository.GitManifestWithDependencies {
+	ret := make([]*repository.GitManifestWithDependencies, 0, len(repo.Manifests))
+	for _, m := range repo.Manifests {
+		path := m.Path
+		d := &repository.GitManifestWithDependencies{
+			Manifest: &repository.GitManifest{
+				ManifestPath: &path,
+				Workspace:    optional(m.Workspace),
+				Ecosystem:    optional(m.Package.Eco),
+				Name:         optional(m.Package.Name),
+				Version:      optional(m.Package.Version),
+			},
+			Dependencies: make([]*repository.GitManifestDependency, 0, len(m.Dependencies)),
+		}
+		for _, dep := range m.Dependencies {
+			d.Dependencies = append(d.Dependencies, &repository.GitManifestDependency{
+				Ecosystem:         optional(dep.Eco),
+				Name:              optional(dep.Name),
+				VersionConstraint: optional(dep.Version),
+			})
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// recordManifests replaces the dependency inventory of the commit of the
+// repository.
+func recordManifests(gitLink string, repo *git.Repo) {
+	if repo.Commit == "" {
+		return
+	}
+	gm := repository.NewGitManifestRepository(storage.GetDefaultAppDatabaseContext())
+	if err := gm.Replace(gitLink, repo.Commit, manifestsToDO(repo)); err != nil {
+		logger.Errorf("Inserting manifests of %s Failed: %v", gitLink, err)
+	}
+}
@@ -71,6 +71,7 @@ func Collect(gitLink string, disableCollect bool) {
 	}()
 
 	gmr := repository.NewGitMetricsRepository(storage.GetDefaultAppDatabaseContext())
+	ghr := repository.NewGitHygieneRepository(storage.GetDefaultAppDatabaseContext())
 
 	gf, err := gmr.GetGitFileByLink(gitLink)
 	// begin get file path
@@ -140,19 +141,50 @@ func Collect(gitLink string, disableCollect bool) {
 		// 	"gitlink": gitLink,
 		// }).Infof("git metrics collected successfully: %v", gitLink)
 
-		err := gmr.InsertOrUpdate(&repository.GitMetric{
-			GitLink:          sqlutil.ToData(gitLink),
-			CreatedSince:     sqlutil.ToNullable(repo.CreatedSince),
-			UpdatedSince:     sqlutil.ToNullable(repo.UpdatedSince),
-			ContributorCount: sqlutil.ToNullable(repo.ContributorCount),
-			CommitFrequency:  sqlutil.ToNullable(repo.CommitFrequency),
-			OrgCount:         sqlutil.ToNullable(repo.OrgCount),
-			//* License:          sqlutil.ToNullable(pq.StringArray(repo.Licenses)),
-			Language: sqlutil.ToNullable(pq.StringArray(repo.Languages)),
-		})
+		metric := &repository.GitMetric{
+			GitLink:               sqlutil.ToData(gitLink),
+			CreatedSince:          sqlutil.ToNullable(repo.CreatedSince),
+			UpdatedSince:          sqlutil.ToNullable(repo.UpdatedSince),
+			ContributorCount:      sqlutil.ToNullable(repo.ContributorCount),
+			CommitFrequency:       sqlutil.ToNullable(repo.CommitFrequency),
+			OrgCount:              sqlutil.ToNullable(repo.OrgCount),
+			BusFactor:             sqlutil.ToNullable(repo.BusFactor),
+			TopAuthorShare:        sqlutil.ToNullable(repo.TopAuthorShare),
+			ActiveMaintainers:     sqlutil.ToNullable(repo.ActiveMaintainers),
+			ReleasesLastYear:      sqlutil.ToNullable(repo.ReleasesLastYear),
+			MedianReleaseInterval: sqlutil.ToData(repo.MedianReleaseIntervalDays()),
+			LastRelease:           sqlutil.ToData(repo.LastReleaseTime()),
+			SignedReleaseRatio:    sqlutil.ToNullable(repo.SignedReleaseRatio),
+			License:               sqlutil.ToNullable(pq.StringArray(repo.License)),
+			LicenseExpression:     sqlutil.ToData(repo.SPDXExpression()),
+			Language:              sqlutil.ToNullable(pq.StringArray(repo.Languages)),
+		}
+		err := gmr.InsertOrUpdate(metric)
 
 		if err != nil {
 			logger.Errorf("Inserting %s Failed", gitLink)
+			return
+		}
+
+		if metric.ID == nil {
+			return
+		}
+		hygiene := &repository.GitHygiene{
+			GitMetricID:    metric.ID,
+			SecurityPolicy: sqlutil.ToNullable(repo.Hygiene.SecurityPolicy),
+			CodeOwners:     sqlutil.ToNullable(repo.Hygiene.CodeOwners),
+			Fuzzing:        sqlutil.ToNullable(repo.Hygiene.Fuzzing),
+			CISystems:      sqlutil.ToNullable(pq.StringArray(repo.Hygiene.CISystems)),
+			DependencyBots: sqlutil.ToNullable(pq.StringArray(repo.Hygiene.DependencyBots)),
+		}
+		if ratio, ok := repo.Hygiene.SignedCommitRatio(); ok {
+			hygiene.SignedCommitRatio = sqlutil.ToNullable(ratio)
+		}
+		if ratio, ok := repo.Hygiene.SignedTagRatio(); ok {
+			hygiene.SignedTagRatio = sqlutil.ToNullable(ratio)
+		}
+		if err := ghr.InsertOrUpdate(hygiene); err != nil {
+			logger.Errorf("Inserting hygiene of %s Failed: %v", gitLink, err)
 		}
 
 	}
@@ -170,7 +202,7 @@ func Collect(gitLink string, disableCollect bool) {
 	recordClone(true, nil)
 
 	if !disableCollect {
-		repo, err := git.ParseRepo(r)
+		repo, err := git.ParseRepoFrom(r, loadCheckpoint(gitLink))
 		if err != nil {
 			logger.WithFields(map[string]any{
 				"gitlink": gitLink,
@@ -178,5 +210,9 @@ func Collect(gitLink string, disableCollect bool) {
 			return
 		}
 		recordParseSuccess(repo)
+		recordManifests(gitLink, repo)
+		recordEmbeds(gitLink, repo)
+		recordActivity(gitLink, repo)
+		recordCheckpoint(gitLink, repo)
 	}
 }
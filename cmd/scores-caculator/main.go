@@ -1,12 +1,15 @@
 package main
 
 import (
+	"iter"
 	"log"
+	"time"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	scores "github.com/HUSTSecLab/OpenSift/pkg/score"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 	_ "github.com/lib/pq"
 	"github.com/spf13/pflag"
 )
@@ -14,44 +17,144 @@ import (
 var (
 	batchSize     = pflag.Int("batch", 1000, "batch size")
 	calcType      = pflag.String("calc", "all", "calculation type: distro, git, langeco, all")
-	normalization = pflag.String("normalization", "log", "normalization type: log, sigmoid")
+	normalization = pflag.String("normalization", "", "normalization type: log, sigmoid, percentile, zscore, minmax, defaults to the one in the scoring profile")
+	profile       = pflag.String("profile", "", "scoring profile file in yaml or json format, the built-in default profile is used if not set")
+	fittedRound   = pflag.Int("fitted-round", 0, "reuse the normalization parameters fitted in this round instead of fitting them again, for data-driven normalization")
+	simulate      = pflag.Bool("simulate", false, "re-score the current round in memory with the profile and print the top N diff against the live ranking, nothing is written")
+	topN          = pflag.Int("top", 100, "number of top links compared in simulate mode")
+	anomalyZ      = pflag.Float64("anomaly-z", scores.DefaultAnomalyOptions().ZScore, "robust z-score above which a change of a dimension score between rounds is abnormal")
+	anomalyChange = pflag.Float64("anomaly-min-change", scores.DefaultAnomalyOptions().MinRelativeChange, "relative change of a dimension score between rounds below which it is never abnormal")
+	publish       = pflag.Bool("publish", false, "publish the round after the anomaly check if its anomaly rate is at most --max-anomaly-rate, otherwise it stays blocked until the workflow runner or an admin publishes it")
+	maxRate       = pflag.Float64("max-anomaly-rate", 0.05, "highest fraction of abnormally changed links of a round published with --publish")
+	incremental   = pflag.Bool("incremental", false, "only recompute links whose inputs changed since the last round, and carry forward the other scores")
+	maxCarryAge   = pflag.Duration("max-carry-age", 30*24*time.Hour, "in incremental rounds, recompute scores computed longer ago than this if the profile weights time-based metrics, 0 to carry them forever")
 )
 
 func main() {
 	config.RegistCommonFlags(pflag.CommandLine)
 	config.ParseFlags(pflag.CommandLine)
-	ac := storage.GetDefaultAppDatabaseContext()
-	scores.UpdatePackageList(ac)
-	linksMap := scores.FetchGitLink(ac)
-	// linksMap := []string{"https://sourceware.org/git/glibc.git"}
-	gitMeticMap := scores.FetchGitMetrics(ac)
-	langEcoMetricMap := scores.FetchLangEcoMetadata(ac)
-	distMetricMap := scores.FetchDistMetadata(ac)
-	var gitMetadataScore = make(map[string]*scores.GitMetadataScore)
 
-	packageScore := make(map[string]*scores.LinkScore)
-	round := scores.GetRound(ac)
+	if *profile != "" {
+		p, err := scores.LoadProfile(*profile)
+		if err != nil {
+			log.Fatalf("Failed to load scoring profile: %v", err)
+		}
+		scores.UseProfile(p)
+	}
+	if *normalization != "" && *normalization != scores.ActiveProfile().Normalization {
+		// the normalization is part of the profile hash recorded with
+		// every score, so the override goes into a copy of the profile
+		p := *scores.ActiveProfile()
+		p.Normalization = *normalization
+		scores.UseProfile(&p)
+	}
+	*normalization = scores.ActiveProfile().Normalization
+	if _, ok := scores.ActiveProfile().Thresholds[*normalization]; !ok && !scores.IsDataDriven(*normalization) {
+		log.Fatalf("No thresholds for normalization %s in profile %s", *normalization, scores.ActiveProfile().Name)
+	}
+	logger.Infof("Using scoring profile %s (%s)", scores.ActiveProfile().Name, scores.ActiveProfile().Hash())
 
-	for _, link := range linksMap {
-		if _, ok := distMetricMap[link]; !ok {
-			distMetricMap[link] = scores.NewDistScore()
+	ac := storage.GetDefaultAppDatabaseContext()
+	if *simulate {
+		liveRound, err := scores.LiveRound(ac)
+		if err != nil {
+			log.Fatalf("Failed to fetch the live round: %v", err)
+		}
+		diff, err := scores.Simulate(ac, liveRound, scores.ActiveProfile(), *normalization, *topN)
+		if err != nil {
+			log.Fatalf("Failed to simulate scores: %v", err)
 		}
-		distMetricMap[link].CalculateDistScore(*normalization)
+		printRankingDiff(diff)
+		return
+	}
 
-		if _, ok := langEcoMetricMap[link]; !ok {
-			langEcoMetricMap[link] = scores.NewLangEcoScore()
+	var round int
+	var links iter.Seq[string]
+	isIncremental := false
+	opts := scores.StreamOptions{Normalization: *normalization, BatchSize: *batchSize}
+	if r := scores.ResumableRound(ac); r != nil {
+		round = *r.Round
+		scores.ResumeRound(r, *normalization)
+		if r.Checkpoint != nil {
+			opts.Checkpoint = *r.Checkpoint
+		}
+		// the chunk after the checkpoint may be partially written
+		if _, err := repository.NewScoreRepository(ac).DeleteByRoundAfter(round, opts.Checkpoint); err != nil {
+			log.Fatalf("Failed to delete partially written scores: %v", err)
+		}
+		if r.Recomputed != nil {
+			opts.Recomputed = *r.Recomputed
+		}
+		if r.Incremental != nil && *r.Incremental {
+			links, isIncremental = scores.PlanIncremental(ac, round-1, *normalization, *maxCarryAge)
+			if !isIncremental {
+				log.Fatalf("Failed to plan incremental round %d again", round)
+			}
+		}
+		logger.Infof("Resuming round %d after %s, %d links written", round, opts.Checkpoint, opts.Recomputed)
+	} else {
+		startTime := time.Now()
+		scores.UseCalculationTime(startTime)
+		scores.UpdatePackageList(ac)
+		round = scores.GetRound(ac) + 1
+		if *incremental {
+			links, isIncremental = scores.PlanIncremental(ac, round-1, *normalization, *maxCarryAge)
+		}
+		if isIncremental {
+			logger.Infof("Incremental round %d, recomputing links changed since round %d", round, round-1)
 		}
-		langEcoMetricMap[link].CalculateLangEcoScore(*normalization)
+		if scores.IsDataDriven(*normalization) {
+			if *fittedRound > 0 {
+				params, err := scores.FittedParamsOfRound(ac, *fittedRound)
+				if err != nil {
+					log.Fatalf("Failed to load fitted parameters: %v", err)
+				}
+				scores.UseFittedParams(params)
+				logger.Infof("Using %s parameters fitted in round %d", *normalization, *fittedRound)
+			} else {
+				scores.FitNormalizationStream(ac, *normalization)
+				logger.Infof("Fitted %s parameters", *normalization)
+			}
+		}
+		scores.StartRound(ac, round, *normalization, isIncremental, startTime)
+	}
+
+	opts.Round = round
+	if isIncremental {
+		opts.Links = links
+	}
+	logger.Println("Updating database...")
+	recomputed := scores.CalculateStream(ac, opts)
+
+	carried := int64(0)
+	if isIncremental {
+		// links planned again on resume may differ from the first plan, so
+		// everything not scored yet is carried forward
+		carried = scores.CarryForward(ac, round-1, round)
+		logger.Infof("Carried forward %d scores from round %d", carried, round-1)
+	}
+	scores.FinishRound(ac, round, recomputed, int(carried))
+
+	anomalous, compared, baseline, err := scores.DetectAnomalies(ac, round, scores.AnomalyOptions{
+		ZScore:            *anomalyZ,
+		MinRelativeChange: *anomalyChange,
+	})
+	if err != nil {
+		log.Fatalf("Failed to detect anomalies: %v", err)
+	}
+	if baseline > 0 {
+		logger.Infof("%d of %d links changed abnormally since round %d", anomalous, compared, baseline)
+	}
 
-		gitMetadataScore[link] = scores.NewGitMetadataScore()
-		if _, ok := gitMeticMap[link]; !ok {
-			log.Println("No git metadata for ", link)
+	if *publish {
+		published, rate, err := scores.PublishRound(ac, round, *maxRate)
+		if err != nil {
+			log.Fatalf("Failed to publish round %d: %v", round, err)
+		}
+		if !published {
+			logger.Warnf("Round %d is not published, anomaly rate %.4f is above %.4f", round, rate, *maxRate)
 		} else {
-			gitMetadataScore[link].CalculateGitMetadataScore(gitMeticMap[link], *normalization)
+			logger.Infof("Round %d is published", round)
 		}
-		packageScore[link] = scores.NewLinkScore(gitMetadataScore[link], distMetricMap[link], langEcoMetricMap[link], round+1)
-		packageScore[link].CalculateScore(*normalization)
 	}
-	logger.Println("Updating database...")
-	scores.UpdateScore(ac, packageScore)
 }
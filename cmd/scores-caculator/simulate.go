@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	scores "github.com/HUSTSecLab/OpenSift/pkg/score"
+)
+
+func formatRank(rank int) string {
+	if rank == 0 {
+		return "-"
+	}
+	return fmt.Sprint(rank)
+}
+
+func printRankChanges(title string, changes []*scores.RankChange) {
+	fmt.Printf("\n%s (%d)\n", title, len(changes))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tLIVE\tDELTA\tSCORE\tLIVE SCORE\tGIT LINK")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%+d\t%.4f\t%.4f\t%s\n",
+			formatRank(c.SimulatedRank), formatRank(c.LiveRank), c.Delta, c.SimulatedScore, c.LiveScore, c.GitLink)
+	}
+	w.Flush()
+}
+
+func printRankingDiff(diff *scores.RankingDiff) {
+	fmt.Printf("Simulated round %d, top %d\n", diff.Round, diff.TopN)
+	fmt.Printf("Kendall tau: %.4f\n", diff.KendallTau)
+	fmt.Printf("Spearman:    %.4f\n", diff.Spearman)
+	printRankChanges("Top", diff.Top)
+	printRankChanges("Entered", diff.Entered)
+	printRankChanges("Left", diff.Left)
+}
@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/HUSTSecLab/OpenSift/cmd/workflow-runner/internal/workflow"
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/score"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	_ "github.com/lib/pq"
+)
+
+// WorkflowRunCheckAnomalies publishes the latest score round if its
+// anomaly rate is at most the configured threshold. Rounds are blocked
+// from the start, so otherwise the round stays blocked and the task fails,
+// so nothing after it runs for the round.
+func WorkflowRunCheckAnomalies(ctx *workflow.RunningCtx, stop chan struct{}, kill chan struct{}) error {
+	ac := storage.GetDefaultAppDatabaseContext()
+	round, err := repository.NewScoreRepository(ac).GetRound()
+	if err != nil {
+		return fmt.Errorf("failed to get score round: %v", err)
+	}
+
+	maxRate := config.GetWorkflowMaxAnomalyRate()
+	published, rate, err := score.PublishRound(ac, round, maxRate)
+	if err != nil {
+		return fmt.Errorf("failed to publish score round %d: %v", round, err)
+	}
+	fmt.Fprintf(ctx.LoggerFile, "round %d: anomaly rate %.4f, max %.4f\n", round, rate, maxRate)
+	if !published {
+		return fmt.Errorf("round %d is not published, anomaly rate %.4f is above %.4f or the round is not finished", round, rate, maxRate)
+	}
+	return nil
+}
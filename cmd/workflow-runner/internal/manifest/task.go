@@ -7,6 +7,7 @@ import (
 )
 
 var (
+	taskCheckAnomalies      workflow.WorkflowNode
 	taskCalcScore           workflow.WorkflowNode
 	taskUpdateDistruibution workflow.WorkflowNode
 	taskSyncGitMetrics      workflow.WorkflowNode
@@ -18,6 +19,7 @@ var (
 )
 
 var tasks []*workflow.WorkflowNode = []*workflow.WorkflowNode{
+	&taskCheckAnomalies,
 	&taskCalcScore,
 	&taskUpdateDistruibution,
 	&taskSyncGitMetrics,
@@ -33,8 +35,9 @@ func GetAllTasks() []*workflow.WorkflowNode {
 
 func GetTargetTask() *workflow.WorkflowNode {
 	// This function is used to get the target task for the current workflow.
-	// In this case, we return the taskCalcScore as the target task.
-	return &taskCalcScore
+	// In this case, we return the taskCheckAnomalies as the target task,
+	// which publishes the round calculated by taskCalcScore.
+	return &taskCheckAnomalies
 }
 
 func setNodeDefaults(node *workflow.WorkflowNode) {
@@ -46,6 +49,16 @@ func setNodeDefaults(node *workflow.WorkflowNode) {
 }
 
 func initTasks() {
+	/** check score anomalies **/
+	setNodeDefaults(&taskCheckAnomalies)
+	taskCheckAnomalies.Name = "check-score-anomalies"
+	taskCheckAnomalies.Title = "检查分数异常"
+	taskCheckAnomalies.Description = "检查本轮分数相对上一轮的异常变化，异常比例过高时阻止发布本轮分数"
+	taskCheckAnomalies.Run = WorkflowRunCheckAnomalies
+	taskCheckAnomalies.Dependencies = []*workflow.WorkflowNode{
+		&taskCalcScore,
+	}
+
 	/** calculate score **/
 	setNodeDefaults(&taskCalcScore)
 	taskCalcScore.Name = "calc-score"
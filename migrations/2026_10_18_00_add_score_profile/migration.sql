@@ -0,0 +1,5 @@
+ALTER TABLE scores
+ADD COLUMN profile_name varchar;
+
+ALTER TABLE scores
+ADD COLUMN profile_hash varchar;
@@ -0,0 +1,14 @@
+-- dimension is the parent node of the metric in the score tree,
+-- "score" for gitMetadataScore, distScore and langEcoScore themselves
+create table if not exists score_breakdowns
+(
+    score_id     int8    not null references scores (id),
+    dimension    varchar not null,
+    metric       varchar not null,
+    value        float8,
+    normalized   float8,
+    weight       float8,
+    contribution float8,
+
+    primary key (score_id, dimension, metric)
+);
@@ -0,0 +1,19 @@
+create table if not exists score_rounds
+(
+    round           integer primary key,
+    profile_name    varchar,
+    profile_hash    varchar,
+    normalization   varchar,
+    package_list    jsonb,
+    incremental     boolean default false,
+    recomputed      integer default 0,
+    carried_forward integer default 0,
+    start_time      timestamp,
+    update_time     timestamp default now()
+);
+
+create index if not exists idx_scores_round_git_link on scores (round, git_link);
+
+create index if not exists idx_git_metrics_update_time on git_metrics (update_time);
+create index if not exists idx_lang_ecosystems_update_time on lang_ecosystems (update_time);
+create index if not exists idx_distribution_dependencies_update_time on distribution_dependencies (update_time);
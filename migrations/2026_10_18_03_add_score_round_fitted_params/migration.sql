@@ -0,0 +1,2 @@
+alter table score_rounds
+    add column if not exists fitted_params jsonb;
@@ -0,0 +1,36 @@
+create table if not exists score_anomalies
+(
+    id          serial primary key,
+    round       integer not null,
+    git_link    varchar not null,
+    dimension   varchar not null,
+    previous    double precision,
+    current     double precision,
+    delta       double precision,
+    z_score     double precision,
+    create_time timestamp default now()
+);
+
+create index if not exists idx_score_anomalies_round on score_anomalies (round, dimension);
+
+alter table score_rounds
+    add column if not exists compared_links  integer,
+    add column if not exists anomalous_links integer,
+    add column if not exists blocked         boolean default false;
+
+-- rounds blocked from publication are skipped by the ranking
+create or replace view rankings as (
+    select *, rank() over (order by score desc nulls last) as ranking
+            from (select  s.git_link   as git_link,
+                        s.id          as score_id,
+                        s.dist_score  as dist_score,
+                        s.lang_score  as lang_score,
+                        s.git_score   as git_score,
+                        s.score       as score,
+                        s.update_time as update_time
+                        from scores s
+                where s.round = (select max(round) from scores ss
+                                 where not exists (select 1 from score_rounds sr
+                                                   where sr.round = ss.round and sr.blocked))) as t
+    order by score desc nulls last
+);
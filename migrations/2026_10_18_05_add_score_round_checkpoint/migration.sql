@@ -0,0 +1,11 @@
+-- rounds recorded before checkpoints were added are finished
+alter table score_rounds
+    add column if not exists checkpoint varchar,
+    add column if not exists finished   boolean default true;
+
+-- the calculator streams every source ordered by git_link in "C"
+-- collation, so the order is the same as comparing strings in go
+create index if not exists idx_git_metrics_git_link_c on git_metrics (git_link collate "C");
+create index if not exists idx_lang_ecosystems_git_link_c on lang_ecosystems (git_link collate "C");
+create index if not exists idx_distribution_dependencies_git_link_c on distribution_dependencies (git_link collate "C");
+create index if not exists idx_scores_round_git_link_c on scores (round, git_link collate "C");
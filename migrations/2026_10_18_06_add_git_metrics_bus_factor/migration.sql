@@ -0,0 +1,5 @@
+-- maintainer concentration computed from the commit log
+alter table git_metrics
+    add column if not exists bus_factor         integer,
+    add column if not exists top_author_share   double precision,
+    add column if not exists active_maintainers integer;
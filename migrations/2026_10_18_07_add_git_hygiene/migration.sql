@@ -0,0 +1,13 @@
+-- repository hygiene signals collected with each row of git_metrics
+create table if not exists git_hygiene
+(
+    git_metric_id       int8 primary key references git_metrics (id) on delete cascade,
+    security_policy     boolean,
+    code_owners         boolean,
+    fuzzing             boolean,
+    ci_systems          varchar[],
+    dependency_bots     varchar[],
+    signed_commit_ratio double precision,
+    signed_tag_ratio    double precision,
+    update_time         timestamp
+);
@@ -0,0 +1,24 @@
+-- packages declared by the manifests of a repository at a commit
+create table if not exists git_manifests
+(
+    id            bigserial primary key,
+    git_link      varchar not null,
+    commit_hash   varchar not null,
+    manifest_path varchar not null,
+    ecosystem     varchar,
+    name          varchar,
+    version       varchar,
+    update_time   timestamp
+);
+create index if not exists idx_git_manifests_git_link_commit on git_manifests (git_link, commit_hash);
+
+-- dependencies listed by each manifest
+create table if not exists git_manifest_dependencies
+(
+    id                 bigserial primary key,
+    manifest_id        int8 not null references git_manifests (id) on delete cascade,
+    ecosystem          varchar,
+    name               varchar,
+    version_constraint varchar
+);
+create index if not exists idx_git_manifest_dependencies_manifest_id on git_manifest_dependencies (manifest_id);
@@ -0,0 +1,9 @@
+-- git_relationships are projected from distributions and from the
+-- manifests of repositories, the same pair may come from both
+alter table git_relationships
+    add column if not exists source varchar not null default 'distribution';
+alter table git_relationships
+    drop constraint if exists git_relationships_pkey;
+alter table git_relationships
+    add primary key (fromgitlink, togitlink, source);
+create index if not exists idx_git_relationships_source on git_relationships (source);
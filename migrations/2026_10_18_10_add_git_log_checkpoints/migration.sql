@@ -0,0 +1,8 @@
+-- aggregation state of the commit log of each repository, so that
+-- collecting it again only walks the new commits
+create table if not exists git_log_checkpoints
+(
+    git_link    varchar primary key,
+    checkpoint  bytea not null,
+    update_time timestamp
+);
@@ -0,0 +1,13 @@
+-- monthly activity of each repository since its first commit
+create table if not exists git_activity
+(
+    git_link         varchar not null,
+    month            date    not null,
+    commits          int4    not null,
+    merge_commits    int4    not null,
+    active_authors   int4    not null,
+    new_contributors int4    not null,
+    active_orgs      int4    not null,
+    update_time      timestamp,
+    primary key (git_link, month)
+);
@@ -0,0 +1,7 @@
+-- release cadence computed from the version tags, last_release is null
+-- for repositories without releases
+alter table git_metrics
+    add column if not exists releases_last_year      integer,
+    add column if not exists median_release_interval double precision,
+    add column if not exists last_release            date,
+    add column if not exists signed_release_ratio    double precision;
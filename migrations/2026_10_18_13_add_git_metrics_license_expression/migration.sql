@@ -0,0 +1,30 @@
+-- SPDX expression combining the licenses detected in the repository, the
+-- license column holds its license identifiers
+alter table git_metrics
+    add column if not exists license_expression varchar;
+
+-- the ranking shows the license of the git metrics of each score
+drop view if exists rankings;
+create view rankings as (
+    select *, rank() over (order by score desc nulls last) as ranking
+            from (select  s.git_link   as git_link,
+                        s.id          as score_id,
+                        s.dist_score  as dist_score,
+                        s.lang_score  as lang_score,
+                        s.git_score   as git_score,
+                        s.score       as score,
+                        s.update_time as update_time,
+                        (select gm.license_expression
+                         from scores_git sg
+                         join git_metrics gm on sg.git_metrics_id = gm.id
+                         where sg.score_id = s.id
+                         limit 1)     as license
+                        from scores s
+                where s.round = (select max(round) from scores ss
+                                 where not exists (select 1 from score_rounds sr
+                                                   where sr.round = ss.round and sr.blocked))) as t
+    order by score desc nulls last
+);
+
+drop table if exists rankings_cache;
+create table rankings_cache as select * from rankings;
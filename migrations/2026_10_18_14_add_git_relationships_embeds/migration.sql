@@ -0,0 +1,10 @@
+-- repositories embed others as submodules or vendored copies, which are
+-- kept apart from the dependencies declared by packages
+alter table git_relationships
+    add column if not exists kind varchar not null default 'depends';
+-- path of the embedded copy, and its version if known
+alter table git_relationships
+    add column if not exists path varchar;
+alter table git_relationships
+    add column if not exists version varchar;
+create index if not exists idx_git_relationships_fromgitlink_kind on git_relationships (fromgitlink, kind);
@@ -0,0 +1,5 @@
+-- directory of the workspace a manifest of a monorepo belongs to, e.g. of
+-- npm workspaces, Cargo workspaces, Maven modules or go.work, "." at the
+-- root of the repository
+alter table git_manifests
+    add column if not exists workspace varchar;
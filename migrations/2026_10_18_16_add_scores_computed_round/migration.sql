@@ -0,0 +1,4 @@
+-- round in which a score was computed, carried scores keep the round of
+-- the score they are copied from
+alter table scores
+    add column if not exists computed_round integer;
@@ -0,0 +1,3 @@
+-- the whole profile in json, so the formula of a round can be rebuilt
+alter table score_rounds
+    add column if not exists profile varchar;
@@ -0,0 +1,5 @@
+-- who wrote the row, null for the collectors of registries and deps.dev,
+-- gitgraph for the metrics of the git dependency graph, which are only
+-- written for the links the registries do not cover
+alter table lang_ecosystems
+    add column if not exists source varchar;
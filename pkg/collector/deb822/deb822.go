@@ -0,0 +1,92 @@
+// Package deb822 parses the control files of Debian and its derivatives,
+// e.g. the Packages indices of their archives, and the relationships
+// between their packages, see
+// https://www.debian.org/doc/debian-policy/ch-controlfields.html
+package deb822
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Paragraph is a stanza of a control file, by the lowercase names of its
+// fields. The lines of multi-line fields are joined with newlines,
+// without their leading space, and lines of a single dot are empty.
+type Paragraph map[string]string
+
+// Get returns the value of a field, case insensitively, or an empty
+// string if the paragraph does not have it.
+func (p Paragraph) Get(field string) string {
+	return p[strings.ToLower(field)]
+}
+
+// maximum length of a line, as Description fields may be long
+const maxLineSize = 16 * 1024 * 1024
+
+// Parse returns the paragraphs of a control file, which are separated by
+// blank lines. Comment lines and lines without a field are skipped.
+func Parse(r io.Reader) ([]Paragraph, error) {
+	ret := make([]Paragraph, 0)
+	var p Paragraph
+	field := ""
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			if p != nil {
+				ret = append(ret, p)
+			}
+			p, field = nil, ""
+		case strings.HasPrefix(line, "#"):
+		case line[0] == ' ' || line[0] == '\t':
+			// continuation of the last field
+			if field == "" {
+				continue
+			}
+			cont := strings.TrimSpace(line)
+			if cont == "." {
+				cont = ""
+			}
+			if p[field] == "" {
+				p[field] = cont
+			} else {
+				p[field] += "\n" + cont
+			}
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				field = ""
+				continue
+			}
+			if p == nil {
+				p = make(Paragraph)
+			}
+			field = strings.ToLower(strings.TrimSpace(name))
+			p[field] = strings.TrimSpace(value)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return ret, err
+	}
+	if p != nil {
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// Synopsis returns the first line of a Description field.
+func Synopsis(description string) string {
+	line, _, _ := strings.Cut(description, "\n")
+	return strings.TrimSpace(line)
+}
+
+// SourceName returns the name of the source package of a Source field,
+// which may be followed by the version of the source in parentheses.
+func SourceName(source string) string {
+	name, _, _ := strings.Cut(strings.TrimSpace(source), " ")
+	return name
+}
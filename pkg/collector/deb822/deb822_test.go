@@ -0,0 +1,86 @@
+package deb822
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const packages = `Package: curl
+Source: curl (8.5.0-2)
+Version: 8.5.0-2
+Pre-Depends: libc6 (>= 2.34)
+Depends: libcurl4 (= 8.5.0-2),
+ zlib1g (>= 1:1.1.4)
+Recommends: ca-certificates | ca-certificates-java
+Homepage: https://curl.se:443/
+Description: command line tool for transferring data with URL syntax
+ curl is a command line tool for transferring data with URL syntax.
+ .
+ It supports many protocols.
+
+# comment
+Package: exim4-daemon-light
+Provides: mail-transport-agent, exim4-localscanapi-6.0 (= 6.0)
+`
+
+func TestParse(t *testing.T) {
+	paragraphs, err := Parse(strings.NewReader(packages))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paragraphs) != 2 {
+		t.Fatalf("Expected 2 paragraphs, got %d", len(paragraphs))
+	}
+	p := paragraphs[0]
+	if p.Get("package") != "curl" || p.Get("Homepage") != "https://curl.se:443/" {
+		t.Errorf("Wrong fields %v", p)
+	}
+	if got := SourceName(p.Get("Source")); got != "curl" {
+		t.Errorf("Wrong source %q", got)
+	}
+	if got := p.Get("Depends"); got != "libcurl4 (= 8.5.0-2),\nzlib1g (>= 1:1.1.4)" {
+		t.Errorf("Wrong multi-line field %q", got)
+	}
+	if got := p.Get("Description"); !strings.HasSuffix(got, "syntax.\n\nIt supports many protocols.") {
+		t.Errorf("Wrong description %q", got)
+	}
+	if got := Synopsis(p.Get("Description")); got != "command line tool for transferring data with URL syntax" {
+		t.Errorf("Wrong synopsis %q", got)
+	}
+	if paragraphs[1].Get("Package") != "exim4-daemon-light" {
+		t.Errorf("Wrong paragraph after a comment %v", paragraphs[1])
+	}
+}
+
+func TestParseRelations(t *testing.T) {
+	got := ParseRelations("libc6 (>= 2.34), default-mta | mail-transport-agent,\n python3:any (>=3.11~), foo [amd64] <!nocheck> | bar (<< 2) [!i386], , ")
+	want := []Alternatives{
+		{{Name: "libc6", Version: ">= 2.34"}},
+		{{Name: "default-mta"}, {Name: "mail-transport-agent"}},
+		{{Name: "python3", Arch: "any", Version: ">=3.11~"}},
+		{{Name: "foo"}, {Name: "bar", Version: "<< 2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRelations() = %+v, want %+v", got, want)
+	}
+	if names := want[1].Names(); !reflect.DeepEqual(names, []string{"default-mta", "mail-transport-agent"}) {
+		t.Errorf("Wrong names %v", names)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	w, err := ParseWeights(map[string]string{"recommends": "0.2", "SUGGESTS": "0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Weights{PreDepends: 1, Depends: 1, Recommends: 0.2, Suggests: 0.1}
+	if !reflect.DeepEqual(w, want) {
+		t.Errorf("ParseWeights() = %v, want %v", w, want)
+	}
+	for _, bad := range []map[string]string{{"breaks": "1"}, {"depends": "2"}, {"depends": "x"}} {
+		if _, err := ParseWeights(bad); err == nil {
+			t.Errorf("Expected an error for %v", bad)
+		}
+	}
+}
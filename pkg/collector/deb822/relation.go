@@ -0,0 +1,145 @@
+package deb822
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is a kind of relationship between binary packages.
+type Kind string
+
+const (
+	PreDepends Kind = "Pre-Depends"
+	Depends    Kind = "Depends"
+	Recommends Kind = "Recommends"
+	Suggests   Kind = "Suggests"
+)
+
+// Kinds are the relationship fields of binary packages, from the
+// strongest one.
+var Kinds = []Kind{PreDepends, Depends, Recommends, Suggests}
+
+// Weights are the weights of the kinds of relationships in the dependency
+// graph, between 0 and 1. Relationships of kinds without weights are
+// dropped.
+type Weights map[Kind]float64
+
+// DefaultWeights take packages which are installed by default as
+// dependencies, and recommended packages as half of them.
+var DefaultWeights = Weights{
+	PreDepends: 1,
+	Depends:    1,
+	Recommends: 0.5,
+	Suggests:   0,
+}
+
+// ParseWeights returns the default weights overridden by the weights of
+// the kinds, e.g. {"recommends": "0.3"}, whose names are case
+// insensitive.
+func ParseWeights(weights map[string]string) (Weights, error) {
+	ret := make(Weights, len(DefaultWeights))
+	for k, w := range DefaultWeights {
+		ret[k] = w
+	}
+	for name, value := range weights {
+		kind, ok := parseKind(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown relationship %q", name)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || w < 0 || w > 1 {
+			return nil, fmt.Errorf("invalid weight %q of %s, must be between 0 and 1", value, kind)
+		}
+		ret[kind] = w
+	}
+	return ret, nil
+}
+
+func parseKind(name string) (Kind, bool) {
+	for _, k := range Kinds {
+		if strings.EqualFold(string(k), strings.TrimSpace(name)) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// Relation is a relationship with a package, e.g. libc6 (>= 2.34).
+type Relation struct {
+	Name string
+	// architecture qualifier of the name, e.g. any of python3:any
+	Arch string
+	// version constraint, e.g. >= 2.34
+	Version string
+}
+
+// Alternatives are relations separated by |, any of which satisfies the
+// relationship.
+type Alternatives []Relation
+
+// Names returns the names of the alternatives.
+func (a Alternatives) Names() []string {
+	ret := make([]string, 0, len(a))
+	for _, r := range a {
+		ret = append(ret, r.Name)
+	}
+	return ret
+}
+
+// ParseRelations parses a relationship field, e.g. Depends or Provides,
+// into the relationships it lists, each with its alternatives.
+// Architecture restrictions and build profiles of source packages are
+// dropped.
+func ParseRelations(value string) []Alternatives {
+	ret := make([]Alternatives, 0)
+	for _, group := range strings.Split(value, ",") {
+		alts := Alternatives{}
+		for _, s := range strings.Split(group, "|") {
+			if r, ok := parseRelation(s); ok {
+				alts = append(alts, r)
+			}
+		}
+		if len(alts) > 0 {
+			ret = append(ret, alts)
+		}
+	}
+	return ret
+}
+
+func parseRelation(s string) (Relation, bool) {
+	r := Relation{}
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		version, rest := s[i+1:], ""
+		if j := strings.IndexByte(version, ')'); j >= 0 {
+			version, rest = version[:j], version[j+1:]
+		}
+		r.Version = strings.Join(strings.Fields(version), " ")
+		s = s[:i] + " " + rest
+	}
+
+	// [amd64 arm64] and <!nocheck>
+	for _, delims := range []string{"[]", "<>"} {
+		for {
+			i := strings.IndexByte(s, delims[0])
+			if i < 0 {
+				break
+			}
+			j := strings.IndexByte(s[i:], delims[1])
+			if j < 0 {
+				s = s[:i]
+				break
+			}
+			s = s[:i] + s[i+j+1:]
+		}
+	}
+
+	r.Name = strings.TrimSpace(s)
+	if name, arch, ok := strings.Cut(r.Name, ":"); ok {
+		r.Name, r.Arch = name, arch
+	}
+	if r.Name == "" || strings.ContainsAny(r.Name, " \t\n") {
+		return Relation{}, false
+	}
+	return r, true
+}
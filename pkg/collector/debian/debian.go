@@ -2,9 +2,8 @@ package debian
 
 import (
 	"log"
-	"regexp"
-	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/collector/deb822"
 	collector "github.com/HUSTSecLab/OpenSift/pkg/collector/internal"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
@@ -35,47 +34,8 @@ func (dc *DebianCollector) Collect(outputPath string) {
 	}
 }
 
-func (dc *DebianCollector) ParseInfo(data string) {
-	var currentPkg *collector.PackageInfo
-	lines := strings.Split(data, "\n")
-	for _, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "Package:"):
-			if currentPkg != nil {
-				dc.SetPkgInfo(currentPkg.Name, currentPkg)
-			}
-			currentPkg = &collector.PackageInfo{Name: strings.TrimSpace(strings.Split(line, ":")[1])}
-		case strings.Contains(line, "Version:"):
-			currentPkg.Version = strings.TrimSpace(strings.Split(line, ":")[1])
-		case strings.Contains(line, "Description:"):
-			currentPkg.Description = strings.TrimSpace(strings.Split(line, ":")[1])
-		case strings.Contains(line, "Homepage:"):
-			currentPkg.Homepage = strings.TrimSpace(strings.Split(line, ":")[1] + ":" + strings.Split(line, ":")[2])
-		case strings.Contains(line, "Depends:"):
-			depLine := strings.TrimPrefix(line, "Depends: ")
-			re := regexp.MustCompile(`[\w\-\.|]+(?:\s*\([^)]+\))?`)
-			matches := re.FindAllString(depLine, -1)
-
-			var cleanedDeps []string
-			for _, match := range matches {
-				for _, subPkg := range strings.Split(match, "|") {
-					subPkg = strings.TrimSpace(subPkg)
-					if idx := strings.Index(subPkg, " ("); idx != -1 {
-						subPkg = subPkg[:idx]
-					}
-					cleanedDeps = append(cleanedDeps, subPkg)
-				}
-			}
-			currentPkg.DirectDepends = cleanedDeps
-		}
-	}
-	if currentPkg != nil {
-		dc.SetPkgInfo(currentPkg.Name, currentPkg)
-	}
-}
-
-func NewDebianCollector() *DebianCollector {
+func NewDebianCollector(weights deb822.Weights) *DebianCollector {
 	return &DebianCollector{
-		CollecterInterface: collector.NewCollector(repository.Debian, repository.DistPackageTablePrefix("debian")),
+		CollecterInterface: collector.NewDebCollector(repository.Debian, repository.DistPackageTablePrefix("debian"), weights),
 	}
 }
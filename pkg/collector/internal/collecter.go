@@ -357,8 +357,8 @@ func (cl *Collecter) UpdateRelationships(ac storage.AppDatabaseContext) {
 	}
 	err := repo.InsertRelationships(cl.Type, relationships)
 	if err != nil {
-		fmt.Printf("Error inserting relationships for %s: %v\n", cl.Type, err)
+		fmt.Printf("Error inserting relationships for %v: %v\n", cl.Type, err)
 	} else {
-		fmt.Printf("Successfully inserted relationships for %s.\n", cl.Type)
+		fmt.Printf("Successfully inserted relationships for %v.\n", cl.Type)
 	}
 }
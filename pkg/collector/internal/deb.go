@@ -0,0 +1,257 @@
+package collector
+
+import (
+	"container/heap"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/collector/deb822"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// DebCollecter collects the Packages indices of Debian and its
+// derivatives. The dependency graph keeps the kinds of the relationships
+// and their alternatives: every relationship weighs as its kind, split
+// among its alternatives, and virtual packages are resolved to the
+// packages providing them.
+type DebCollecter struct {
+	*Collecter
+	Weights deb822.Weights
+	// weights of the edges of the dependency graph, by the names of the
+	// dependents and of the dependencies
+	edges map[string]map[string]float64
+	// dependents of every package and their weights, see GetDep
+	dependents      map[string]int
+	dependentWeight map[string]float64
+}
+
+func NewDebCollector(Type repository.DistType, DistPackageTablePrefix repository.DistPackageTablePrefix, weights deb822.Weights) CollecterInterface {
+	if weights == nil {
+		weights = deb822.DefaultWeights
+	}
+	return &DebCollecter{
+		Collecter: &Collecter{
+			PkgInfoMap:             make(map[string]PackageInfo),
+			Type:                   Type,
+			DistPackageTablePrefix: DistPackageTablePrefix,
+		},
+		Weights: weights,
+	}
+}
+
+// homepage returns the URL of a Homepage field, or an empty string for
+// placeholders, e.g. <insert the upstream URL, if relevant>.
+func homepage(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return s
+	}
+	return ""
+}
+
+// ParseInfo parses the paragraphs of Packages indices. Packages listed
+// more than once, e.g. in several components, keep their last paragraph.
+func (dc *DebCollecter) ParseInfo(data string) {
+	paragraphs, err := deb822.Parse(strings.NewReader(data))
+	if err != nil {
+		log.Println("Error parsing package info:", err)
+	}
+	for _, p := range paragraphs {
+		name := p.Get("Package")
+		if name == "" {
+			continue
+		}
+		pkg := &PackageInfo{
+			Name:        name,
+			Version:     p.Get("Version"),
+			Description: deb822.Synopsis(p.Get("Description")),
+			Homepage:    homepage(p.Get("Homepage")),
+			Source:      deb822.SourceName(p.Get("Source")),
+			Relations:   make(map[deb822.Kind][][]string),
+		}
+		for _, provided := range deb822.ParseRelations(p.Get("Provides")) {
+			pkg.Provides = append(pkg.Provides, provided[0].Name)
+		}
+		for _, kind := range deb822.Kinds {
+			for _, alts := range deb822.ParseRelations(p.Get(string(kind))) {
+				names := alts.Names()
+				pkg.Relations[kind] = append(pkg.Relations[kind], names)
+				if dc.Weights[kind] > 0 {
+					pkg.DirectDepends = append(pkg.DirectDepends, names...)
+				}
+			}
+		}
+		pkg.DirectDepends = lo.Uniq(pkg.DirectDepends)
+		dc.SetPkgInfo(name, pkg)
+	}
+}
+
+// buildEdges returns the weighted edges of the dependency graph. A
+// relationship weighs as its kind, split among the packages its
+// alternatives resolve to: the package of the name, or the packages
+// providing it if it is virtual. A package depending on another in
+// several ways takes the heaviest one.
+func (dc *DebCollecter) buildEdges() map[string]map[string]float64 {
+	providers := make(map[string][]string)
+	for name, pkg := range dc.PkgInfoMap {
+		for _, p := range pkg.Provides {
+			if p != name {
+				providers[p] = append(providers[p], name)
+			}
+		}
+	}
+	for _, names := range providers {
+		slices.Sort(names)
+	}
+	resolve := func(name string) []string {
+		if _, ok := dc.PkgInfoMap[name]; ok {
+			return []string{name}
+		}
+		return providers[name]
+	}
+
+	edges := make(map[string]map[string]float64, len(dc.PkgInfoMap))
+	for name, pkg := range dc.PkgInfoMap {
+		out := make(map[string]float64)
+		for kind, groups := range pkg.Relations {
+			w := dc.Weights[kind]
+			if w <= 0 {
+				continue
+			}
+			for _, alts := range groups {
+				targets := lo.Without(lo.Uniq(lo.FlatMap(alts, func(alt string, _ int) []string { return resolve(alt) })), name)
+				if len(targets) == 0 {
+					continue
+				}
+				share := w / float64(len(targets))
+				for _, t := range targets {
+					out[t] = max(out[t], share)
+				}
+			}
+		}
+		edges[name] = out
+	}
+	return edges
+}
+
+// reachItem is a package reached from another, with the weight of the
+// heaviest path to it.
+type reachItem struct {
+	name   string
+	weight float64
+}
+
+type reachQueue []reachItem
+
+func (q reachQueue) Len() int           { return len(q) }
+func (q reachQueue) Less(i, j int) bool { return q[i].weight > q[j].weight }
+func (q reachQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *reachQueue) Push(x any)        { *q = append(*q, x.(reachItem)) }
+func (q *reachQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// reach returns the packages the package depends on directly or
+// indirectly, in the order they are reached, and the weights of the
+// heaviest paths to them, which are the products of the weights of their
+// edges.
+func (dc *DebCollecter) reach(name string) ([]string, map[string]float64) {
+	weights := map[string]float64{name: 1}
+	order := make([]string, 0)
+	done := make(map[string]bool)
+	q := &reachQueue{{name, 1}}
+	for q.Len() > 0 {
+		item := heap.Pop(q).(reachItem)
+		if done[item.name] {
+			continue
+		}
+		done[item.name] = true
+		order = append(order, item.name)
+		for dep, w := range dc.edges[item.name] {
+			if w = item.weight * w; !done[dep] && w > weights[dep] {
+				weights[dep] = w
+				heap.Push(q, reachItem{dep, w})
+			}
+		}
+	}
+	return order, weights
+}
+
+// GetDep records the packages every package depends on directly or
+// indirectly, the package first as the other collectors do, and the
+// weighted dependents of every package, see reach.
+func (dc *DebCollecter) GetDep() {
+	dc.edges = dc.buildEdges()
+	dc.dependents = make(map[string]int)
+	dc.dependentWeight = make(map[string]float64)
+	for pkgName := range dc.PkgInfoMap {
+		deps, weights := dc.reach(pkgName)
+		for _, dep := range deps[1:] {
+			dc.dependents[dep]++
+			dc.dependentWeight[dep] += weights[dep]
+		}
+		pkgInfo := dc.PkgInfoMap[pkgName]
+		pkgInfo.IndirectDepends = deps
+		dc.PkgInfoMap[pkgName] = pkgInfo
+	}
+}
+
+// GetDepCount sets the dependents of every package found by GetDep.
+func (dc *DebCollecter) GetDepCount() {
+	for pkgName, pkgInfo := range dc.PkgInfoMap {
+		pkgInfo.DependsCount = dc.dependents[pkgName]
+		pkgInfo.DependsWeight = dc.dependentWeight[pkgName]
+		dc.PkgInfoMap[pkgName] = pkgInfo
+	}
+}
+
+// PageRank computes the pagerank of the weighted dependency graph, where
+// every package shares its rank among its dependencies in proportion to
+// the weights of the edges.
+func (dc *DebCollecter) PageRank(d float64, iterations int) {
+	if dc.edges == nil {
+		dc.edges = dc.buildEdges()
+	}
+	N := float64(len(dc.PkgInfoMap))
+	ranks := make(map[string]float64, len(dc.PkgInfoMap))
+	for pkgName := range dc.PkgInfoMap {
+		ranks[pkgName] = 1.0 / N
+	}
+
+	for i := 0; i < iterations; i++ {
+		newRanks := make(map[string]float64, len(ranks))
+		for pkgName := range dc.PkgInfoMap {
+			newRanks[pkgName] = (1 - d) / N
+		}
+		for pkgName, out := range dc.edges {
+			total := 0.0
+			for _, w := range out {
+				total += w
+			}
+			for dep, w := range out {
+				newRanks[dep] += d * ranks[pkgName] * w / total
+			}
+		}
+		ranks = newRanks
+	}
+
+	for pkgName, rank := range ranks {
+		pkgInfo := dc.PkgInfoMap[pkgName]
+		pkgInfo.PageRank = rank
+		dc.PkgInfoMap[pkgName] = pkgInfo
+	}
+}
+
+// CalculateDistImpact computes the impact of every package by its
+// weighted dependents.
+func (dc *DebCollecter) CalculateDistImpact() {
+	for pkgName, pkgInfo := range dc.PkgInfoMap {
+		pkgInfo.Impact = pkgInfo.DependsWeight / float64(dc.DistRepoCount)
+		dc.PkgInfoMap[pkgName] = pkgInfo
+	}
+}
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/collector/deb822"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+const debPackages = `Package: app
+Source: app-src (1.0-1)
+Version: 1.0-1
+Depends: libc6, default-mta | mail-transport-agent
+Recommends: docs
+Suggests: extras
+Homepage: <insert the upstream URL, if relevant>
+Description: an application
+ with a long description
+
+Package: libc6
+Version: 2.36-9
+Homepage: https://www.gnu.org:443/software/libc/
+
+Package: exim4
+Provides: mail-transport-agent
+Depends: libc6
+
+Package: postfix
+Provides: mail-transport-agent
+Pre-Depends: libc6
+
+Package: docs
+Depends: libc6
+
+Package: extras
+`
+
+func TestDebCollecter(t *testing.T) {
+	dc := NewDebCollector(repository.Debian, repository.DistPackageTablePrefix("debian"), nil).(*DebCollecter)
+	dc.ParseInfo(debPackages)
+	if len(dc.PkgInfoMap) != 6 {
+		t.Fatalf("Expected 6 packages, got %d", len(dc.PkgInfoMap))
+	}
+
+	app := dc.PkgInfoMap["app"]
+	if app.Source != "app-src" || app.Homepage != "" || app.Description != "an application" {
+		t.Errorf("Wrong package %+v", app)
+	}
+	if libc := dc.PkgInfoMap["libc6"]; libc.Homepage != "https://www.gnu.org:443/software/libc/" {
+		t.Errorf("Wrong homepage %q", libc.Homepage)
+	}
+	wantRelations := map[deb822.Kind][][]string{
+		deb822.Depends:    {{"libc6"}, {"default-mta", "mail-transport-agent"}},
+		deb822.Recommends: {{"docs"}},
+		deb822.Suggests:   {{"extras"}},
+	}
+	if !reflect.DeepEqual(app.Relations, wantRelations) {
+		t.Errorf("Wrong relations %v", app.Relations)
+	}
+
+	// the virtual package is split between its providers, and suggested
+	// packages weigh nothing
+	edges := dc.buildEdges()
+	wantEdges := map[string]float64{"libc6": 1, "exim4": 0.5, "postfix": 0.5, "docs": 0.5}
+	if !reflect.DeepEqual(edges["app"], wantEdges) {
+		t.Errorf("Wrong edges of app %v", edges["app"])
+	}
+
+	dc.GetDep()
+	dc.PageRank(0.85, 20)
+	dc.GetDepCount()
+	dc.DistRepoCount = 10
+	dc.CalculateDistImpact()
+
+	libc := dc.PkgInfoMap["libc6"]
+	if libc.DependsCount != 4 || libc.DependsWeight != 4 {
+		t.Errorf("Expected 4 dependents of libc6, got %d weighing %v", libc.DependsCount, libc.DependsWeight)
+	}
+	if libc.Impact != 0.4 {
+		t.Errorf("Wrong impact of libc6 %v", libc.Impact)
+	}
+	if exim := dc.PkgInfoMap["exim4"]; exim.DependsCount != 1 || exim.DependsWeight != 0.5 {
+		t.Errorf("Expected app weighing 0.5 to depend on exim4, got %d weighing %v", exim.DependsCount, exim.DependsWeight)
+	}
+	if extras := dc.PkgInfoMap["extras"]; extras.DependsCount != 0 {
+		t.Errorf("Expected no dependents of extras, got %d", extras.DependsCount)
+	}
+	if deps := dc.PkgInfoMap["app"].IndirectDepends; deps[0] != "app" || len(deps) != 5 {
+		t.Errorf("Wrong indirect dependencies of app %v", deps)
+	}
+
+	total := 0.0
+	for _, pkg := range dc.PkgInfoMap {
+		total += pkg.PageRank
+	}
+	if dc.PkgInfoMap["libc6"].PageRank <= dc.PkgInfoMap["exim4"].PageRank || math.IsNaN(total) {
+		t.Errorf("Wrong pageranks, libc6 %v and exim4 %v", dc.PkgInfoMap["libc6"].PageRank, dc.PkgInfoMap["exim4"].PageRank)
+	}
+}
@@ -3,6 +3,7 @@ package collector
 import (
 	"log"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/collector/deb822"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 )
@@ -27,6 +28,16 @@ type PackageInfo struct {
 	Gitlink                string
 	Type                   repository.DistType
 	DistPackageTablePrefix repository.DistPackageTablePrefix
+	// source package of the Debian family, whose git link the binary
+	// packages without one take
+	Source string
+	// virtual packages provided by the package of the Debian family
+	Provides []string
+	// relationships of the package of the Debian family by their kinds,
+	// with the names of their alternatives
+	Relations map[deb822.Kind][][]string
+	// number of dependents weighted by the kinds of their relationships
+	DependsWeight float64
 }
 
 type PackageURL []string
@@ -123,7 +134,10 @@ func (pkg *PackageInfo) GetGitlinkByPkg(ac storage.AppDatabaseContext) {
 	if err != nil {
 		log.Println("Error getting package info from database:", err)
 	}
-	if pkgInfo.GitLink != nil {
+	if (pkgInfo == nil || pkgInfo.GitLink == nil) && pkg.Source != "" && pkg.Source != pkg.Name {
+		pkgInfo, err = repo.GetByName(pkg.Source)
+	}
+	if pkgInfo != nil && pkgInfo.GitLink != nil {
 		pkg.Gitlink = *pkgInfo.GitLink
 	} else {
 		log.Println("Error getting package info from database:", err)
@@ -77,6 +77,12 @@ func RegistGitStorageFlags(flag *pflag.FlagSet) {
 	flag.StringP("git-storage", "s", "", "path to git storage location")
 	viper.BindPFlag("git.storage", flag.Lookup("git-storage"))
 	viper.BindEnv("git.storage", "GIT_STORAGE_PATH")
+	flag.String("git-clone-mode", "mirror", "how repositories are cloned: mirror, blobless (no blobs but those parsed) or treeless (no trees and blobs but those of HEAD)")
+	viper.BindPFlag("git.clone-mode", flag.Lookup("git-clone-mode"))
+	viper.BindEnv("git.clone-mode", "GIT_CLONE_MODE")
+	flag.String("git-backend", "go-git", "how repositories are analyzed: go-git, or cli to stream the log and the tree from system git")
+	viper.BindPFlag("git.backend", flag.Lookup("git-backend"))
+	viper.BindEnv("git.backend", "GIT_BACKEND")
 }
 
 func RegistGithubTokenFlags(flag *pflag.FlagSet) {
@@ -111,7 +117,9 @@ func RegistWebFlags(flag *pflag.FlagSet) {
 
 func RegistWorkflowRunnerFlags(flag *pflag.FlagSet) {
 	flag.String("workflow-runner-history-dir", "./workflow_history", "workflow history dir")
+	flag.Float64("workflow-runner-max-anomaly-rate", 0.05, "rounds with a higher fraction of abnormally changed links are not published")
 	viper.BindPFlag("workflow.history-dir", flag.Lookup("workflow-runner-history-dir"))
+	viper.BindPFlag("workflow.max-anomaly-rate", flag.Lookup("workflow-runner-max-anomaly-rate"))
 }
 
 // include config file, database, log
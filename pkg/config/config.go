@@ -107,6 +107,14 @@ func GetGitStoragePath() string {
 	return viper.GetString("git.storage")
 }
 
+func GetGitCloneMode() string {
+	return viper.GetString("git.clone-mode")
+}
+
+func GetGitBackend() string {
+	return viper.GetString("git.backend")
+}
+
 func GetRpcCollectorAddress() string {
 	return viper.GetString("rpc.collector")
 }
@@ -166,3 +174,7 @@ func GetWebPredefinedSuperAdmins() []string {
 func GetWorkflowHistoryDir() string {
 	return viper.GetString("workflow.history-dir")
 }
+
+func GetWorkflowMaxAnomalyRate() float64 {
+	return viper.GetFloat64("workflow.max-anomaly-rate")
+}
@@ -356,6 +356,9 @@ func Depsdev(batchSize int, workerPoolSize int, calculatePageRankFlag bool, debu
 						ltype = repository.NuGet
 					case "pypi":
 						ltype = repository.Pypi
+					default:
+						// other registries are collected by langcollector
+						return true
 					}
 
 					key := langEcoKey{
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/config"
 	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
@@ -66,7 +67,15 @@ func CloneOutProcess(u *url.RepoURL, path string, progress io.Writer) (*gogit.Re
 
 	defer os.RemoveAll(tmpDir)
 
-	cmd := exec.Command("git", "clone", "--mirror", "--progress", u.URL, tmpDir)
+	filter, err := cloneFilter()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"clone", "--mirror", "--progress"}
+	if filter != "" {
+		args = append(args, "--filter="+filter)
+	}
+	cmd := exec.Command("git", append(args, u.URL, tmpDir)...)
 	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
 	cmd.Stderr = progress
 	cmd.Stdout = progress
@@ -89,6 +98,13 @@ func CloneOutProcess(u *url.RepoURL, path string, progress io.Writer) (*gogit.Re
 		return nil, err
 	}
 
+	if strings.HasPrefix(filter, "tree:") {
+		// clone --mirror names the remote origin
+		if err := fetchHeadTrees(path, "origin"); err != nil {
+			return nil, err
+		}
+	}
+
 	return Open(path)
 }
 
@@ -234,6 +250,10 @@ func Update(u *url.RepoURL, path string, progress io.Writer) (*gogit.Repository,
 		return r, err
 	}
 
+	if remote, filter, ok := partialClone(r); ok {
+		return updatePartial(path, remote, filter, progress)
+	}
+
 	remoteRefs, err := r.Remotes()
 	if err != nil {
 		return r, err
@@ -0,0 +1,197 @@
+package collector
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// clone modes of config.GetGitCloneMode. Partial clones miss objects,
+// which are fetched on demand from the promisor remote, see FetchObjects.
+const (
+	// all objects
+	CloneModeMirror = "mirror"
+	// commits and trees, blobs on demand
+	CloneModeBlobless = "blobless"
+	// commits, trees of HEAD, and blobs on demand
+	CloneModeTreeless = "treeless"
+)
+
+var cloneFilters = map[string]string{
+	CloneModeMirror:   "",
+	CloneModeBlobless: "blob:none",
+	CloneModeTreeless: "tree:0",
+}
+
+var errNotPartial = errors.New("not a partial clone")
+
+// cloneFilter returns the --filter of the configured clone mode, or an
+// empty string for full clones.
+func cloneFilter() (string, error) {
+	mode := config.GetGitCloneMode()
+	if mode == "" {
+		mode = CloneModeMirror
+	}
+	filter, ok := cloneFilters[mode]
+	if !ok {
+		return "", fmt.Errorf("unknown git clone mode %q", mode)
+	}
+	return filter, nil
+}
+
+// partialClone returns the promisor remote and the filter of a partial
+// clone, see gitrepository-layout(5). Mirror clones only mark the remote
+// as promisor.
+func partialClone(r *gogit.Repository) (string, string, bool) {
+	cfg, err := r.Config()
+	if err != nil {
+		return "", "", false
+	}
+	remote := cfg.Raw.Section("extensions").Option("partialclone")
+	if remote == "" {
+		for _, s := range cfg.Raw.Section("remote").Subsections {
+			if s.Option("promisor") == "true" {
+				remote = s.Name
+				break
+			}
+		}
+	}
+	if remote == "" {
+		return "", "", false
+	}
+	filter := cfg.Raw.Section("remote").Subsection(remote).Option("partialclonefilter")
+	return remote, filter, true
+}
+
+// IsPartial reports whether the repository is a partial clone.
+func IsPartial(r *gogit.Repository) bool {
+	_, _, ok := partialClone(r)
+	return ok
+}
+
+// RepoPath returns the path of a repository on the filesystem.
+func RepoPath(r *gogit.Repository) (string, error) {
+	s, ok := r.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", errors.New("repository is not on the filesystem")
+	}
+	return s.Filesystem().Root(), nil
+}
+
+// GitCommand returns a command running system git in the repository at
+// path, which never prompts for credentials.
+func GitCommand(path string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// runGit runs system git in the repository at path. The output goes to
+// progress if it is not nil, it is discarded and errors include stderr
+// otherwise.
+func runGit(path string, stdin io.Reader, progress io.Writer, args ...string) error {
+	cmd := GitCommand(path, args...)
+	var stderr bytes.Buffer
+	if progress != nil {
+		cmd.Stdout = progress
+		cmd.Stderr = progress
+	} else {
+		cmd.Stderr = &stderr
+	}
+	if stdin != nil {
+		cmd.Stdin = stdin
+	} else if devnull, err := os.Open(os.DevNull); err == nil {
+		defer devnull.Close()
+		cmd.Stdin = devnull
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// FetchObjects fetches missing objects of a partial clone in one request,
+// and returns the reopened repository, as go-git only reads the packs
+// present when it is opened.
+func FetchObjects(r *gogit.Repository, hashes []plumbing.Hash) (*gogit.Repository, error) {
+	remote, _, ok := partialClone(r)
+	if !ok {
+		return r, errNotPartial
+	}
+	path, err := RepoPath(r)
+	if err != nil {
+		return r, err
+	}
+
+	ids := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		ids = append(ids, h.String())
+	}
+	if err := fetchMissing(path, remote, ids); err != nil {
+		return r, err
+	}
+	return Open(path)
+}
+
+// fetchMissing fetches objects of a partial clone by id in one request.
+func fetchMissing(path, remote string, ids []string) error {
+	var stdin strings.Builder
+	for _, id := range ids {
+		stdin.WriteString(id + "\n")
+	}
+	// the same fetch git runs for missing objects, see partial-clone(7)
+	return runGit(path, strings.NewReader(stdin.String()), nil,
+		"-c", "fetch.negotiationAlgorithm=noop",
+		"fetch", remote, "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no",
+		"--filter=blob:none", "--stdin")
+}
+
+// fetchHeadTrees fetches the trees of HEAD missing in treeless clones in
+// one request, as git would fetch each of them in another round trip when
+// reading them. A tree is fetched with its subtrees, so the missing trees
+// listed without fetching, usually only the root tree, are enough.
+func fetchHeadTrees(path, remote string) error {
+	// the blob filter leaves only trees among the missing objects
+	cmd := GitCommand(path, "rev-list", "--objects", "--no-object-names", "--no-walk",
+		"--filter=blob:none", "--missing=print", "HEAD")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git rev-list: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	missing := make([]string, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		if id, ok := strings.CutPrefix(line, "?"); ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fetchMissing(path, remote, missing)
+}
+
+// updatePartial fetches a partial clone with system git, as go-git does not
+// support filters, and would fetch the blobs of new commits.
+func updatePartial(path, remote, filter string, progress io.Writer) (*gogit.Repository, error) {
+	err := runGit(path, nil, progress, "fetch", "--prune", "--progress", remote)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(filter, "tree:") {
+		if err := fetchHeadTrees(path, remote); err != nil {
+			return nil, err
+		}
+	}
+	return Open(path)
+}
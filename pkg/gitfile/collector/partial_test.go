@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloblessClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	src := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", src}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@alice.org",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@alice.org")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "uploadpack.allowFilter", "true")
+	git("config", "uploadpack.allowAnySHA1InWant", "true")
+	require.NoError(t, os.WriteFile(filepath.Join(src, "go.mod"), []byte("module a\n"), 0644))
+	git("add", "go.mod")
+	git("commit", "-q", "-m", "init")
+	blob := plumbing.NewHash(git("rev-parse", "HEAD:go.mod")[:40])
+
+	viper.Set("git.clone-mode", CloneModeBlobless)
+	defer viper.Set("git.clone-mode", "")
+
+	u := url.RepoURL{URL: "file://" + src}
+	path := filepath.Join(t.TempDir(), "a")
+	r, err := CloneOutProcess(&u, path, nil)
+	require.NoError(t, err)
+	require.True(t, IsPartial(r))
+	require.Error(t, r.Storer.HasEncodedObject(blob))
+
+	r, err = FetchObjects(r, []plumbing.Hash{blob})
+	require.NoError(t, err)
+	require.NoError(t, r.Storer.HasEncodedObject(blob))
+
+	r, err = Update(&u, path, nil)
+	require.NoError(t, err)
+	require.True(t, IsPartial(r))
+}
+
+func TestTreelessClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	src := t.TempDir()
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@alice.org",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@alice.org")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+	git(src, "init", "-q")
+	git(src, "config", "uploadpack.allowFilter", "true")
+	git(src, "config", "uploadpack.allowAnySHA1InWant", "true")
+	for _, dir := range []string{"a/b/c", "a/d", "e"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(src, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, dir, "go.mod"), []byte("module "+dir+"\n"), 0644))
+	}
+	git(src, "add", ".")
+	git(src, "commit", "-q", "-m", "init")
+
+	viper.Set("git.clone-mode", CloneModeTreeless)
+	defer viper.Set("git.clone-mode", "")
+
+	trace := filepath.Join(t.TempDir(), "trace.json")
+	t.Setenv("GIT_TRACE2_EVENT", trace)
+	u := url.RepoURL{URL: "file://" + src}
+	path := filepath.Join(t.TempDir(), "a")
+	r, err := CloneOutProcess(&u, path, nil)
+	require.NoError(t, err)
+	require.True(t, IsPartial(r))
+
+	// one request for the clone, and one for the trees of HEAD
+	events, err := os.ReadFile(trace)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(events), `"name":"upload-pack"`))
+
+	// every tree of HEAD is fetched, the blobs are not
+	objects := git(path, "rev-list", "--objects", "--no-object-names", "--missing=print", "HEAD^{tree}")
+	trees, missing := 0, 0
+	for _, line := range strings.Fields(objects) {
+		if strings.HasPrefix(line, "?") {
+			missing++
+		} else if strings.TrimSpace(git(path, "cat-file", "-t", line)) == "tree" {
+			trees++
+		}
+	}
+	require.Equal(t, 6, trees)
+	require.Equal(t, 3, missing)
+}
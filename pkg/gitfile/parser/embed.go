@@ -0,0 +1,115 @@
+package parser
+
+// GITMODULES lists the path and the URL of every submodule
+const GITMODULES string = ".gitmodules"
+
+// directories holding copies of other projects, at any depth, e.g.
+// third_party/zlib or src/external/fmt
+var EMBED_DIRS = map[string]bool{
+	"vendor":      true,
+	"vendors":     true,
+	"third_party": true,
+	"third-party": true,
+	"thirdparty":  true,
+	"3rdparty":    true,
+	"external":    true,
+	"externals":   true,
+	"extern":      true,
+	"deps":        true,
+	"contrib":     true,
+}
+
+// files of a vendored directory declaring its version in the first line
+var EMBED_VERSION_FILES = []string{"VERSION", "VERSION.txt", "version.txt", "version"}
+
+// Upstream is a project commonly vendored by C/C++ projects.
+type Upstream struct {
+	GitLink string
+	// header of the project with a #define of its version string, in
+	// addition to EMBED_VERSION_FILES
+	VersionFile string
+}
+
+// KNOWN_UPSTREAMS are the upstreams of vendored directories, by the
+// directory name in lowercase without a version suffix
+var KNOWN_UPSTREAMS = map[string]Upstream{
+	"zlib":              {"https://github.com/madler/zlib", "zlib.h"},
+	"zstd":              {"https://github.com/facebook/zstd", ""},
+	"lz4":               {"https://github.com/lz4/lz4", ""},
+	"brotli":            {"https://github.com/google/brotli", ""},
+	"bzip2":             {"https://gitlab.com/bzip2/bzip2", ""},
+	"xz":                {"https://github.com/tukaani-project/xz", ""},
+	"snappy":            {"https://github.com/google/snappy", ""},
+	"miniz":             {"https://github.com/richgel999/miniz", "miniz.h"},
+	"libpng":            {"https://github.com/pnggroup/libpng", "png.h"},
+	"libjpeg-turbo":     {"https://github.com/libjpeg-turbo/libjpeg-turbo", ""},
+	"giflib":            {"https://github.com/mirrorer/giflib", ""},
+	"libwebp":           {"https://github.com/webmproject/libwebp", ""},
+	"freetype":          {"https://github.com/freetype/freetype", ""},
+	"harfbuzz":          {"https://github.com/harfbuzz/harfbuzz", ""},
+	"stb":               {"https://github.com/nothings/stb", ""},
+	"imgui":             {"https://github.com/ocornut/imgui", "imgui.h"},
+	"glfw":              {"https://github.com/glfw/glfw", ""},
+	"glm":               {"https://github.com/g-truc/glm", ""},
+	"sdl":               {"https://github.com/libsdl-org/SDL", ""},
+	"sdl2":              {"https://github.com/libsdl-org/SDL", ""},
+	"googletest":        {"https://github.com/google/googletest", ""},
+	"gtest":             {"https://github.com/google/googletest", ""},
+	"benchmark":         {"https://github.com/google/benchmark", ""},
+	"catch2":            {"https://github.com/catchorg/Catch2", ""},
+	"doctest":           {"https://github.com/doctest/doctest", ""},
+	"abseil-cpp":        {"https://github.com/abseil/abseil-cpp", ""},
+	"abseil":            {"https://github.com/abseil/abseil-cpp", ""},
+	"fmt":               {"https://github.com/fmtlib/fmt", ""},
+	"spdlog":            {"https://github.com/gabime/spdlog", ""},
+	"nlohmann_json":     {"https://github.com/nlohmann/json", ""},
+	"nlohmann":          {"https://github.com/nlohmann/json", ""},
+	"rapidjson":         {"https://github.com/Tencent/rapidjson", ""},
+	"jsoncpp":           {"https://github.com/open-source-parsers/jsoncpp", ""},
+	"cjson":             {"https://github.com/DaveGamble/cJSON", ""},
+	"yaml-cpp":          {"https://github.com/jbeder/yaml-cpp", ""},
+	"libyaml":           {"https://github.com/yaml/libyaml", ""},
+	"tinyxml2":          {"https://github.com/leethomason/tinyxml2", ""},
+	"pugixml":           {"https://github.com/zeux/pugixml", ""},
+	"expat":             {"https://github.com/libexpat/libexpat", ""},
+	"libexpat":          {"https://github.com/libexpat/libexpat", ""},
+	"libxml2":           {"https://github.com/GNOME/libxml2", ""},
+	"protobuf":          {"https://github.com/protocolbuffers/protobuf", ""},
+	"grpc":              {"https://github.com/grpc/grpc", ""},
+	"flatbuffers":       {"https://github.com/google/flatbuffers", ""},
+	"capnproto":         {"https://github.com/capnproto/capnproto", ""},
+	"pybind11":          {"https://github.com/pybind/pybind11", ""},
+	"eigen":             {"https://gitlab.com/libeigen/eigen", ""},
+	"openssl":           {"https://github.com/openssl/openssl", ""},
+	"boringssl":         {"https://github.com/google/boringssl", ""},
+	"mbedtls":           {"https://github.com/Mbed-TLS/mbedtls", ""},
+	"wolfssl":           {"https://github.com/wolfSSL/wolfssl", ""},
+	"curl":              {"https://github.com/curl/curl", "include/curl/curlver.h"},
+	"libuv":             {"https://github.com/libuv/libuv", ""},
+	"libevent":          {"https://github.com/libevent/libevent", ""},
+	"http-parser":       {"https://github.com/nodejs/http-parser", ""},
+	"llhttp":            {"https://github.com/nodejs/llhttp", ""},
+	"nghttp2":           {"https://github.com/nghttp2/nghttp2", ""},
+	"c-ares":            {"https://github.com/c-ares/c-ares", ""},
+	"sqlite":            {"https://github.com/sqlite/sqlite", "sqlite3.h"},
+	"sqlite3":           {"https://github.com/sqlite/sqlite", "sqlite3.h"},
+	"leveldb":           {"https://github.com/google/leveldb", ""},
+	"lmdb":              {"https://github.com/LMDB/lmdb", ""},
+	"lua":               {"https://github.com/lua/lua", ""},
+	"luajit":            {"https://github.com/LuaJIT/LuaJIT", ""},
+	"pcre2":             {"https://github.com/PCRE2Project/pcre2", ""},
+	"re2":               {"https://github.com/google/re2", ""},
+	"oniguruma":         {"https://github.com/kkos/oniguruma", ""},
+	"double-conversion": {"https://github.com/google/double-conversion", ""},
+	"utf8proc":          {"https://github.com/JuliaStrings/utf8proc", ""},
+	"libffi":            {"https://github.com/libffi/libffi", ""},
+	"jemalloc":          {"https://github.com/jemalloc/jemalloc", ""},
+	"mimalloc":          {"https://github.com/microsoft/mimalloc", ""},
+	"libgit2":           {"https://github.com/libgit2/libgit2", ""},
+	"libssh2":           {"https://github.com/libssh2/libssh2", ""},
+	"cli11":             {"https://github.com/CLIUtils/CLI11", ""},
+	"cxxopts":           {"https://github.com/jarro2783/cxxopts", ""},
+	"tinyformat":        {"https://github.com/c42f/tinyformat", ""},
+	"xxhash":            {"https://github.com/Cyan4973/xxHash", ""},
+	"libsodium":         {"https://github.com/jedisct1/libsodium", ""},
+}
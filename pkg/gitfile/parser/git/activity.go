@@ -0,0 +1,129 @@
+package git
+
+import (
+	"slices"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/samber/lo"
+)
+
+const monthLayout = "2006-01"
+
+// MonthlyActivity is the activity of a repository in a month, by the
+// author time of commits in UTC.
+type MonthlyActivity struct {
+	// first day of the month
+	Month        time.Time
+	Commits      int
+	MergeCommits int
+	// people with commits in the month, and those of them without commits
+	// in earlier months
+	ActiveAuthors   int
+	NewContributors int
+	// organizations of the emails of the active authors
+	ActiveOrgs int
+}
+
+// LogMonth is the commits of a month in LogCheckpoint.
+type LogMonth struct {
+	Commits      int   `json:"commits"`
+	MergeCommits int   `json:"merges"`
+	Authors      []int `json:"authors"`
+}
+
+type monthState struct {
+	commits      int
+	mergeCommits int
+	authors      map[int]bool
+}
+
+func (s *logState) addMonth(author int, authorTime time.Time, merge bool) {
+	key := authorTime.UTC().Format(monthLayout)
+	m, ok := s.months[key]
+	if !ok {
+		m = &monthState{authors: make(map[int]bool)}
+		s.months[key] = m
+	}
+	m.commits++
+	if merge {
+		m.mergeCommits++
+	}
+	m.authors[author] = true
+}
+
+func monthsFromCheckpoint(months map[string]*LogMonth) map[string]*monthState {
+	ret := make(map[string]*monthState, len(months))
+	for key, m := range months {
+		ret[key] = &monthState{
+			commits:      m.Commits,
+			mergeCommits: m.MergeCommits,
+			authors:      lo.SliceToMap(m.Authors, func(a int) (int, bool) { return a, true }),
+		}
+	}
+	return ret
+}
+
+func monthsToCheckpoint(months map[string]*monthState) map[string]*LogMonth {
+	ret := make(map[string]*LogMonth, len(months))
+	for key, m := range months {
+		authors := lo.Keys(m.authors)
+		slices.Sort(authors)
+		ret[key] = &LogMonth{
+			Commits:      m.commits,
+			MergeCommits: m.mergeCommits,
+			Authors:      authors,
+		}
+	}
+	return ret
+}
+
+// activity returns the activity of every month from the first commit to
+// the current month, including months without commits, with the person
+// and the organization of every author.
+func (s *logState) activity(people, orgs []string) []MonthlyActivity {
+	if len(s.months) == 0 {
+		return nil
+	}
+	keys := lo.Keys(s.months)
+	slices.Sort(keys)
+	first, err := time.Parse(monthLayout, keys[0])
+	if err != nil {
+		return nil
+	}
+	last, err := time.Parse(monthLayout, parser.NOW.UTC().Format(monthLayout))
+	if err != nil {
+		return nil
+	}
+	if latest, err := time.Parse(monthLayout, keys[len(keys)-1]); err == nil && latest.After(last) {
+		last = latest
+	}
+
+	seen := make(map[string]bool)
+	ret := make([]MonthlyActivity, 0)
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		a := MonthlyActivity{Month: month}
+		if m, ok := s.months[month.Format(monthLayout)]; ok {
+			active := make(map[string]bool)
+			activeOrgs := make(map[string]bool)
+			for author := range m.authors {
+				active[people[author]] = true
+				if orgs[author] != "" {
+					activeOrgs[orgs[author]] = true
+				}
+			}
+			for person := range active {
+				if !seen[person] {
+					seen[person] = true
+					a.NewContributors++
+				}
+			}
+			a.Commits = m.commits
+			a.MergeCommits = m.mergeCommits
+			a.ActiveAuthors = len(active)
+			a.ActiveOrgs = len(activeOrgs)
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
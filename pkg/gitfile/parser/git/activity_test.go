@@ -0,0 +1,39 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+)
+
+func TestActivity(t *testing.T) {
+	tr := newTestRepo(t)
+	c1 := tr.commit("alice", 90)
+	c2 := tr.commit("bob", 89, c1)
+	f1 := tr.commit("carol", 88, c1)
+	m := tr.commit("alice", 87, c2, f1)
+	c3 := tr.commit("bob", 0, m)
+	tr.setRef("refs/heads/master", c3)
+
+	activity := tr.walk(nil).Activity
+	first := time.Now().AddDate(0, 0, -90).UTC()
+	months := (parser.NOW.UTC().Year()-first.Year())*12 + int(parser.NOW.UTC().Month()-first.Month()) + 1
+	if len(activity) != months {
+		t.Fatalf("Expected %d months, got %d", months, len(activity))
+	}
+
+	total := MonthlyActivity{}
+	for _, a := range activity {
+		total.Commits += a.Commits
+		total.MergeCommits += a.MergeCommits
+		total.NewContributors += a.NewContributors
+	}
+	if total.Commits != 5 || total.MergeCommits != 1 || total.NewContributors != 3 {
+		t.Errorf("Expected 5 commits, 1 merge and 3 contributors, got %+v", total)
+	}
+	last := activity[len(activity)-1]
+	if last.Commits != 1 || last.ActiveAuthors != 1 || last.NewContributors != 0 || last.ActiveOrgs != 1 {
+		t.Errorf("Wrong activity of the current month: %+v", last)
+	}
+}
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// backends of config.GetGitBackend
+const (
+	BackendGoGit = "go-git"
+	BackendCLI   = "cli"
+)
+
+// Backend reads the history and the tree of HEAD of a repository, which
+// WalkLog and WalkRepo analyze.
+type Backend interface {
+	// Refs returns the commit of HEAD and every ref pointing to a commit,
+	// which are the refs the full log is walked from.
+	Refs() (map[string]plumbing.Hash, error)
+	// IsAncestor reports whether commit a is an ancestor of commit b, and
+	// errors if a is missing.
+	IsAncestor(a, b plumbing.Hash) (bool, error)
+	// Log calls fn for every commit reachable from tips but not from
+	// walked, like git rev-list tips ^walked.
+	Log(tips, walked []plumbing.Hash, fn func(*object.Commit)) error
+	// Tree returns the commit of HEAD and the files of its tree, without
+	// submodules.
+	Tree() (plumbing.Hash, []TreeFile, error)
+	// Files calls fn for the files in order, skipping those whose blobs
+	// are missing in partial clones.
+	Files(files []TreeFile, fn func(*object.File)) error
+	// Releases returns the version tags, see GetReleases.
+	Releases() ([]*Release, error)
+	// Tags returns the annotated tags.
+	Tags() ([]*object.Tag, error)
+}
+
+// TreeFile is a file of the tree of HEAD.
+type TreeFile struct {
+	Name string
+	Mode filemode.FileMode
+	Hash plumbing.Hash
+	// -1 if the blob is missing in a partial clone
+	Size int64
+}
+
+// NewBackend returns the backend of config.GetGitBackend.
+func NewBackend(r *git.Repository) (Backend, error) {
+	switch config.GetGitBackend() {
+	case "", BackendGoGit:
+		return NewGoGitBackend(r), nil
+	case BackendCLI:
+		return NewCLIBackend(r)
+	default:
+		return nil, fmt.Errorf("unknown git backend %q", config.GetGitBackend())
+	}
+}
+
+type goGitBackend struct {
+	r *git.Repository
+}
+
+// NewGoGitBackend returns the backend reading the repository with go-git.
+func NewGoGitBackend(r *git.Repository) Backend {
+	return &goGitBackend{r: r}
+}
+
+func (b *goGitBackend) Refs() (map[string]plumbing.Hash, error) {
+	return getCommitRefs(b.r)
+}
+
+func (b *goGitBackend) IsAncestor(a, c plumbing.Hash) (bool, error) {
+	ca, err := b.r.CommitObject(a)
+	if err != nil {
+		return false, err
+	}
+	if a == c {
+		return true, nil
+	}
+	cc, err := b.r.CommitObject(c)
+	if err != nil {
+		return false, err
+	}
+	return ca.IsAncestor(cc)
+}
+
+func (b *goGitBackend) Log(tips, walked []plumbing.Hash, fn func(*object.Commit)) error {
+	return walkNewCommits(b.r, tips, walked, fn)
+}
+
+func (b *goGitBackend) Tree() (plumbing.Hash, []TreeFile, error) {
+	ref, err := b.r.Head()
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+	commit, err := b.r.CommitObject(ref.Hash())
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	files := make([]TreeFile, 0)
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return plumbing.ZeroHash, nil, err
+		}
+		if entry.Mode == filemode.Dir || entry.Mode == filemode.Submodule {
+			continue
+		}
+		f := TreeFile{Name: name, Mode: entry.Mode, Hash: entry.Hash, Size: -1}
+		obj, err := b.r.Storer.EncodedObject(plumbing.BlobObject, entry.Hash)
+		if err == nil {
+			f.Size = obj.Size()
+		} else if !errors.Is(err, plumbing.ErrObjectNotFound) {
+			return plumbing.ZeroHash, nil, err
+		}
+		files = append(files, f)
+	}
+	return commit.Hash, files, nil
+}
+
+func (b *goGitBackend) Files(files []TreeFile, fn func(*object.File)) error {
+	for _, f := range files {
+		blob, err := object.GetBlob(b.r.Storer, f.Hash)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fn(object.NewFile(f.Name, f.Mode, blob))
+	}
+	return nil
+}
+
+func (b *goGitBackend) Releases() ([]*Release, error) {
+	return GetReleases(b.r)
+}
+
+func (b *goGitBackend) Tags() ([]*object.Tag, error) {
+	tags, err := GetTags(b.r)
+	if err != nil {
+		return nil, err
+	}
+	return *tags, nil
+}
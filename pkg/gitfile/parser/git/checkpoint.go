@@ -0,0 +1,372 @@
+package git
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// bumped whenever what WalkLog aggregates changes, so checkpoints of an
+// older version are discarded and the full log is walked again
+const logCheckpointVersion = 3
+
+var errNoCommits = errors.New("no commits found")
+
+// LogCheckpoint is what WalkLog aggregates from the commit log, and the
+// commit every ref pointed to at that time. Walking the log again from a
+// checkpoint only walks the commits added since then.
+type LogCheckpoint struct {
+	Version int `json:"version"`
+	// commit of every ref, including HEAD
+	Refs map[string]string `json:"refs"`
+	// authors of all commits, as in the commits, so that identities are
+	// resolved again with the current mailmap
+	Authors []Author `json:"authors"`
+	// committer time of the first and the last commit
+	FirstCommit   time.Time `json:"first_commit"`
+	LastCommit    time.Time `json:"last_commit"`
+	Commits       int       `json:"commits"`
+	SignedCommits int       `json:"signed_commits"`
+	// commits still in the bus factor window or the last year, as the
+	// metrics of these windows are counted again on every walk
+	Recent []RecentCommit `json:"recent"`
+	// commits of every month, by the month of the author time in UTC
+	Months map[string]*LogMonth `json:"months"`
+}
+
+// RecentCommit is a commit counted by the time windows of WalkLog.
+type RecentCommit struct {
+	// index of the author in Authors, and the times in unix seconds
+	Author     int   `json:"a"`
+	AuthorTime int64 `json:"at"`
+	CommitTime int64 `json:"ct"`
+}
+
+// MarshalBinary encodes the checkpoint as gzipped JSON.
+func (cp *LogCheckpoint) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(cp); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a checkpoint encoded by MarshalBinary.
+func (cp *LogCheckpoint) UnmarshalBinary(data []byte) error {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return json.NewDecoder(r).Decode(cp)
+}
+
+// logState aggregates the commits walked by WalkLog.
+type logState struct {
+	authors       []Author
+	authorIndex   map[Author]int
+	firstCommit   time.Time
+	lastCommit    time.Time
+	commits       int
+	signedCommits int
+	recent        []RecentCommit
+	months        map[string]*monthState
+}
+
+func newLogState() *logState {
+	return &logState{
+		authorIndex: make(map[Author]int),
+		months:      make(map[string]*monthState),
+	}
+}
+
+func (cp *LogCheckpoint) state() *logState {
+	s := newLogState()
+	for _, a := range cp.Authors {
+		s.authorIndex[a] = len(s.authors)
+		s.authors = append(s.authors, a)
+	}
+	s.firstCommit = cp.FirstCommit
+	s.lastCommit = cp.LastCommit
+	s.commits = cp.Commits
+	s.signedCommits = cp.SignedCommits
+	s.recent = append(s.recent, cp.Recent...)
+	s.months = monthsFromCheckpoint(cp.Months)
+	return s
+}
+
+func (s *logState) add(c *object.Commit) {
+	if c.Committer.When.Before(parser.BEGIN_TIME) || c.Committer.When.After(parser.END_TIME) {
+		return
+	}
+
+	author := Author{Name: c.Author.Name, Email: c.Author.Email}
+	idx, ok := s.authorIndex[author]
+	if !ok {
+		idx = len(s.authors)
+		s.authorIndex[author] = idx
+		s.authors = append(s.authors, author)
+	}
+
+	when := c.Committer.When
+	if s.commits == 0 || when.Before(s.firstCommit) {
+		s.firstCommit = when
+	}
+	if s.commits == 0 || when.After(s.lastCommit) {
+		s.lastCommit = when
+	}
+	s.commits++
+	if c.PGPSignature != "" {
+		s.signedCommits++
+	}
+
+	s.recent = append(s.recent, RecentCommit{
+		Author:     idx,
+		AuthorTime: c.Author.When.Unix(),
+		CommitTime: when.Unix(),
+	})
+	s.addMonth(idx, c.Author.When, c.NumParents() > 1)
+}
+
+// apply sets the metrics of the log to the repository, counting authors
+// by the people they are resolved to with the mailmap of the repository.
+func (s *logState) apply(repo *Repo) {
+	people, orgs := resolveIdentities(s.authors, repo.Mailmap)
+
+	// commits per person in the bus factor window and in the last year
+	recentAuthors := make(map[string]int, 0)
+	lastYearAuthors := make(map[string]int, 0)
+	var commitCount float64 = 0
+	for _, c := range s.recent {
+		author := people[c.Author]
+		if c.AuthorTime > parser.BUS_FACTOR_SINCE.Unix() {
+			recentAuthors[author]++
+		}
+		if c.AuthorTime > parser.LAST_YEAR.Unix() {
+			lastYearAuthors[author]++
+		}
+		if c.CommitTime > parser.LAST_YEAR.Unix() {
+			commitCount++
+		}
+	}
+
+	repo.CreatedSince = s.firstCommit
+	repo.UpdatedSince = s.lastCommit
+	repo.ContributorCount = countDistinct(people)
+	repo.OrgCount = countDistinct(orgs)
+	repo.CommitFrequency = commitCount / 52
+	repo.BusFactor, repo.TopAuthorShare = authorConcentration(recentAuthors, parser.BUS_FACTOR_COVERAGE)
+	repo.ActiveMaintainers = countActiveMaintainers(lastYearAuthors, parser.ACTIVE_MAINTAINER_COMMITS)
+	repo.Hygiene.Commits = s.commits
+	repo.Hygiene.SignedCommits = s.signedCommits
+	repo.Activity = s.activity(people, orgs)
+}
+
+// checkpoint returns the checkpoint of the state. Commits which have left
+// all time windows are dropped, as they never come back.
+func (s *logState) checkpoint(refs map[string]plumbing.Hash) *LogCheckpoint {
+	cp := &LogCheckpoint{
+		Version:       logCheckpointVersion,
+		Refs:          make(map[string]string, len(refs)),
+		Authors:       s.authors,
+		FirstCommit:   s.firstCommit,
+		LastCommit:    s.lastCommit,
+		Commits:       s.commits,
+		SignedCommits: s.signedCommits,
+		Recent:        make([]RecentCommit, 0),
+		Months:        monthsToCheckpoint(s.months),
+	}
+	for name, h := range refs {
+		cp.Refs[name] = h.String()
+	}
+	for _, c := range s.recent {
+		if c.AuthorTime > parser.BUS_FACTOR_SINCE.Unix() || c.CommitTime > parser.LAST_YEAR.Unix() {
+			cp.Recent = append(cp.Recent, c)
+		}
+	}
+	return cp
+}
+
+// getCommitRefs returns the commit of HEAD and every ref pointing to a
+// commit, which are the refs the full log is walked from.
+func getCommitRefs(r *git.Repository) (map[string]plumbing.Hash, error) {
+	refs := make(map[string]plumbing.Hash)
+	if head, err := r.Head(); err == nil {
+		refs[plumbing.HEAD.String()] = head.Hash()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	rIter, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	err = rIter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		// annotated tags are not walked, the same as git.LogOptions.All
+		if _, err := r.CommitObject(ref.Hash()); err != nil {
+			return nil
+		}
+		refs[ref.Name().String()] = ref.Hash()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// resumable returns nil if the log can be walked from the checkpoint, that
+// is all refs of the checkpoint still exist and are only fast-forwarded.
+// Otherwise, e.g. after a force-push, commits counted in the checkpoint
+// may not be in the log anymore.
+func (cp *LogCheckpoint) resumable(b Backend, refs map[string]plumbing.Hash) error {
+	if cp.Version != logCheckpointVersion {
+		return fmt.Errorf("checkpoint version %d is outdated", cp.Version)
+	}
+	for name, old := range cp.Refs {
+		h, ok := refs[name]
+		if !ok {
+			return fmt.Errorf("ref %s is deleted", name)
+		}
+		ok, err := b.IsAncestor(plumbing.NewHash(old), h)
+		if err != nil {
+			return fmt.Errorf("commit %s of ref %s: %w", old, name, err)
+		}
+		if !ok {
+			return fmt.Errorf("ref %s is force-pushed", name)
+		}
+	}
+	return nil
+}
+
+// commitQueue is a max heap of commits by committer time.
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int           { return len(q) }
+func (q commitQueue) Less(i, j int) bool { return q[i].Committer.When.After(q[j].Committer.When) }
+func (q commitQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)        { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+const (
+	markQueued = 1 << iota
+	markWalked
+	markPopped
+)
+
+// walkSlop is the number of commits popped after only the ones reachable
+// from walked are left, like SLOP of git rev-list, so that commits with a
+// skewed committer time are still marked before walking stops.
+const walkSlop = 5
+
+// walkNewCommits calls fn for every commit reachable from tips but not
+// from walked, the tips of the last walk, like git rev-list tips ^walked.
+//
+// Commits are popped by committer time and fn is only called once walking
+// stops, since with skewed committer times a popped commit may be found to
+// be reachable from walked later. Parents missing in shallow clones are
+// skipped.
+func walkNewCommits(r *git.Repository, tips, walked []plumbing.Hash, fn func(*object.Commit)) error {
+	var q commitQueue
+	marks := make(map[plumbing.Hash]int)
+	// popped commits not reachable from walked when they are popped
+	var popped []*object.Commit
+	newCount := 0
+
+	var pushHashes func(hashes []plumbing.Hash, isWalked bool) error
+	push := func(c *object.Commit, isWalked bool) error {
+		m := marks[c.Hash]
+		if m == 0 {
+			if isWalked {
+				marks[c.Hash] = markQueued | markWalked
+			} else {
+				marks[c.Hash] = markQueued
+				newCount++
+			}
+			heap.Push(&q, c)
+			return nil
+		}
+		if !isWalked || m&markWalked != 0 {
+			return nil
+		}
+		marks[c.Hash] = m | markWalked
+		if m&markPopped == 0 {
+			newCount--
+			return nil
+		}
+		// the parents are already pushed as new ones
+		return pushHashes(c.ParentHashes, true)
+	}
+	pushHashes = func(hashes []plumbing.Hash, isWalked bool) error {
+		for _, h := range hashes {
+			c, err := r.CommitObject(h)
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := push(c, isWalked); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := pushHashes(walked, true); err != nil {
+		return err
+	}
+	if err := pushHashes(tips, false); err != nil {
+		return err
+	}
+	for slop := walkSlop; q.Len() > 0 && slop > 0; {
+		c := heap.Pop(&q).(*object.Commit)
+		m := marks[c.Hash]
+		marks[c.Hash] = m | markPopped
+		isWalked := m&markWalked != 0
+		if !isWalked {
+			newCount--
+			popped = append(popped, c)
+		}
+		if err := pushHashes(c.ParentHashes, isWalked); err != nil {
+			return err
+		}
+		if !isWalked {
+			continue
+		}
+		// the slop is used up only if no new commit is left and the next
+		// commit is older than this one
+		if newCount > 0 || q.Len() > 0 && !c.Committer.When.After(q[0].Committer.When) {
+			slop = walkSlop
+		} else {
+			slop--
+		}
+	}
+	for _, c := range popped {
+		if marks[c.Hash]&markWalked == 0 {
+			fn(c)
+		}
+	}
+	return nil
+}
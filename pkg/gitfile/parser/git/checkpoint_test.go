@@ -0,0 +1,260 @@
+package git
+
+import (
+	"maps"
+	"os/exec"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type testRepo struct {
+	t    *testing.T
+	r    *git.Repository
+	tree plumbing.Hash
+}
+
+// testRepoFiles are the files of the tree of every commit of a testRepo,
+// so that the backends parse the same manifests and licenses as well.
+var testRepoFiles = map[string]string{
+	"go.mod":       "module github.com/o/r\n\nrequire github.com/x/y v1.0.0\n",
+	"main.go":      "// SPDX-License-Identifier: MIT\npackage main\n",
+	"package.json": `{"name": "r", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}}`,
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	// on the filesystem, so that system git can read it
+	r, err := git.PlainInit(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := &object.Tree{}
+	for _, name := range slices.Sorted(maps.Keys(testRepoFiles)) {
+		blob := r.Storer.NewEncodedObject()
+		blob.SetType(plumbing.BlobObject)
+		w, err := blob.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(testRepoFiles[name])); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+		h, err := r.Storer.SetEncodedObject(blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: h})
+	}
+	obj := r.Storer.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+	h, err := r.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRepo{t: t, r: r, tree: h}
+}
+
+func (tr *testRepo) commit(author string, daysAgo int, parents ...plumbing.Hash) plumbing.Hash {
+	sig := object.Signature{
+		Name:  author,
+		Email: author + "@" + author + ".org",
+		When:  time.Now().AddDate(0, 0, -daysAgo).Truncate(time.Second),
+	}
+	c := &object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      author,
+		TreeHash:     tr.tree,
+		ParentHashes: parents,
+	}
+	obj := tr.r.Storer.NewEncodedObject()
+	if err := c.Encode(obj); err != nil {
+		tr.t.Fatal(err)
+	}
+	h, err := tr.r.Storer.SetEncodedObject(obj)
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	return h
+}
+
+func (tr *testRepo) setRef(name string, h plumbing.Hash) {
+	if err := tr.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), h)); err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// walk walks HEAD and the log with go-git, and with system git if it is
+// installed, which must give the same results.
+func (tr *testRepo) walk(cp *LogCheckpoint) *Repo {
+	walk := func(b Backend) *Repo {
+		repo := NewRepo()
+		repo.Checkpoint = cp
+		if err := repo.WalkRepo(b); err != nil {
+			tr.t.Fatal(err)
+		}
+		if err := repo.WalkLog(b); err != nil {
+			tr.t.Fatal(err)
+		}
+		return &repo
+	}
+	repo := walk(NewGoGitBackend(tr.r))
+
+	if _, err := exec.LookPath("git"); err == nil {
+		b, err := NewCLIBackend(tr.r)
+		if err != nil {
+			tr.t.Fatal(err)
+		}
+		requireSameBackends(tr.t, walk(b), repo)
+	}
+	return repo
+}
+
+// requireSameBackends checks the repositories parsed by two backends are
+// the same. Authors of checkpoints are in the order they are walked.
+func requireSameBackends(t *testing.T, got, want *Repo) {
+	t.Helper()
+	if (got.Checkpoint == nil) != (want.Checkpoint == nil) ||
+		got.Checkpoint != nil && !reflect.DeepEqual(got.Checkpoint.Refs, want.Checkpoint.Refs) {
+		t.Errorf("Wrong checkpoint %+v, want %+v", got.Checkpoint, want.Checkpoint)
+	}
+	g, w := *got, *want
+	g.Checkpoint, w.Checkpoint = nil, nil
+	g.EcoDeps, w.EcoDeps = nil, nil
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("Backends differ: %+v, want %+v", g, w)
+	}
+}
+
+func requireSameLog(t *testing.T, got, want *Repo) {
+	t.Helper()
+	if !got.CreatedSince.Equal(want.CreatedSince) || !got.UpdatedSince.Equal(want.UpdatedSince) {
+		t.Errorf("Wrong time range: %v - %v, want %v - %v", got.CreatedSince, got.UpdatedSince, want.CreatedSince, want.UpdatedSince)
+	}
+	if got.ContributorCount != want.ContributorCount || got.OrgCount != want.OrgCount {
+		t.Errorf("Wrong counts: %d contributors, %d orgs, want %d and %d", got.ContributorCount, got.OrgCount, want.ContributorCount, want.OrgCount)
+	}
+	if got.CommitFrequency != want.CommitFrequency || got.BusFactor != want.BusFactor ||
+		got.TopAuthorShare != want.TopAuthorShare || got.ActiveMaintainers != want.ActiveMaintainers {
+		t.Errorf("Wrong windowed metrics: %v %v %v %v, want %v %v %v %v",
+			got.CommitFrequency, got.BusFactor, got.TopAuthorShare, got.ActiveMaintainers,
+			want.CommitFrequency, want.BusFactor, want.TopAuthorShare, want.ActiveMaintainers)
+	}
+	if got.Hygiene.Commits != want.Hygiene.Commits {
+		t.Errorf("Wrong number of commits: %d, want %d", got.Hygiene.Commits, want.Hygiene.Commits)
+	}
+	if !reflect.DeepEqual(got.Activity, want.Activity) {
+		t.Errorf("Wrong activity: %v, want %v", got.Activity, want.Activity)
+	}
+}
+
+func TestWalkLogCheckpoint(t *testing.T) {
+	tr := newTestRepo(t)
+	c1 := tr.commit("alice", 1100)
+	c2 := tr.commit("bob", 550, c1)
+	c3 := tr.commit("alice", 100, c2)
+	f1 := tr.commit("carol", 200, c2)
+	tr.setRef("refs/heads/master", c3)
+	tr.setRef("refs/heads/feature", f1)
+
+	first := tr.walk(nil)
+	if first.Hygiene.Commits != 4 || first.ContributorCount != 3 {
+		t.Fatalf("Expected 4 commits by 3 authors, got %d by %d", first.Hygiene.Commits, first.ContributorCount)
+	}
+	data, err := first.Checkpoint.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cp LogCheckpoint
+	if err := cp.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(cp.Recent) != 3 {
+		t.Errorf("Expected the commit of 1100 days ago to be dropped, got %d recent commits", len(cp.Recent))
+	}
+
+	// merge the feature branch, and fork an old commit
+	m := tr.commit("alice", 10, c3, f1)
+	c4 := tr.commit("dave", 5, m)
+	s1 := tr.commit("erin", 2, c1)
+	tr.setRef("refs/heads/master", c4)
+	tr.setRef("refs/heads/side", s1)
+	tr.setRef("refs/tags/old", c2)
+
+	if walked := tr.countNewCommits(&cp); walked != 3 {
+		t.Errorf("Expected 3 new commits to be walked, got %d", walked)
+	}
+
+	incremental := tr.walk(&cp)
+	requireSameLog(t, incremental, tr.walk(nil))
+	if incremental.Hygiene.Commits != 7 || incremental.ContributorCount != 5 {
+		t.Errorf("Expected 7 commits by 5 authors, got %d by %d", incremental.Hygiene.Commits, incremental.ContributorCount)
+	}
+
+	// force-push master, the commits only reachable from it are gone
+	x := tr.commit("frank", 1)
+	tr.setRef("refs/heads/master", x)
+	if err := incremental.Checkpoint.resumable(NewGoGitBackend(tr.r), mustRefs(t, tr.r)); err == nil {
+		t.Errorf("Expected the checkpoint not to be resumable after a force-push")
+	}
+	requireSameLog(t, tr.walk(incremental.Checkpoint), tr.walk(nil))
+}
+
+// TestWalkLogCheckpointClockSkew checks commits reachable from the last
+// walk are not walked again if they are committed with a clock ahead of
+// the one of their children.
+func TestWalkLogCheckpointClockSkew(t *testing.T) {
+	tr := newTestRepo(t)
+	c1 := tr.commit("alice", 100)
+	x := tr.commit("bob", 2, c1)
+	c2 := tr.commit("alice", 50, x)
+	tr.setRef("refs/heads/master", c2)
+	first := tr.walk(nil)
+
+	// branch off the skewed commit, which is newer than the tip of master
+	f1 := tr.commit("carol", 1, x)
+	tr.setRef("refs/heads/feature", f1)
+	if walked := tr.countNewCommits(first.Checkpoint); walked != 1 {
+		t.Errorf("Expected 1 new commit to be walked, got %d", walked)
+	}
+
+	incremental := tr.walk(first.Checkpoint)
+	requireSameLog(t, incremental, tr.walk(nil))
+	if incremental.Hygiene.Commits != 4 {
+		t.Errorf("Expected 4 commits, got %d", incremental.Hygiene.Commits)
+	}
+}
+
+// countNewCommits returns the number of commits walked from the current
+// refs after the walk of cp.
+func (tr *testRepo) countNewCommits(cp *LogCheckpoint) int {
+	var tips, old []plumbing.Hash
+	for _, h := range mustRefs(tr.t, tr.r) {
+		tips = append(tips, h)
+	}
+	for _, h := range cp.Refs {
+		old = append(old, plumbing.NewHash(h))
+	}
+	walked := 0
+	if err := walkNewCommits(tr.r, tips, old, func(*object.Commit) { walked++ }); err != nil {
+		tr.t.Fatal(err)
+	}
+	return walked
+}
+
+func mustRefs(t *testing.T, r *git.Repository) map[string]plumbing.Hash {
+	refs, err := getCommitRefs(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return refs
+}
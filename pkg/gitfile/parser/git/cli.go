@@ -0,0 +1,302 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// cliBackend streams the log and the tree from system git, which is much
+// faster than go-git on large repositories. Tags are few, and are read by
+// go-git.
+type cliBackend struct {
+	goGitBackend
+	path    string
+	partial bool
+}
+
+// NewCLIBackend returns the backend reading the repository with system
+// git, the repository must be on the filesystem.
+func NewCLIBackend(r *git.Repository) (Backend, error) {
+	path, err := collector.RepoPath(r)
+	if err != nil {
+		return nil, err
+	}
+	return &cliBackend{
+		goGitBackend: goGitBackend{r: r},
+		path:         path,
+		partial:      collector.IsPartial(r),
+	}, nil
+}
+
+// run runs git, and calls fn with its output while it runs.
+func (b *cliBackend) run(stdin io.Reader, fn func(*bufio.Reader) error, args ...string) error {
+	cmd := collector.GitCommand(b.path, args...)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	err = fn(bufio.NewReaderSize(stdout, 1<<16))
+	if err != nil {
+		// stop git if fn fails before reading all the output
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (b *cliBackend) Refs() (map[string]plumbing.Hash, error) {
+	refs := make(map[string]plumbing.Hash)
+	err := b.run(nil, func(out *bufio.Reader) error {
+		for {
+			line, err := out.ReadString('\n')
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// annotated tags are not walked, the same as git.LogOptions.All
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[1] == "commit" {
+				refs[fields[2]] = plumbing.NewHash(fields[0])
+			}
+		}
+	}, "for-each-ref", "--format=%(objectname) %(objecttype) %(refname)")
+	if err != nil {
+		return nil, err
+	}
+
+	// rev-parse fails on an unborn HEAD
+	err = b.run(nil, func(out *bufio.Reader) error {
+		line, err := out.ReadString('\n')
+		if err == nil {
+			refs[plumbing.HEAD.String()] = plumbing.NewHash(strings.TrimSpace(line))
+		}
+		return nil
+	}, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (b *cliBackend) IsAncestor(a, c plumbing.Hash) (bool, error) {
+	discard := func(*bufio.Reader) error { return nil }
+	if err := b.run(nil, discard, "cat-file", "-e", a.String()+"^{commit}"); err != nil {
+		return false, fmt.Errorf("commit %s: %w", a, plumbing.ErrObjectNotFound)
+	}
+	err := b.run(nil, discard, "merge-base", "--is-ancestor", a.String(), c.String())
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Log streams the raw commits of git log, as the format placeholders of
+// signatures verify them with gpg.
+func (b *cliBackend) Log(tips, walked []plumbing.Hash, fn func(*object.Commit)) error {
+	// git log walks HEAD without revisions
+	if len(tips) == 0 {
+		return nil
+	}
+	var stdin strings.Builder
+	for _, h := range tips {
+		stdin.WriteString(h.String() + "\n")
+	}
+	for _, h := range walked {
+		stdin.WriteString("^" + h.String() + "\n")
+	}
+	return b.run(strings.NewReader(stdin.String()), func(out *bufio.Reader) error {
+		for {
+			raw, err := out.ReadBytes(0)
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if len(raw) > 0 {
+				c, perr := parseRawCommit(bytes.TrimSuffix(raw, []byte{0}))
+				if perr != nil {
+					return perr
+				}
+				fn(c)
+			}
+			if err == io.EOF {
+				return nil
+			}
+		}
+	}, "-c", "log.showSignature=false", "log", "--stdin", "--ignore-missing",
+		"--format=raw", "-z", "--no-color", "--no-decorate")
+}
+
+// parseRawCommit parses a commit of git log --format=raw, which is the
+// commit line, the headers of the commit object, and the message indented
+// by 4 spaces.
+func parseRawCommit(raw []byte) (*object.Commit, error) {
+	header, message, _ := bytes.Cut(raw, []byte("\n\n"))
+	lines := strings.Split(string(header), "\n")
+	hash, ok := strings.CutPrefix(lines[0], "commit ")
+	if !ok {
+		return nil, fmt.Errorf("malformed commit in git log: %q", lines[0])
+	}
+
+	c := &object.Commit{Hash: plumbing.NewHash(strings.Fields(hash)[0])}
+	var signature strings.Builder
+	inSignature := false
+	for _, line := range lines[1:] {
+		// continuation lines of multi-line headers
+		if inSignature && strings.HasPrefix(line, " ") {
+			signature.WriteString(line[1:] + "\n")
+			continue
+		}
+		inSignature = false
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			c.TreeHash = plumbing.NewHash(value)
+		case "parent":
+			c.ParentHashes = append(c.ParentHashes, plumbing.NewHash(value))
+		case "author":
+			c.Author.Decode([]byte(value))
+		case "committer":
+			c.Committer.Decode([]byte(value))
+		case "gpgsig":
+			inSignature = true
+			signature.WriteString(value + "\n")
+		}
+	}
+	c.PGPSignature = signature.String()
+
+	msg := strings.Split(string(message), "\n")
+	for i, line := range msg {
+		msg[i] = strings.TrimPrefix(line, "    ")
+	}
+	c.Message = strings.Join(msg, "\n")
+	return c, nil
+}
+
+// Tree lists the tree with sizes, but in partial clones, where git would
+// fetch every blob for its size.
+func (b *cliBackend) Tree() (plumbing.Hash, []TreeFile, error) {
+	var head plumbing.Hash
+	err := b.run(nil, func(out *bufio.Reader) error {
+		line, err := out.ReadString('\n')
+		head = plumbing.NewHash(strings.TrimSpace(line))
+		return err
+	}, "rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+
+	args := []string{"ls-tree", "-r", "--full-tree", "-z"}
+	if !b.partial {
+		args = append(args, "-l")
+	}
+	files := make([]TreeFile, 0)
+	err = b.run(nil, func(out *bufio.Reader) error {
+		for {
+			entry, err := out.ReadString(0)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// <mode> <type> <object> [<size>]\t<name>
+			info, name, ok := strings.Cut(strings.TrimSuffix(entry, "\x00"), "\t")
+			fields := strings.Fields(info)
+			if !ok || len(fields) < 3 {
+				return fmt.Errorf("malformed entry in git ls-tree: %q", entry)
+			}
+			if fields[1] != "blob" {
+				continue
+			}
+			mode, err := filemode.New(fields[0])
+			if err != nil {
+				return err
+			}
+			f := TreeFile{Name: name, Mode: mode, Hash: plumbing.NewHash(fields[2]), Size: -1}
+			if len(fields) > 3 {
+				if f.Size, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+					return err
+				}
+			}
+			files = append(files, f)
+		}
+	}, append(args, head.String())...)
+	if err != nil {
+		return plumbing.ZeroHash, nil, err
+	}
+	return head, files, nil
+}
+
+// Files streams the blobs from git cat-file --batch, in the order of the
+// hashes written to it.
+func (b *cliBackend) Files(files []TreeFile, fn func(*object.File)) error {
+	if len(files) == 0 {
+		return nil
+	}
+	stdin, w := io.Pipe()
+	go func() {
+		bw := bufio.NewWriter(w)
+		for _, f := range files {
+			bw.WriteString(f.Hash.String() + "\n")
+		}
+		w.CloseWithError(bw.Flush())
+	}()
+	defer stdin.Close()
+
+	return b.run(stdin, func(out *bufio.Reader) error {
+		for _, f := range files {
+			// <oid> blob <size>\n<contents>\n, or <oid> missing\n
+			line, err := out.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 3 || fields[1] != "blob" {
+				continue
+			}
+			size, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				return err
+			}
+			obj := &plumbing.MemoryObject{}
+			obj.SetType(plumbing.BlobObject)
+			if _, err := io.CopyN(obj, out, size); err != nil {
+				return err
+			}
+			if _, err := out.Discard(1); err != nil {
+				return err
+			}
+			blob, err := object.DecodeBlob(obj)
+			if err != nil {
+				return err
+			}
+			fn(object.NewFile(f.Name, f.Mode, blob))
+		}
+		return nil
+	}, "cat-file", "--batch")
+}
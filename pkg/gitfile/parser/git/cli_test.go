@@ -0,0 +1,104 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+)
+
+func TestParseRawCommit(t *testing.T) {
+	raw := "commit 448d5cc217d76fbdc6d0d36d20fe15792bccd18c\n" +
+		"tree aaff74984cccd156a469afa7d9ab10e4777beb24\n" +
+		"parent 36283c251367e31a0f2767584796c849c5eaa917\n" +
+		"parent 1d5b7fc5d4ef4c8a0f4a4b1c6c4e4c5b3b0a7e5f\n" +
+		"author alice <alice@alice.org> 1700000000 +0200\n" +
+		"committer bob <bob@bob.org> 1700000100 +0000\n" +
+		"gpgsig -----BEGIN PGP SIGNATURE-----\n" +
+		" \n" +
+		" -----END PGP SIGNATURE-----\n" +
+		"\n" +
+		"    subject\n" +
+		"    \n" +
+		"    body"
+	c, err := parseRawCommit([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash.String() != "448d5cc217d76fbdc6d0d36d20fe15792bccd18c" || c.NumParents() != 2 {
+		t.Errorf("Wrong commit %s with %d parents", c.Hash, c.NumParents())
+	}
+	if c.Author.Email != "alice@alice.org" || c.Author.When.Unix() != 1700000000 || c.Committer.Name != "bob" {
+		t.Errorf("Wrong signatures %v, %v", c.Author, c.Committer)
+	}
+	if _, offset := c.Author.When.Zone(); offset != 7200 {
+		t.Errorf("Wrong time zone offset %d", offset)
+	}
+	if c.PGPSignature != "-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n" {
+		t.Errorf("Wrong signature %q", c.PGPSignature)
+	}
+	if c.Message != "subject\n\nbody" {
+		t.Errorf("Wrong message %q", c.Message)
+	}
+}
+
+func TestBackendsWalkRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                   "module github.com/o/r\n\nrequire github.com/x/y v1.0.0\n",
+		"main.go":                  "// SPDX-License-Identifier: MIT\npackage main\n",
+		"web/package.json":         `{"name": "web", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}}`,
+		"vendor/x/x.go":            "// SPDX-License-Identifier: GPL-2.0\npackage x\n",
+		"LICENSES/Apache-2.0.txt":  "Apache License\n",
+		".mailmap":                 "Alice <alice@alice.org> <alice@old.org>\n",
+		".github/workflows/ci.yml": "on: push\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=alice", "-c", "user.email=alice@alice.org", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatal(string(out))
+		}
+	}
+
+	r, err := collector.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := NewCLIBackend(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk := func(b Backend) *Repo {
+		repo := NewRepo()
+		if err := repo.WalkRepo(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.WalkLog(b); err != nil {
+			t.Fatal(err)
+		}
+		return &repo
+	}
+	want := walk(NewGoGitBackend(r))
+	requireSameBackends(t, walk(cli), want)
+
+	if want.LicenseExpression != "Apache-2.0 AND MIT" || len(want.Manifests) != 2 || want.Mailmap == nil {
+		t.Errorf("Wrong repository: %q, %d manifests, mailmap %v", want.LicenseExpression, len(want.Manifests), want.Mailmap)
+	}
+}
@@ -0,0 +1,229 @@
+package git
+
+import (
+	"bufio"
+	neturl "net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// kinds of Embed
+const (
+	EmbedSubmodule = "submodule"
+	EmbedVendored  = "vendored"
+)
+
+// Embed is another project whose code is in the repository at HEAD, as a
+// submodule or a vendored copy.
+type Embed struct {
+	Path    string
+	GitLink string
+	Kind    string
+	// version of vendored copies, empty if unknown
+	Version string
+}
+
+var (
+	// zlib-1.2.13, googletest_v1.14.0
+	embedDirVersionRegexp = regexp.MustCompile(`^(.+?)[-_]v?(\d+(?:\.\d+)+[0-9a-z.+-]*)$`)
+	embedVersionRegexp    = regexp.MustCompile(`^v?(\d+(?:\.\d+)*[0-9A-Za-z.+-]*)$`)
+	// #define ZLIB_VERSION "1.3.1"
+	embedDefineRegexp = regexp.MustCompile(`#\s*define\s+\w*VER\w*\s+"v?(\d[^"]*)"`)
+)
+
+// embedDetector finds the submodules and the vendored copies of known
+// upstreams, see WalkRepo.
+type embedDetector struct {
+	// URL of the repository, which relative submodule URLs are relative to
+	repoURL    string
+	submodules []*Embed
+	vendored   map[string]*Embed
+}
+
+func newEmbedDetector(repoURL string) *embedDetector {
+	return &embedDetector{
+		repoURL:  repoURL,
+		vendored: make(map[string]*Embed),
+	}
+}
+
+// upstream returns the known upstream of a vendored directory.
+func upstream(dir string) parser.Upstream {
+	key, _ := splitDirVersion(path.Base(dir))
+	return parser.KNOWN_UPSTREAMS[key]
+}
+
+// vendoredDir returns the vendored directory of a known upstream a file
+// is in, the outermost one if they are nested, and the path of the file
+// in it.
+func vendoredDir(name string) (string, string, bool) {
+	parts := strings.Split(name, "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if !parser.EMBED_DIRS[strings.ToLower(parts[i])] {
+			continue
+		}
+		if key, _ := splitDirVersion(parts[i+1]); parser.KNOWN_UPSTREAMS[key].GitLink != "" {
+			return strings.Join(parts[:i+2], "/"), strings.Join(parts[i+2:], "/"), true
+		}
+	}
+	return "", "", false
+}
+
+// splitDirVersion returns the upstream name of a directory name in
+// lowercase, and the version suffix of the name.
+func splitDirVersion(dir string) (string, string) {
+	dir = strings.ToLower(dir)
+	if m := embedDirVersionRegexp.FindStringSubmatch(dir); m != nil {
+		return m[1], m[2]
+	}
+	return dir, ""
+}
+
+// ParseName records the vendored directory the file is in.
+func (d *embedDetector) ParseName(name string) {
+	dir, _, ok := vendoredDir(name)
+	if !ok {
+		return
+	}
+	if _, ok := d.vendored[dir]; ok {
+		return
+	}
+	_, version := splitDirVersion(path.Base(dir))
+	d.vendored[dir] = &Embed{Path: dir, GitLink: upstream(dir).GitLink, Kind: EmbedVendored, Version: version}
+}
+
+// NeedsContents reports whether Parse reads the contents of the file, that
+// is .gitmodules and the version files of vendored directories.
+func (d *embedDetector) NeedsContents(name string) bool {
+	if name == parser.GITMODULES {
+		return true
+	}
+	dir, file, ok := vendoredDir(name)
+	if !ok {
+		return false
+	}
+	return slices.Contains(parser.EMBED_VERSION_FILES, file) || file == upstream(dir).VersionFile
+}
+
+// Parse parses a file whose contents are read, see NeedsContents.
+func (d *embedDetector) Parse(f *object.File) {
+	if f.Name == parser.GITMODULES {
+		contents, err := f.Contents()
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+		d.parseSubmodules(contents)
+		return
+	}
+
+	dir, file, ok := vendoredDir(f.Name)
+	if !ok {
+		return
+	}
+	e := d.vendored[dir]
+	if e == nil || e.Version != "" {
+		return
+	}
+	r, err := f.Reader()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	defer r.Close()
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if file == upstream(dir).VersionFile {
+			if m := embedDefineRegexp.FindStringSubmatch(line); m != nil {
+				e.Version = m[1]
+				return
+			}
+			continue
+		}
+		// the first line of version files
+		if m := embedVersionRegexp.FindStringSubmatch(line); m != nil {
+			e.Version = m[1]
+		}
+		return
+	}
+}
+
+func (d *embedDetector) parseSubmodules(contents string) {
+	modules := gitconfig.NewModules()
+	if err := modules.Unmarshal([]byte(contents)); err != nil {
+		logger.Errorf("Failed to parse %s: %v", parser.GITMODULES, err)
+		return
+	}
+	for _, m := range modules.Submodules {
+		link := submoduleLink(d.repoURL, m.URL)
+		if link == "" || m.Path == "" {
+			continue
+		}
+		d.submodules = append(d.submodules, &Embed{Path: m.Path, GitLink: link, Kind: EmbedSubmodule})
+	}
+}
+
+// submoduleLink returns the git link of the URL of a submodule, which may
+// be relative to the URL of the repository, or an empty string for local
+// paths.
+func submoduleLink(repoURL, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "./") || strings.HasPrefix(raw, "../") {
+		base := submoduleLink("", repoURL)
+		if base == "" {
+			return ""
+		}
+		u, err := neturl.Parse(base)
+		if err != nil {
+			return ""
+		}
+		u.Path = path.Join(u.Path, raw)
+		raw = u.String()
+	}
+	if link := langcollector.NormalizeGitLink(raw); link != "" {
+		return link
+	}
+
+	// other hosts, keeping the whole path
+	s := strings.TrimPrefix(raw, "git+")
+	if !strings.Contains(s, "://") {
+		// scp-like syntax, git@host:path
+		at := strings.Index(s, "@")
+		colon := strings.Index(s, ":")
+		if at < 0 || colon < at {
+			return ""
+		}
+		s = "ssh://" + s[at+1:colon] + "/" + s[colon+1:]
+	}
+	u, err := neturl.Parse(s)
+	if err != nil || u.Hostname() == "" || !slices.Contains([]string{"https", "http", "git", "ssh"}, u.Scheme) {
+		return ""
+	}
+	p := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if p == "" {
+		return ""
+	}
+	return "https://" + strings.ToLower(u.Hostname()) + "/" + p
+}
+
+// Embeds returns the submodules, and the vendored copies which are not
+// submodules, by path.
+func (d *embedDetector) Embeds() []*Embed {
+	ret := slices.Clone(d.submodules)
+	for dir, e := range d.vendored {
+		if !slices.ContainsFunc(d.submodules, func(s *Embed) bool { return s.Path == dir }) {
+			ret = append(ret, e)
+		}
+	}
+	slices.SortFunc(ret, func(a, b *Embed) int { return strings.Compare(a.Path, b.Path) })
+	return ret
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubmoduleLink(t *testing.T) {
+	repoURL := "https://github.com/o/r.git"
+	tests := map[string]string{
+		"https://github.com/madler/zlib.git":      "https://github.com/madler/zlib",
+		"git@github.com:google/googletest.git":    "https://github.com/google/googletest",
+		"../fmt.git":                              "https://github.com/o/fmt",
+		"https://git.savannah.gnu.org/git/gnulib": "https://git.savannah.gnu.org/git/gnulib",
+		"git://anongit.freedesktop.org/mesa/drm":  "https://anongit.freedesktop.org/mesa/drm",
+		"user@example.org:libs/foo.git":           "https://example.org/libs/foo",
+		"/srv/git/foo":                            "",
+		"file:///srv/git/foo":                     "",
+	}
+	for raw, want := range tests {
+		if got := submoduleLink(repoURL, raw); got != want {
+			t.Errorf("submoduleLink(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestEmbedDetector(t *testing.T) {
+	files := map[string]string{
+		".gitmodules": "[submodule \"googletest\"]\n\tpath = third_party/googletest\n\turl = https://github.com/google/googletest.git\n" +
+			"[submodule \"local\"]\n\tpath = tools\n\turl = /srv/git/tools\n",
+		"third_party/zlib/zlib.h":           "/* zlib.h */\n#define ZLIB_VERSION \"1.3.1\"\n",
+		"third_party/zlib/inflate.c":        "",
+		"src/external/fmt-10.2.1/format.cc": "",
+		"deps/lua/VERSION":                  "5.4.6\n",
+		"deps/lua/lua.c":                    "",
+		"deps/lua/deps/zlib/zlib.h":         "#define ZLIB_VERSION \"1.2.13\"\n",
+		"vendor/github.com/x/y/y.go":        "",
+		"third_party/unknown/x.c":           "",
+		"third_party/README.md":             "",
+	}
+	names := []string{
+		".gitmodules",
+		"deps/lua/VERSION",
+		"deps/lua/deps/zlib/zlib.h",
+		"deps/lua/lua.c",
+		"src/external/fmt-10.2.1/format.cc",
+		"third_party/README.md",
+		"third_party/unknown/x.c",
+		"third_party/zlib/inflate.c",
+		"third_party/zlib/zlib.h",
+		"vendor/github.com/x/y/y.go",
+	}
+
+	d := newEmbedDetector("https://github.com/o/r")
+	needed := make([]string, 0)
+	for _, name := range names {
+		d.ParseName(name)
+		if d.NeedsContents(name) {
+			needed = append(needed, name)
+		}
+	}
+	wantNeeded := []string{".gitmodules", "deps/lua/VERSION", "third_party/zlib/zlib.h"}
+	if !reflect.DeepEqual(needed, wantNeeded) {
+		t.Errorf("Expected the contents of %v, got %v", wantNeeded, needed)
+	}
+	for _, name := range needed {
+		d.Parse(newMemoryFile(t, name, files[name]))
+	}
+
+	want := []Embed{
+		{"deps/lua", "https://github.com/lua/lua", EmbedVendored, "5.4.6"},
+		{"src/external/fmt-10.2.1", "https://github.com/fmtlib/fmt", EmbedVendored, "10.2.1"},
+		{"third_party/googletest", "https://github.com/google/googletest", EmbedSubmodule, ""},
+		{"third_party/zlib", "https://github.com/madler/zlib", EmbedVendored, "1.3.1"},
+	}
+	got := make([]Embed, 0)
+	for _, e := range d.Embeds() {
+		got = append(got, *e)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Embeds() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Hygiene is the security and maintenance setup of a repository, read
+// from the files at HEAD and the commit log.
+type Hygiene struct {
+	SecurityPolicy bool
+	CodeOwners     bool
+	Fuzzing        bool
+	// names of the CI systems configured, e.g. github-actions
+	CISystems []string
+	// names of the dependency update bots configured, e.g. dependabot
+	DependencyBots []string
+	// commits and annotated tags walked, and how many of them are signed
+	Commits       int
+	SignedCommits int
+	Tags          int
+	SignedTags    int
+}
+
+// directories GitHub and GitLab look for community files in
+var communityDirs = []string{"", ".github", "docs", ".gitlab"}
+
+var securityPolicyFiles = []string{"security.md", "security.rst", "security.txt", "security"}
+
+// CI configuration files by the full path or the directory they are in
+var ciFiles = map[string]string{
+	".gitlab-ci.yml":       "gitlab-ci",
+	"Jenkinsfile":          "jenkins",
+	".travis.yml":          "travis",
+	".circleci/config.yml": "circleci",
+	"azure-pipelines.yml":  "azure-pipelines",
+	".drone.yml":           "drone",
+	"appveyor.yml":         "appveyor",
+	".appveyor.yml":        "appveyor",
+	".woodpecker.yml":      "woodpecker",
+	".cirrus.yml":          "cirrus",
+}
+
+var ciDirs = map[string]string{
+	".github/workflows": "github-actions",
+	".woodpecker":       "woodpecker",
+	".buildkite":        "buildkite",
+}
+
+var dependencyBotFiles = map[string]string{
+	".github/dependabot.yml":  "dependabot",
+	".github/dependabot.yaml": "dependabot",
+	"renovate.json":           "renovate",
+	"renovate.json5":          "renovate",
+	".renovaterc":             "renovate",
+	".renovaterc.json":        "renovate",
+	".github/renovate.json":   "renovate",
+	".github/renovate.json5":  "renovate",
+	".gitlab/renovate.json":   "renovate",
+	".pyup.yml":               "pyup",
+}
+
+// directories of fuzz targets, including ClusterFuzzLite and OSS-Fuzz
+// project files
+var fuzzingDirs = []string{"fuzz", "fuzzing", "fuzzers", "fuzztest", ".clusterfuzzlite", "oss-fuzz"}
+
+// parseFile records the signals of a file at HEAD.
+func (h *Hygiene) parseFile(name string) {
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+	lowerBase := strings.ToLower(base)
+
+	if slices.Contains(communityDirs, dir) {
+		if slices.Contains(securityPolicyFiles, lowerBase) {
+			h.SecurityPolicy = true
+		}
+		if base == "CODEOWNERS" {
+			h.CodeOwners = true
+		}
+	}
+
+	if ci, ok := ciFiles[name]; ok {
+		h.addCI(ci)
+	} else if ci, ok := ciDirs[dir]; ok {
+		h.addCI(ci)
+	}
+	if bot, ok := dependencyBotFiles[name]; ok && !slices.Contains(h.DependencyBots, bot) {
+		h.DependencyBots = append(h.DependencyBots, bot)
+	}
+
+	if !h.Fuzzing && isFuzzingFile(dir, lowerBase) {
+		h.Fuzzing = true
+	}
+}
+
+func (h *Hygiene) addCI(ci string) {
+	if !slices.Contains(h.CISystems, ci) {
+		h.CISystems = append(h.CISystems, ci)
+	}
+}
+
+func isFuzzingFile(dir, lowerBase string) bool {
+	for _, d := range strings.Split(strings.ToLower(dir), "/") {
+		if slices.Contains(fuzzingDirs, d) {
+			return true
+		}
+	}
+	return strings.HasPrefix(lowerBase, "fuzz_") ||
+		strings.HasSuffix(lowerBase, "_fuzzer.c") ||
+		strings.HasSuffix(lowerBase, "_fuzzer.cc") ||
+		strings.HasSuffix(lowerBase, "_fuzzer.cpp") ||
+		strings.HasSuffix(lowerBase, "fuzz_test.go")
+}
+
+// SignedCommitRatio returns the fraction of signed commits, and false if
+// no commit is walked.
+func (h *Hygiene) SignedCommitRatio() (float64, bool) {
+	if h.Commits == 0 {
+		return 0, false
+	}
+	return float64(h.SignedCommits) / float64(h.Commits), true
+}
+
+// SignedTagRatio returns the fraction of signed annotated tags, and false
+// if there is no annotated tag.
+func (h *Hygiene) SignedTagRatio() (float64, bool) {
+	if h.Tags == 0 {
+		return 0, false
+	}
+	return float64(h.SignedTags) / float64(h.Tags), true
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHygieneParseFile(t *testing.T) {
+	var h Hygiene
+	for _, name := range []string{
+		"README.md",
+		"src/security.md",
+		".github/SECURITY.md",
+		"docs/CODEOWNERS",
+		".github/workflows/ci.yml",
+		".github/workflows/release.yaml",
+		"Jenkinsfile",
+		"vendor/x/.travis.yml",
+		".github/dependabot.yml",
+		"renovate.json",
+	} {
+		h.parseFile(name)
+	}
+	if !h.SecurityPolicy || !h.CodeOwners {
+		t.Errorf("Expected security policy and CODEOWNERS, got %v and %v", h.SecurityPolicy, h.CodeOwners)
+	}
+	if h.Fuzzing {
+		t.Errorf("Expected no fuzzing")
+	}
+	if want := []string{"github-actions", "jenkins"}; !slices.Equal(h.CISystems, want) {
+		t.Errorf("Expected CI %v, got %v", want, h.CISystems)
+	}
+	if want := []string{"dependabot", "renovate"}; !slices.Equal(h.DependencyBots, want) {
+		t.Errorf("Expected bots %v, got %v", want, h.DependencyBots)
+	}
+
+	for _, name := range []string{"tests/fuzz/parser.c", ".clusterfuzzlite/project.yaml", "src/png_read_fuzzer.cc", "pkg/parse_fuzz_test.go"} {
+		h := Hygiene{}
+		h.parseFile(name)
+		if !h.Fuzzing {
+			t.Errorf("Expected %s to be a fuzzing file", name)
+		}
+	}
+}
+
+func TestHygieneSignedRatio(t *testing.T) {
+	h := Hygiene{Commits: 4, SignedCommits: 1}
+	if r, ok := h.SignedCommitRatio(); !ok || r != 0.25 {
+		t.Errorf("Expected signed commit ratio 0.25, got %v %v", r, ok)
+	}
+	if _, ok := h.SignedTagRatio(); ok {
+		t.Errorf("Expected no signed tag ratio without tags")
+	}
+}
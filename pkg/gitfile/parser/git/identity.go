@@ -0,0 +1,231 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+)
+
+// Author is the name and email of the author of a commit.
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Mailmap maps the names and emails of commits to the canonical ones, see
+// gitmailmap(5).
+type Mailmap struct {
+	// by the lowercase email, and by the lowercase name and email
+	byEmail     map[string]Author
+	byNameEmail map[Author]Author
+}
+
+// ParseMailmap parses the contents of a .mailmap file. Malformed lines
+// are skipped.
+func ParseMailmap(contents string) *Mailmap {
+	m := &Mailmap{
+		byEmail:     make(map[string]Author),
+		byNameEmail: make(map[Author]Author),
+	}
+	for _, line := range strings.Split(contents, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		// Proper Name <proper@email> Commit Name <commit@email>, where
+		// all but one email are optional
+		var names, emails []string
+		for {
+			open := strings.IndexByte(line, '<')
+			if open < 0 {
+				break
+			}
+			end := strings.IndexByte(line[open:], '>')
+			if end < 0 {
+				break
+			}
+			names = append(names, strings.TrimSpace(line[:open]))
+			emails = append(emails, strings.TrimSpace(line[open+1:open+end]))
+			line = line[open+end+1:]
+		}
+
+		switch len(emails) {
+		case 1:
+			if names[0] != "" {
+				m.byEmail[strings.ToLower(emails[0])] = Author{Name: names[0]}
+			}
+		case 2:
+			proper := Author{Name: names[0], Email: emails[0]}
+			if names[1] == "" {
+				m.byEmail[strings.ToLower(emails[1])] = proper
+			} else {
+				m.byNameEmail[Author{strings.ToLower(names[1]), strings.ToLower(emails[1])}] = proper
+			}
+		}
+	}
+	return m
+}
+
+// Resolve returns the canonical name and email of an author.
+func (m *Mailmap) Resolve(a Author) Author {
+	if m == nil {
+		return a
+	}
+	email := strings.ToLower(a.Email)
+	proper, ok := m.byNameEmail[Author{strings.ToLower(a.Name), email}]
+	if !ok {
+		proper, ok = m.byEmail[email]
+	}
+	if !ok {
+		return a
+	}
+	if proper.Name != "" {
+		a.Name = proper.Name
+	}
+	if proper.Email != "" {
+		a.Email = proper.Email
+	}
+	return a
+}
+
+// normalizeEmail returns the email which aliases of the same mailbox
+// share, e.g. without +tags, and GitHub noreply addresses without the user
+// ID.
+func normalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return email
+	}
+	if domain == "users.noreply.github.com" {
+		// 12345+login@users.noreply.github.com
+		if _, login, ok := strings.Cut(local, "+"); ok {
+			local = login
+		}
+	} else if i := strings.IndexByte(local, '+'); i > 0 {
+		local = local[:i]
+	}
+	if domain == "googlemail.com" {
+		domain = "gmail.com"
+	}
+	if domain == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
+
+// normalizeName returns the name authors are clustered by, or an empty
+// string if the name is too common to tell people apart, e.g. a single
+// word like root or a login.
+func normalizeName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '.' || r == ',' || r == '"' || r == '\''
+	})
+	if len(fields) < 2 {
+		return ""
+	}
+	return strings.Join(fields, " ")
+}
+
+// Organization returns the organization of an email, or an empty string
+// if it is an individual, e.g. a freemail or noreply address.
+func Organization(email string) string {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	domain = strings.Trim(domain, ". >")
+	if !ok || !strings.Contains(domain, ".") {
+		return ""
+	}
+	for d := domain; ; {
+		if parser.INDIVIDUAL_EMAIL_DOMAINS[d] {
+			return ""
+		}
+		if org, ok := parser.ORGANIZATION_EMAIL_DOMAINS[d]; ok {
+			return org
+		}
+		_, parent, ok := strings.Cut(d, ".")
+		if !ok || !strings.Contains(parent, ".") {
+			break
+		}
+		d = parent
+	}
+	return registrableDomain(domain)
+}
+
+// second level domains under country code top level domains, e.g. co.uk
+var secondLevelDomains = map[string]bool{
+	"ac": true, "co": true, "com": true, "edu": true, "gov": true, "net": true, "org": true, "or": true, "ne": true,
+}
+
+// registrableDomain returns the domain an organization registers, so that
+// mail.example.com and example.com are the same organization.
+func registrableDomain(domain string) string {
+	labels := strings.Split(domain, ".")
+	n := 2
+	if len(labels) > 2 && len(labels[len(labels)-1]) == 2 && secondLevelDomains[labels[len(labels)-2]] {
+		n = 3
+	}
+	if len(labels) <= n {
+		return domain
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// resolveIdentities clusters authors into people, after mapping them by
+// the mailmap. Authors sharing a normalized email or a normalized name are
+// the same person. It returns the key of the person and the organization
+// of every author, which is empty for individuals.
+func resolveIdentities(authors []Author, mailmap *Mailmap) ([]string, []string) {
+	parent := make([]int, len(authors))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	byKey := make(map[string]int)
+	union := func(i int, key string) {
+		j, ok := byKey[key]
+		if !ok {
+			byKey[key] = i
+			return
+		}
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[ri] = rj
+		}
+	}
+
+	orgs := make([]string, len(authors))
+	for i, a := range authors {
+		a = mailmap.Resolve(a)
+		if email := normalizeEmail(a.Email); email != "" {
+			union(i, "email:"+email)
+		}
+		if name := normalizeName(a.Name); name != "" {
+			union(i, "name:"+name)
+		}
+		orgs[i] = Organization(a.Email)
+	}
+
+	people := make([]string, len(authors))
+	for i := range authors {
+		people[i] = strconv.Itoa(find(i))
+	}
+	return people, orgs
+}
+
+// countDistinct returns the number of distinct non-empty values.
+func countDistinct(values []string) int {
+	set := make(map[string]bool)
+	for _, v := range values {
+		if v != "" {
+			set[v] = true
+		}
+	}
+	return len(set)
+}
@@ -0,0 +1,78 @@
+package git
+
+import "testing"
+
+func TestMailmap(t *testing.T) {
+	m := ParseMailmap(`# comment
+Jane Doe <jane@example.org>
+<jane@example.org> <jane@old.example.org>
+Jane Doe <jane@example.org> jd <JD@laptop.local>  # trailing comment
+malformed <line
+`)
+	tests := []struct {
+		input Author
+		want  Author
+	}{
+		{Author{"jane", "jane@example.org"}, Author{"Jane Doe", "jane@example.org"}},
+		{Author{"Jane D", "jane@old.example.org"}, Author{"Jane D", "jane@example.org"}},
+		{Author{"JD", "jd@laptop.local"}, Author{"Jane Doe", "jane@example.org"}},
+		// the commit name does not match
+		{Author{"root", "jd@laptop.local"}, Author{"root", "jd@laptop.local"}},
+	}
+	for _, tt := range tests {
+		if got := m.Resolve(tt.input); got != tt.want {
+			t.Errorf("Resolve(%v) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestOrganization(t *testing.T) {
+	tests := map[string]string{
+		"a@redhat.com":                    "Red Hat",
+		"a@fedoraproject.org":             "Red Hat",
+		"a@linux.ibm.com":                 "IBM",
+		"a@mail.example.io":               "example.io",
+		"a@cam.ac.uk":                     "cam.ac.uk",
+		"a@gmail.com":                     "",
+		"1234+a@users.noreply.github.com": "",
+		"root@localhost":                  "",
+		"invalid":                         "",
+		"a@dept.university.edu.cn":        "university.edu.cn",
+		"a@noreply.github.com":            "",
+		"49699333+dependabot[bot]@users.noreply.github.com": "",
+	}
+	for email, want := range tests {
+		if got := Organization(email); got != want {
+			t.Errorf("Organization(%s) = %q, want %q", email, got, want)
+		}
+	}
+}
+
+func TestResolveIdentities(t *testing.T) {
+	authors := []Author{
+		{"Jane Doe", "jane@corp.com"},
+		{"Jane Doe", "jane.doe@gmail.com"},
+		{"jane", "janedoe+git@gmail.com"},
+		{"jd", "jd@laptop.local"},
+		{"John Roe", "1234+jroe@users.noreply.github.com"},
+		{"jroe", "jroe@users.noreply.github.com"},
+		// single words are not clustered by name
+		{"root", "root@a.com"},
+		{"root", "root@b.com"},
+	}
+	mailmap := ParseMailmap("Jane Doe <jane@corp.com> <jd@laptop.local>")
+	people, orgs := resolveIdentities(authors, mailmap)
+	if count := countDistinct(people); count != 4 {
+		t.Errorf("Expected 4 people, got %d: %v", count, people)
+	}
+	if people[0] != people[1] || people[1] != people[2] || people[2] != people[3] {
+		t.Errorf("Expected the aliases of Jane to be the same person: %v", people)
+	}
+	if people[4] != people[5] || people[6] == people[7] {
+		t.Errorf("Wrong clusters: %v", people)
+	}
+	// corp.com, a.com and b.com
+	if count := countDistinct(orgs); count != 3 {
+		t.Errorf("Expected 3 organizations, got %d: %v", count, orgs)
+	}
+}
@@ -2,10 +2,27 @@ package git
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"strings"
 
 	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/autotools"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/cmake"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/conan"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/meson"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/vcpkg"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/conda"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/elixir"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/pnpm"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/nodejs/yarn"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/php"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/poetry"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/python/pypi/pipenv"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/ruby/bundler"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/ruby/gem"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/swift"
 	dotnet "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/dornet"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/dornet/nuget"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/go/mod"
@@ -26,14 +43,95 @@ import (
 type LangEcoConfig struct {
 	defaultName    string
 	defaultVersion string
-	eco            map[string]bool
 }
 
 type LangEcoDeps struct {
 	languages    map[string]int64
 	ecosystems   map[string]int64
 	dependencies map[*langeco.Package]*langeco.Dependencies
+	manifests    []*Manifest
 	config       LangEcoConfig
+	// count files instead of bytes, as sizes are unknown in partial clones
+	countFiles bool
+}
+
+// Manifest is a manifest or lock file parsed at HEAD.
+type Manifest struct {
+	Path string
+	// directory of the manifest, which is the directory of the package it
+	// declares, "." at the root
+	Dir string
+	// directory of the workspace the manifest belongs to, as its root or
+	// one of its members, empty if it belongs to none
+	Workspace    string
+	Package      langeco.Package
+	Dependencies langeco.Dependencies
+}
+
+// ManifestParser parses a kind of manifest or lock file into the package
+// it declares and its dependencies.
+type ManifestParser struct {
+	// matched against the base name of the file, or the trailing path if
+	// it has a slash, see path.Match
+	Pattern string
+	// ecosystem of the package and dependencies which do not have one
+	Eco   string
+	Parse func(content string) (*langeco.Package, *langeco.Dependencies, error)
+}
+
+// MANIFEST_PARSERS are tried in order, the first match parses the file.
+var MANIFEST_PARSERS = []ManifestParser{
+	{langeco.PY_SETUP, parser.PYPI, setup.Parse},
+	{langeco.NODEJS_PACKAGE_JSON, parser.NPM, packagejson.Parse},
+	{langeco.GO_MOD, parser.GO, mod.Parse},
+	{langeco.GO_SUM, parser.GO, sum.Parse},
+	{langeco.NPM_PACKAGE_LOCK, parser.NPM, npm.Parse},
+	{langeco.CARGO_TOML, parser.CARGO, cargo.Parse},
+	{langeco.CARGO_LOCK, parser.CARGO, lock.Parse},
+	{langeco.PY_PROJECT, parser.PYPI, pyproject.Parse},
+	{langeco.MAVEN_POM, parser.MAVEN, maven.Parse},
+	{langeco.PY_REQUIREMENTS, parser.PYPI, requirements.Parse},
+	{langeco.DOT_NET, parser.NUGET, dotnet.Parse},
+	{langeco.NUGET_CONFIG, parser.NUGET, nuget.Parse},
+	{langeco.REBAR_CONFIG, parser.REBAR, rebar.Parse},
+	{langeco.REBAR_LOCK, parser.REBAR, rebar.Parse},
+	{langeco.CONAN_LOCK, parser.CONAN, conan.Parse},
+	{langeco.CONDA_META, parser.CONDA, conda.Parse},
+	{langeco.MIX_LOCK, parser.ELIXIR, elixir.Parse},
+	{langeco.COMPOSER_LOCK, parser.COMPOSER, php.Parse},
+	{langeco.SWIFT_RESOLVED, parser.SWIFT, swift.Parse},
+	{langeco.PNPM_LOCK, parser.NPM, pnpm.Parse},
+	{langeco.YARN_LOCK, parser.NPM, yarn.Parse},
+	{langeco.POETRY_LOCK, parser.PYPI, poetry.Parse},
+	{langeco.PIPFILE_LOCK, parser.PYPI, pipenv.Parse},
+	{langeco.GEMFILE_LOCK, parser.GEMS, bundler.Parse},
+	{langeco.GEMSPEC, parser.GEMS, gem.Parse},
+	{langeco.CMAKE_LISTS, parser.NATIVE, cmake.Parse},
+	{langeco.MESON_BUILD, parser.NATIVE, meson.Parse},
+	{langeco.CONFIGURE_AC, parser.NATIVE, autotools.Parse},
+	{langeco.VCPKG_JSON, parser.NATIVE, vcpkg.Parse},
+}
+
+// Match reports whether the file at name is parsed by p.
+func (p *ManifestParser) Match(name string) bool {
+	n := strings.Count(p.Pattern, "/") + 1
+	parts := strings.Split(name, "/")
+	if len(parts) < n {
+		return false
+	}
+	ok, _ := path.Match(p.Pattern, strings.Join(parts[len(parts)-n:], "/"))
+	return ok
+}
+
+// GetManifestParser returns the parser of the file, or nil if the file is
+// not a manifest.
+func GetManifestParser(name string) *ManifestParser {
+	for i := range MANIFEST_PARSERS {
+		if MANIFEST_PARSERS[i].Match(name) {
+			return &MANIFEST_PARSERS[i]
+		}
+	}
+	return nil
 }
 
 func NewLangEcoDeps(r *Repo) LangEcoDeps {
@@ -44,14 +142,36 @@ func NewLangEcoDeps(r *Repo) LangEcoDeps {
 		config: LangEcoConfig{
 			defaultName:    fmt.Sprintf("%s/%s/%s", r.Source, r.Owner, r.Name),
 			defaultVersion: " ",
-			eco:            langeco.SUPPORTED_ECOS,
 		},
 	}
 }
 
+// ParseName counts the language and the ecosystem of a file by its size,
+// or as 1 if sizes are unknown in partial clones.
+func (led *LangEcoDeps) ParseName(name string, size int64) {
+	if led.countFiles {
+		size = 1
+	}
+	led.parseName(name, size)
+}
+
+// NeedsContents reports whether Parse reads the contents of the file.
+func (led *LangEcoDeps) NeedsContents(name string) bool {
+	return GetManifestParser(name) != nil
+}
+
+// Parse parses the dependencies of a manifest.
 func (led *LangEcoDeps) Parse(f *object.File) error {
-	filename := filepath.Base(f.Name)
-	filesize := f.Size
+	//* Get Dependency
+	if p := GetManifestParser(f.Name); p != nil {
+		led.getDependencies(f, p)
+	}
+
+	return nil
+}
+
+func (led *LangEcoDeps) parseName(name string, filesize int64) {
+	filename := filepath.Base(name)
 
 	//* Get language
 	if v, ok := parser.LANGUAGE_FILENAMES[filename]; ok {
@@ -64,84 +184,54 @@ func (led *LangEcoDeps) Parse(f *object.File) error {
 		}
 	}
 
-	//* Get Ecosystem and Dependency
+	//* Get Ecosystem
 	if v, ok := parser.ECOSYSTEM_MAP[filename]; ok {
 		led.ecosystems[v] += filesize
-		if t, ok := led.config.eco[v]; ok && t {
-			led.getDependencies(f)
-		}
 	}
-
-	return nil
 }
 
-func (led *LangEcoDeps) getDependencies(file *object.File) {
-	filename := filepath.Base(file.Name)
-
-	eco, ok := parser.ECOSYSTEM_MAP[filename]
-	if ok {
-		if v, ok := langeco.SUPPORTED_ECOS[eco]; !ok || !v {
-			return
-		}
-	} else {
-		return
-	}
-
+func (led *LangEcoDeps) getDependencies(file *object.File, p *ManifestParser) {
 	content, err := file.Contents()
 	if err != nil {
 		logger.Error(err)
 		return
 	}
 
-	pkg := &langeco.Package{}
-	deps := &langeco.Dependencies{}
-
-	switch filename {
-	case langeco.PY_SETUP:
-		pkg, deps, err = setup.Parse(content) //* pip
-	case langeco.NODEJS_PACKAGE_JSON:
-		pkg, deps, err = packagejson.Parse(content) //* npm
-	case langeco.GO_MOD:
-		pkg, deps, err = mod.Parse(content) //* go
-	case langeco.GO_SUM:
-		pkg, deps, err = sum.Parse(content) //* go
-	case langeco.NPM_PACKAGE_LOCK:
-		pkg, deps, err = npm.Parse(content) //* npm
-	case langeco.CARGO_TOML:
-		pkg, deps, err = cargo.Parse(content) //* cargo
-	case langeco.CARGO_LOCK:
-		pkg, deps, err = lock.Parse(content) //* cargo
-	case langeco.PY_PROJECT:
-		pkg, deps, err = pyproject.Parse(content) //* pip
-	case langeco.MAVEN_POM:
-		pkg, deps, err = maven.Parse(content) //* maven
-	case langeco.PY_REQUIREMENTS:
-		pkg, deps, err = requirements.Parse(content) //* pip
-	case langeco.DOT_NET:
-		pkg, deps, err = dotnet.Parse(content) //* .NET
-	case langeco.NUGET_CONFIG:
-		pkg, deps, err = nuget.Parse(content) //* NuGet
-	case langeco.REBAR_CONFIG:
-		pkg, deps, err = rebar.Parse(content) //* Rebar3
-	case langeco.REBAR_LOCK:
-		pkg, deps, err = rebar.Parse(content) //* Rebar3
-	default:
-		return
-	}
-
+	pkg, deps, err := p.Parse(content)
 	if err != nil {
 		logger.Error(err)
 		return
 	}
 
 	if pkg != nil {
+		if pkg.Eco == "" {
+			pkg.Eco = p.Eco
+		}
+		if deps != nil {
+			for i := range *deps {
+				if (*deps)[i].Eco == "" {
+					(*deps)[i].Eco = p.Eco
+				}
+			}
+		}
+		// packages in subdirectories of monorepos are named apart, or
+		// Merge would collapse them
+		dir := path.Dir(file.Name)
 		if pkg.Name == "" {
 			pkg.Name = led.config.defaultName
+			if dir != "." {
+				pkg.Name += "/" + dir
+			}
 		}
 		if pkg.Version == "" {
 			pkg.Version = led.config.defaultVersion
 		}
 		led.dependencies[pkg] = deps
+		m := &Manifest{Path: file.Name, Dir: dir, Package: *pkg}
+		if deps != nil {
+			m.Dependencies = *deps
+		}
+		led.manifests = append(led.manifests, m)
 		/*
 			if v, ok := langeco.TRUSTED_FILES[eco]; ok && filename == v {
 				led.config.eco[eco] = false
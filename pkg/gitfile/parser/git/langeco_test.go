@@ -6,8 +6,12 @@ import (
 	"testing"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func TestEco(t *testing.T) {
@@ -172,3 +176,55 @@ func TestMerge(t *testing.T) {
 	}
 
 }
+
+func TestGetManifestParser(t *testing.T) {
+	for name, want := range map[string]string{
+		"go.mod":                        langeco.GO_MOD,
+		"web/package.json":              langeco.NODEJS_PACKAGE_JSON,
+		"yarn.lock":                     langeco.YARN_LOCK,
+		"ruby/mygem.gemspec":            langeco.GEMSPEC,
+		"env/conda-meta/numpy-1.0.json": langeco.CONDA_META,
+		"Package.resolved":              langeco.SWIFT_RESOLVED,
+		"numpy-1.0.json":                "",
+		"README.md":                     "",
+	} {
+		p := GetManifestParser(name)
+		switch {
+		case want == "" && p != nil:
+			t.Errorf("Expected no parser for %s, got %s", name, p.Pattern)
+		case want != "" && (p == nil || p.Pattern != want):
+			t.Errorf("Expected parser %s for %s, got %v", want, name, p)
+		}
+	}
+}
+
+func newMemoryFile(t *testing.T, name, content string) *object.File {
+	obj := &plumbing.MemoryObject{}
+	obj.SetType(plumbing.BlobObject)
+	if _, err := obj.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := object.DecodeBlob(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return object.NewFile(name, filemode.Regular, blob)
+}
+
+func TestLangEcoDepsManifests(t *testing.T) {
+	led := NewLangEcoDeps(&Repo{Source: "github.com", Owner: "o", Name: "r"})
+	led.Parse(newMemoryFile(t, "web/yarn.lock", "lodash@^4.17.21:\n  version \"4.17.21\"\n"))
+	led.Parse(newMemoryFile(t, "README.md", "# r\n"))
+
+	if len(led.manifests) != 1 {
+		t.Fatalf("Expected 1 manifest, got %d", len(led.manifests))
+	}
+	m := led.manifests[0]
+	// unnamed packages in subdirectories are named by their directories
+	if m.Path != "web/yarn.lock" || m.Dir != "web" || m.Package.Name != "github.com/o/r/web" || m.Package.Eco != parser.NPM {
+		t.Errorf("Wrong manifest %+v", m)
+	}
+	if len(m.Dependencies) != 1 || m.Dependencies[0].Name != "lodash" || m.Dependencies[0].Version != "4.17.21" {
+		t.Errorf("Wrong dependencies %+v", m.Dependencies)
+	}
+}
@@ -0,0 +1,478 @@
+package git
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/licensecheck"
+)
+
+// SPDX identifiers known to licensecheck, by the identifier in lowercase
+var spdxLicenseIDs = func() map[string]string {
+	ids := make(map[string]string)
+	for _, l := range licensecheck.BuiltinLicenses() {
+		ids[strings.ToLower(l.ID)] = l.ID
+	}
+	return ids
+}()
+
+// LicenseExpression is a normalized SPDX license expression.
+type LicenseExpression struct {
+	Expression string
+	// identifiers of the licenses, without exceptions
+	IDs []string
+	// whether the expression has operators, so it needs parentheses in
+	// others
+	compound bool
+}
+
+// ParseLicenseExpression normalizes an SPDX license expression, or the
+// name of a license, e.g. "Apache License, Version 2.0". It returns false
+// if the expression is malformed or has unknown licenses.
+func ParseLicenseExpression(s string) (*LicenseExpression, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, false
+	}
+	if e, ok := parseLicenseTokens(s); ok {
+		return e, true
+	}
+	if id, ok := normalizeLicenseName(s); ok {
+		return &LicenseExpression{Expression: id, IDs: []string{id}}, true
+	}
+	return nil, false
+}
+
+func parseLicenseTokens(s string) (*LicenseExpression, bool) {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s))
+	e := &LicenseExpression{}
+	var b strings.Builder
+	depth := 0
+	// an identifier is expected, otherwise an operator
+	operand := true
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch op := strings.ToUpper(token); {
+		case token == "(":
+			if !operand {
+				return nil, false
+			}
+			depth++
+			b.WriteString("(")
+		case token == ")":
+			if operand || depth == 0 {
+				return nil, false
+			}
+			depth--
+			b.WriteString(")")
+		case op == "AND" || op == "OR":
+			if operand {
+				return nil, false
+			}
+			operand = true
+			e.compound = true
+			b.WriteString(" " + op + " ")
+		case op == "WITH":
+			// the exception is kept as is
+			if operand || i+1 == len(tokens) {
+				return nil, false
+			}
+			i++
+			b.WriteString(" WITH " + tokens[i])
+		default:
+			if !operand {
+				return nil, false
+			}
+			operand = false
+			id, ok := normalizeLicenseID(token)
+			if !ok {
+				return nil, false
+			}
+			if strings.Contains(id, " ") {
+				// a deprecated identifier replaced by an expression
+				sub, ok := parseLicenseTokens(id)
+				if !ok {
+					return nil, false
+				}
+				e.compound = true
+				e.IDs = append(e.IDs, sub.IDs...)
+				b.WriteString("(" + sub.Expression + ")")
+				continue
+			}
+			e.IDs = append(e.IDs, id)
+			b.WriteString(id)
+		}
+	}
+	if operand || depth != 0 {
+		return nil, false
+	}
+	e.Expression = b.String()
+	slices.Sort(e.IDs)
+	e.IDs = slices.Compact(e.IDs)
+	return e, true
+}
+
+// normalizeLicenseID returns the SPDX identifier of a license identifier,
+// which may be deprecated or have a different case.
+func normalizeLicenseID(id string) (string, bool) {
+	if strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-") {
+		return id, true
+	}
+	plus := strings.HasSuffix(id, "+")
+	base := strings.TrimSuffix(id, "+")
+	known, ok := spdxLicenseIDs[strings.ToLower(base)]
+	if !ok {
+		return normalizeLicenseName(id)
+	}
+	if plus {
+		known += "+"
+	}
+	if replacement, ok := parser.DEPRECATED_LICENSE_IDS[known]; ok {
+		return replacement, true
+	}
+	return known, true
+}
+
+var licenseParenthesesRegexp = regexp.MustCompile(`\([^)]*\)`)
+
+// normalizeLicenseName returns the SPDX identifier of the name of a
+// license, see parser.LICENSE_ALIASES.
+func normalizeLicenseName(name string) (string, bool) {
+	name = licenseParenthesesRegexp.ReplaceAllString(strings.ToLower(name), " ")
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+')
+	})
+	words := make([]string, 0, len(fields))
+	for _, w := range fields {
+		switch {
+		case w == "the" || w == "license" || w == "licence" || w == "version" || w == "v":
+			continue
+		case len(w) > 1 && w[0] == 'v' && w[1] >= '0' && w[1] <= '9':
+			w = w[1:]
+		}
+		words = append(words, w)
+	}
+	id, ok := parser.LICENSE_ALIASES[strings.Join(words, " ")]
+	return id, ok
+}
+
+// CombineLicenses combines the expressions found in a repository into one
+// with AND. Expressions covered by another, e.g. MIT by MIT OR Apache-2.0,
+// are dropped. It returns the expression and its license identifiers.
+func CombineLicenses(exprs []*LicenseExpression) (string, []string) {
+	covers := func(a, b *LicenseExpression) bool {
+		for _, id := range b.IDs {
+			if _, ok := slices.BinarySearch(a.IDs, id); !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	kept := make([]*LicenseExpression, 0, len(exprs))
+	for i, e := range exprs {
+		drop := false
+		for j, other := range exprs {
+			if i == j || !covers(other, e) {
+				continue
+			}
+			// covered by a larger expression, or the same licenses found
+			// earlier
+			if len(other.IDs) > len(e.IDs) || j < i {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			kept = append(kept, e)
+		}
+	}
+
+	parts := make([]string, 0, len(kept))
+	ids := make([]string, 0)
+	for _, e := range kept {
+		if len(kept) > 1 && e.compound {
+			parts = append(parts, "("+e.Expression+")")
+		} else {
+			parts = append(parts, e.Expression)
+		}
+		ids = append(ids, e.IDs...)
+	}
+	slices.Sort(parts)
+	slices.Sort(ids)
+	return strings.Join(parts, " AND "), slices.Compact(ids)
+}
+
+// GetLicense returns the SPDX expression of the licenses in a license
+// file, or an empty string if none is recognized.
+func GetLicense(f *object.File) (string, error) {
+	text, err := f.Contents()
+	if err != nil {
+		return "", err
+	}
+	cov := licensecheck.Scan([]byte(text))
+	exprs := make([]*LicenseExpression, 0, len(cov.Match))
+	for _, m := range cov.Match {
+		if e, ok := ParseLicenseExpression(m.ID); ok {
+			exprs = append(exprs, e)
+		}
+	}
+	if len(exprs) == 0 {
+		return "", nil
+	}
+	license, _ := CombineLicenses(exprs)
+	return license, nil
+}
+
+// licenseDetector collects the licenses of a repository from the license
+// files, the REUSE LICENSES directory, the license fields of manifests and
+// SPDX-License-Identifier headers, see WalkRepo.
+type licenseDetector struct {
+	// by priority, declared licenses first
+	manifests []*LicenseExpression
+	files     []*LicenseExpression
+	headers   []*LicenseExpression
+	seen      map[string]bool
+	// files whose headers are read, see NeedsContents
+	headerFiles map[string]bool
+}
+
+func newLicenseDetector() *licenseDetector {
+	return &licenseDetector{seen: make(map[string]bool), headerFiles: make(map[string]bool)}
+}
+
+// isLicenseFile reports whether a file is a license file at the root.
+func isLicenseFile(name string) bool {
+	if strings.Contains(name, "/") {
+		return false
+	}
+	upper := strings.ToUpper(name)
+	for _, prefix := range parser.LICENSE_FILE_PREFIXES {
+		if rest, ok := strings.CutPrefix(upper, prefix); ok &&
+			(rest == "" || rest[0] == '.' || rest[0] == '-' || rest[0] == '_') {
+			return true
+		}
+	}
+	return false
+}
+
+// isVendored reports whether a file is in a directory of vendored code.
+func isVendored(name string) bool {
+	dirs := strings.Split(path.Dir(name), "/")
+	return slices.ContainsFunc(dirs, func(d string) bool { return parser.VENDORED_DIRS[d] })
+}
+
+func (d *licenseDetector) add(list *[]*LicenseExpression, s string) {
+	e, ok := ParseLicenseExpression(s)
+	if !ok {
+		return
+	}
+	if d.seen[e.Expression] {
+		return
+	}
+	d.seen[e.Expression] = true
+	*list = append(*list, e)
+}
+
+// isHeaderFile reports whether the SPDX-License-Identifier header of the
+// file is read, the first LICENSE_HEADER_FILES source files which are not
+// vendored.
+func (d *licenseDetector) isHeaderFile(name string) bool {
+	if len(d.headerFiles) >= parser.LICENSE_HEADER_FILES || isVendored(name) {
+		return false
+	}
+	_, ok := parser.LANGUAGE_EXTENSIONS[path.Ext(name)]
+	return ok
+}
+
+// NeedsContents reports whether Parse reads the contents of the file. It
+// is called for every file in order before Parse, as it picks the header
+// files.
+func (d *licenseDetector) NeedsContents(name string) bool {
+	dir, base := path.Split(name)
+	switch {
+	case dir == "" && (isLicenseFile(base) || parser.LICENSE_MANIFESTS[base]):
+		return true
+	case dir == parser.LICENSES_DIR+"/":
+		return false
+	case d.isHeaderFile(name):
+		d.headerFiles[name] = true
+		return true
+	}
+	return false
+}
+
+// ParseName parses a file whose contents are not read.
+func (d *licenseDetector) ParseName(name string) {
+	if dir, base := path.Split(name); dir == parser.LICENSES_DIR+"/" {
+		// LICENSES/MIT.txt
+		d.add(&d.files, strings.TrimSuffix(base, path.Ext(base)))
+	}
+}
+
+// Parse parses a file whose contents are read, see NeedsContents.
+func (d *licenseDetector) Parse(f *object.File) {
+	dir, name := path.Split(f.Name)
+	switch {
+	case dir == "" && isLicenseFile(name):
+		license, err := GetLicense(f)
+		if err != nil {
+			logger.Error(err)
+		} else if license != "" {
+			d.add(&d.files, license)
+		}
+	case dir == "" && parser.LICENSE_MANIFESTS[name]:
+		contents, err := f.Contents()
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+		for _, license := range manifestLicenses(name, contents) {
+			d.add(&d.manifests, license)
+		}
+	case d.headerFiles[f.Name]:
+		r, err := f.Reader()
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+		defer r.Close()
+		if license := headerLicense(io.LimitReader(r, int64(parser.LICENSE_HEADER_BYTES))); license != "" {
+			d.add(&d.headers, license)
+		}
+	}
+}
+
+// Expression returns the combined SPDX expression and its license
+// identifiers, or an empty expression if no license is found.
+func (d *licenseDetector) Expression() (string, []string) {
+	exprs := slices.Concat(d.manifests, d.files, d.headers)
+	if len(exprs) == 0 {
+		return "", nil
+	}
+	return CombineLicenses(exprs)
+}
+
+const spdxHeader = "SPDX-License-Identifier:"
+
+// closing comment delimiters after the expression in a header
+var commentEndRegexp = regexp.MustCompile(`\s*(\*/|-->|\*\)|#}|%}|-}|"""|'''|;).*$`)
+
+// headerLicense returns the expression of the first
+// SPDX-License-Identifier header.
+func headerLicense(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		_, license, ok := strings.Cut(scanner.Text(), spdxHeader)
+		if ok {
+			return commentEndRegexp.ReplaceAllString(strings.TrimSpace(license), "")
+		}
+	}
+	return ""
+}
+
+// manifestLicenses returns the licenses declared in a manifest, see
+// parser.LICENSE_MANIFESTS.
+func manifestLicenses(name, contents string) []string {
+	switch name {
+	case "package.json":
+		var pkg struct {
+			License  json.RawMessage   `json:"license"`
+			Licenses []json.RawMessage `json:"licenses"`
+		}
+		if err := json.Unmarshal([]byte(contents), &pkg); err != nil {
+			return nil
+		}
+		// "MIT", or the deprecated {"type": "MIT"}
+		licenses := make([]string, 0)
+		for _, raw := range append([]json.RawMessage{pkg.License}, pkg.Licenses...) {
+			var s string
+			var obj struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(raw, &s) == nil {
+				licenses = append(licenses, s)
+			} else if json.Unmarshal(raw, &obj) == nil {
+				licenses = append(licenses, obj.Type)
+			}
+		}
+		return licenses
+	case "Cargo.toml":
+		var cargo struct {
+			Package struct {
+				License string `toml:"license"`
+			} `toml:"package"`
+		}
+		if _, err := toml.Decode(contents, &cargo); err != nil {
+			return nil
+		}
+		return []string{cargo.Package.License}
+	case "pyproject.toml":
+		var pyproject struct {
+			Project struct {
+				// "MIT", or {text = "MIT"} before PEP 639
+				License     any      `toml:"license"`
+				Classifiers []string `toml:"classifiers"`
+			} `toml:"project"`
+			Tool struct {
+				Poetry struct {
+					License string `toml:"license"`
+				} `toml:"poetry"`
+			} `toml:"tool"`
+		}
+		if _, err := toml.Decode(contents, &pyproject); err != nil {
+			return nil
+		}
+		licenses := []string{pyproject.Tool.Poetry.License}
+		switch license := pyproject.Project.License.(type) {
+		case string:
+			licenses = append(licenses, license)
+		case map[string]any:
+			if text, ok := license["text"].(string); ok {
+				licenses = append(licenses, text)
+			}
+		}
+		for _, c := range pyproject.Project.Classifiers {
+			// License :: OSI Approved :: MIT License
+			if strings.HasPrefix(c, "License ::") {
+				parts := strings.Split(c, "::")
+				licenses = append(licenses, parts[len(parts)-1])
+			}
+		}
+		return licenses
+	case "pom.xml":
+		var pom struct {
+			Licenses []struct {
+				Name string `xml:"name"`
+			} `xml:"licenses>license"`
+		}
+		if err := xml.Unmarshal([]byte(contents), &pom); err != nil {
+			return nil
+		}
+		licenses := make([]string, 0, len(pom.Licenses))
+		for _, l := range pom.Licenses {
+			licenses = append(licenses, l.Name)
+		}
+		return licenses
+	}
+	return nil
+}
+
+// SPDXExpression returns the SPDX expression of the licenses of the
+// repository, or nil if none is found.
+func (repo *Repo) SPDXExpression() *string {
+	if repo.LicenseExpression == "" {
+		return nil
+	}
+	return &repo.LicenseExpression
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLicenseExpression(t *testing.T) {
+	tests := map[string]string{
+		"MIT":                             "MIT",
+		"mit":                             "MIT",
+		"Apache-2.0 OR MIT":               "Apache-2.0 OR MIT",
+		"(mit or apache-2.0)":             "(MIT OR Apache-2.0)",
+		"GPL-2.0+":                        "GPL-2.0-or-later",
+		"GPL-2.0 WITH Linux-syscall-note": "GPL-2.0-only WITH Linux-syscall-note",
+		"GPL-2.0-or-3.0":                  "(GPL-2.0-only OR GPL-3.0-only)",
+		"LicenseRef-Proprietary":          "LicenseRef-Proprietary",
+		"Apache License, Version 2.0":     "Apache-2.0",
+		"The Apache Software License":     "Apache-2.0",
+		"MIT License":                     "MIT",
+		"GNU General Public License v2 or later (GPLv2+)": "GPL-2.0-or-later",
+		"BSD 3-Clause":               "BSD-3-Clause",
+		"MIT AND":                    "",
+		"SEE LICENSE IN LICENSE.txt": "",
+		"Proprietary":                "",
+		"MIT OR OR Apache-2.0":       "",
+	}
+	for input, want := range tests {
+		got := ""
+		if e, ok := ParseLicenseExpression(input); ok {
+			got = e.Expression
+		}
+		if got != want {
+			t.Errorf("ParseLicenseExpression(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCombineLicenses(t *testing.T) {
+	parse := func(exprs ...string) []*LicenseExpression {
+		ret := make([]*LicenseExpression, 0, len(exprs))
+		for _, s := range exprs {
+			e, ok := ParseLicenseExpression(s)
+			if !ok {
+				t.Fatalf("Failed to parse %q", s)
+			}
+			ret = append(ret, e)
+		}
+		return ret
+	}
+	tests := []struct {
+		exprs []string
+		want  string
+		ids   []string
+	}{
+		{[]string{"MIT OR Apache-2.0", "MIT", "Apache-2.0"}, "MIT OR Apache-2.0", []string{"Apache-2.0", "MIT"}},
+		{[]string{"GPL-2.0", "MIT", "GPL-2.0-only"}, "GPL-2.0-only AND MIT", []string{"GPL-2.0-only", "MIT"}},
+		{[]string{"MIT OR Apache-2.0", "BSD-3-Clause"}, "(MIT OR Apache-2.0) AND BSD-3-Clause", []string{"Apache-2.0", "BSD-3-Clause", "MIT"}},
+		{[]string{"Apache-2.0 OR MIT", "MIT OR Apache-2.0"}, "Apache-2.0 OR MIT", []string{"Apache-2.0", "MIT"}},
+	}
+	for _, tt := range tests {
+		got, ids := CombineLicenses(parse(tt.exprs...))
+		if got != tt.want || !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("CombineLicenses(%v) = %q, %v, want %q, %v", tt.exprs, got, ids, tt.want, tt.ids)
+		}
+	}
+}
+
+func TestManifestLicenses(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     []string
+	}{
+		{"package.json", `{"name": "a", "license": "ISC"}`, []string{"ISC"}},
+		{"package.json", `{"licenses": [{"type": "MIT"}, {"type": "GPL-2.0"}]}`, []string{"MIT", "GPL-2.0-only"}},
+		{"Cargo.toml", "[package]\nname = \"a\"\nlicense = \"MIT OR Apache-2.0\"\n", []string{"MIT OR Apache-2.0"}},
+		{"pyproject.toml", "[project]\nlicense = {text = \"BSD-3-Clause\"}\nclassifiers = [\"License :: OSI Approved :: MIT License\"]\n", []string{"BSD-3-Clause", "MIT"}},
+		{"pyproject.toml", "[tool.poetry]\nlicense = \"Apache-2.0\"\n", []string{"Apache-2.0"}},
+		{"pom.xml", `<project><licenses><license><name>Apache License, Version 2.0</name></license></licenses></project>`, []string{"Apache-2.0"}},
+	}
+	for _, tt := range tests {
+		got := make([]string, 0)
+		for _, license := range manifestLicenses(tt.name, tt.contents) {
+			if e, ok := ParseLicenseExpression(license); ok {
+				got = append(got, e.Expression)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("manifestLicenses(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHeaderLicense(t *testing.T) {
+	tests := map[string]string{
+		"// SPDX-License-Identifier: GPL-2.0\n":                    "GPL-2.0",
+		"/* SPDX-License-Identifier: MIT OR Apache-2.0 */\nint x;": "MIT OR Apache-2.0",
+		"#!/bin/sh\n# SPDX-License-Identifier: BSD-3-Clause\n":     "BSD-3-Clause",
+		"<!-- SPDX-License-Identifier: CC0-1.0 -->":                "CC0-1.0",
+		"// Copyright\n": "",
+	}
+	for contents, want := range tests {
+		if got := headerLicense(strings.NewReader(contents)); got != want {
+			t.Errorf("headerLicense(%q) = %q, want %q", contents, got, want)
+		}
+	}
+}
+
+func TestIsLicenseFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"LICENSE":      true,
+		"license.md":   true,
+		"LICENSE-MIT":  true,
+		"LICENCE":      true,
+		"COPYING.LIB":  true,
+		"UNLICENSE":    true,
+		"LICENSES":     false,
+		"licensing.md": false,
+		"docs/LICENSE": false,
+	} {
+		if got := isLicenseFile(name); got != want {
+			t.Errorf("isLicenseFile(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// needsContents reports whether WalkRepo reads the contents of the file.
+// It is called for every file in order, as the license detector picks
+// the header files.
+func needsContents(led *LangEcoDeps, licenses *licenseDetector, embeds *embedDetector, workspaces *workspaceDetector, name string) bool {
+	needed := licenses.NeedsContents(name)
+	return needed || led.NeedsContents(name) || embeds.NeedsContents(name) || workspaces.NeedsContents(name) || name == parser.MAILMAP
+}
+
+// fetchContents fetches the blobs of HEAD read by WalkRepo which are
+// missing in a partial clone, in one request instead of one per file, and
+// returns the reopened repository.
+func fetchContents(r *git.Repository) (*git.Repository, error) {
+	_, files, err := NewGoGitBackend(r).Tree()
+	if err != nil {
+		return r, err
+	}
+
+	led := LangEcoDeps{}
+	licenses := newLicenseDetector()
+	embeds := newEmbedDetector("")
+	workspaces := newWorkspaceDetector()
+	hashes := make([]plumbing.Hash, 0)
+	for _, f := range files {
+		if needsContents(&led, licenses, embeds, workspaces, f.Name) && f.Size < 0 {
+			hashes = append(hashes, f.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return r, nil
+	}
+	return collector.FetchObjects(r, hashes)
+}
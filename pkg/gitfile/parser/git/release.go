@@ -0,0 +1,199 @@
+package git
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const (
+	SchemeSemver = "semver"
+	SchemeCalver = "calver"
+)
+
+// Release is a tag naming a version of the repository.
+type Release struct {
+	Tag     string
+	Version string
+	// SchemeSemver or SchemeCalver
+	Scheme string
+	// tagger time of annotated tags, committer time of the tagged commit
+	// otherwise
+	Time   time.Time
+	Signed bool
+}
+
+// optional prefix, e.g. release-, go or pkg/, the version, and an optional
+// suffix, e.g. -rc1 or +build
+var versionTagRegexp = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9_.-]*?[-_/]?)??[vV]?(\d+(?:[._]\d+){1,3})([-+~._].*)?$`)
+
+// suffixes of pre-releases, which are not counted as releases
+var preReleaseRegexp = regexp.MustCompile(`(?i)(alpha|beta|rc|pre|dev|snapshot|nightly|preview|canary|test)`)
+
+// parseVersionTag returns the version and the versioning scheme of a tag,
+// or false if the tag does not name a release.
+func parseVersionTag(tag string) (string, string, bool) {
+	m := versionTagRegexp.FindStringSubmatch(tag)
+	if m == nil || preReleaseRegexp.MatchString(m[2]) {
+		return "", "", false
+	}
+	version := strings.ReplaceAll(m[1], "_", ".")
+	parts := strings.Split(version, ".")
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", "", false
+	}
+	minor, _ := strconv.Atoi(parts[1])
+
+	// YYYY.MM[.DD] or YY.MM, years of calendar versions are far above
+	// the major versions of semantic versions
+	year := parser.NOW.Year()
+	switch {
+	case len(parts[0]) == 4 && major >= 1990 && major <= year+1:
+		return version, SchemeCalver, true
+	case len(parts[0]) == 8 && major/10000 >= 1990 && major/10000 <= year+1:
+		// YYYYMMDD.N
+		return version, SchemeCalver, true
+	case len(parts[0]) == 2 && len(parts[1]) == 2 && parts[1][0] == '0' && major >= 10 && major <= year%100+1 && minor >= 1:
+		// YY.0M, e.g. 24.04, while 18.12 is as likely a semantic version
+		return version, SchemeCalver, true
+	case len(parts[0]) > 3:
+		// hashes, build numbers and dates of other forms
+		return "", "", false
+	}
+	return version, SchemeSemver, true
+}
+
+// GetReleases returns the releases of the repository, ordered by time.
+// Tags of the same commit are a single release, the earliest one.
+func GetReleases(r *git.Repository) ([]*Release, error) {
+	refs, err := GetTagRefs(r)
+	if err != nil {
+		return nil, err
+	}
+
+	byCommit := make(map[plumbing.Hash]*Release)
+	for _, ref := range *refs {
+		name := ref.Name().Short()
+		version, scheme, ok := parseVersionTag(name)
+		if !ok {
+			continue
+		}
+		release := &Release{Tag: name, Version: version, Scheme: scheme}
+
+		target := ref.Hash()
+		if t, err := r.TagObject(ref.Hash()); err == nil {
+			if t.TargetType != plumbing.CommitObject {
+				continue
+			}
+			target = t.Target
+			release.Time = t.Tagger.When
+			release.Signed = t.PGPSignature != ""
+		} else if c, err := r.CommitObject(ref.Hash()); err == nil {
+			release.Time = c.Committer.When
+		} else {
+			// tags of trees and blobs, or of objects missing in shallow
+			// clones
+			continue
+		}
+
+		if prev, ok := byCommit[target]; ok {
+			prev.Signed = prev.Signed || release.Signed
+			if !release.Time.Before(prev.Time) {
+				continue
+			}
+			release.Signed = prev.Signed
+		}
+		byCommit[target] = release
+	}
+
+	releases := make([]*Release, 0, len(byCommit))
+	for _, release := range byCommit {
+		releases = append(releases, release)
+	}
+	slices.SortFunc(releases, func(a, b *Release) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return releases, nil
+}
+
+// applyReleases sets the release cadence metrics of the repository.
+// Releases on the same day count as one for the intervals, as monorepos
+// tag their packages together.
+func (repo *Repo) applyReleases(releases []*Release) {
+	repo.Releases = releases
+	repo.ReleasesLastYear = 0
+	repo.MedianReleaseInterval = parser.UNKNOWN_FREQUENCY
+	repo.LastRelease = parser.UNKNOWN_TIME
+	repo.SignedReleaseRatio = parser.UNKNOWN_FREQUENCY
+	if len(releases) == 0 {
+		return
+	}
+
+	signed := 0
+	days := make([]time.Time, 0, len(releases))
+	for _, release := range releases {
+		if release.Time.After(parser.LAST_YEAR) {
+			repo.ReleasesLastYear++
+		}
+		if release.Signed {
+			signed++
+		}
+		day := release.Time.UTC().Truncate(24 * time.Hour)
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+
+	intervals := make([]float64, 0, len(days))
+	for i := 1; i < len(days); i++ {
+		intervals = append(intervals, days[i].Sub(days[i-1]).Hours()/24)
+	}
+	// a single interval says nothing about the cadence, so it stays
+	// unknown, see MedianReleaseIntervalDays
+	if len(intervals) >= 2 {
+		repo.MedianReleaseInterval = median(intervals)
+	}
+	repo.LastRelease = releases[len(releases)-1].Time
+	repo.SignedReleaseRatio = float64(signed) / float64(len(releases))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// MedianReleaseIntervalDays returns the median release interval in days,
+// or nil if the repository has fewer than two release intervals.
+func (repo *Repo) MedianReleaseIntervalDays() *float64 {
+	if repo.MedianReleaseInterval == parser.UNKNOWN_FREQUENCY {
+		return nil
+	}
+	return &repo.MedianReleaseInterval
+}
+
+// LastReleaseTime returns the time of the last release, or nil if the
+// repository has no releases.
+func (repo *Repo) LastReleaseTime() *time.Time {
+	if repo.LastRelease.IsZero() {
+		return nil
+	}
+	return &repo.LastRelease
+}
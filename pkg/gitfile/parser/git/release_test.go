@@ -0,0 +1,126 @@
+package git
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParseVersionTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		version string
+		scheme  string
+	}{
+		{"v1.2.3", "1.2.3", SchemeSemver},
+		{"1.2", "1.2", SchemeSemver},
+		{"release-2.0.1", "2.0.1", SchemeSemver},
+		{"go1.21.0", "1.21.0", SchemeSemver},
+		{"pkg/v0.3.0", "0.3.0", SchemeSemver},
+		{"python3-1.2", "1.2", SchemeSemver},
+		{"v1_2_3", "1.2.3", SchemeSemver},
+		{"v1.2.3+build.5", "1.2.3", SchemeSemver},
+		{"18.12.0", "18.12.0", SchemeSemver},
+		{"2024.01.15", "2024.01.15", SchemeCalver},
+		{"v2023.3", "2023.3", SchemeCalver},
+		{"24.04", "24.04", SchemeCalver},
+		{"20240115.1", "20240115.1", SchemeCalver},
+		{"v1.2.3-rc1", "", ""},
+		{"1.0.0-beta.2", "", ""},
+		{"2.0~pre1", "", ""},
+		{"latest", "", ""},
+		{"v1", "", ""},
+		{"12345.6", "", ""},
+	}
+	for _, tt := range tests {
+		version, scheme, ok := parseVersionTag(tt.tag)
+		if ok != (tt.version != "") || version != tt.version || scheme != tt.scheme {
+			t.Errorf("parseVersionTag(%s) = %q, %q, %v, want %q, %q", tt.tag, version, scheme, ok, tt.version, tt.scheme)
+		}
+	}
+}
+
+func (tr *testRepo) tag(name string, target plumbing.Hash, daysAgo int, signed bool) {
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     object.Signature{Name: "alice", Email: "alice@alice.org", When: time.Now().AddDate(0, 0, -daysAgo).Truncate(time.Second)},
+		Message:    name + "\n",
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+	if signed {
+		tag.PGPSignature = "-----BEGIN PGP SIGNATURE-----\n-----END PGP SIGNATURE-----\n"
+	}
+	obj := tr.r.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		tr.t.Fatal(err)
+	}
+	h, err := tr.r.Storer.SetEncodedObject(obj)
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	tr.setRef("refs/tags/"+name, h)
+}
+
+func TestReleases(t *testing.T) {
+	tr := newTestRepo(t)
+	c1 := tr.commit("alice", 500)
+	c2 := tr.commit("alice", 300, c1)
+	c3 := tr.commit("alice", 200, c2)
+	c4 := tr.commit("alice", 100, c3)
+	c5 := tr.commit("alice", 10, c4)
+	tr.setRef("refs/heads/master", c5)
+
+	// lightweight tags date from the commit
+	tr.setRef("refs/tags/v1.0.0", c1)
+	tr.tag("v1.1.0", c2, 300, true)
+	// two tags of the same commit are one release
+	tr.tag("v1.2.0", c3, 200, true)
+	tr.setRef("refs/tags/1.2.0", c3)
+	tr.tag("v2.0.0-rc1", c4, 150, true)
+	tr.tag("v2.0.0", c4, 100, false)
+	tr.setRef("refs/tags/nightly", c5)
+
+	repo := tr.walk(nil)
+	if len(repo.Releases) != 4 {
+		t.Fatalf("Expected 4 releases, got %d", len(repo.Releases))
+	}
+	if repo.ReleasesLastYear != 3 {
+		t.Errorf("Expected 3 releases in the last year, got %d", repo.ReleasesLastYear)
+	}
+	// intervals of 200, 100 and 100 days
+	if math.Abs(repo.MedianReleaseInterval-100) > 1 {
+		t.Errorf("Expected a median release interval of 100 days, got %v", repo.MedianReleaseInterval)
+	}
+	if days := time.Since(repo.LastRelease).Hours() / 24; math.Abs(days-100) > 1 {
+		t.Errorf("Expected the last release 100 days ago, got %v", days)
+	}
+	if repo.SignedReleaseRatio != 0.5 {
+		t.Errorf("Expected half of the releases to be signed, got %v", repo.SignedReleaseRatio)
+	}
+
+	empty := newTestRepo(t)
+	empty.setRef("refs/heads/master", empty.commit("alice", 1))
+	repo = empty.walk(nil)
+	if repo.ReleasesLastYear != 0 || repo.MedianReleaseInterval != 0 || !repo.LastRelease.IsZero() {
+		t.Errorf("Expected no releases, got %d, %v, %v", repo.ReleasesLastYear, repo.MedianReleaseInterval, repo.LastRelease)
+	}
+
+	// a single interval leaves the median unknown
+	two := newTestRepo(t)
+	d1 := two.commit("alice", 300)
+	d2 := two.commit("alice", 100, d1)
+	two.setRef("refs/heads/master", d2)
+	two.tag("v1.0.0", d1, 300, false)
+	two.tag("v1.1.0", d2, 100, false)
+	repo = two.walk(nil)
+	if len(repo.Releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(repo.Releases))
+	}
+	if days := repo.MedianReleaseIntervalDays(); days != nil {
+		t.Errorf("Expected an unknown median release interval, got %v", *days)
+	}
+}
@@ -10,18 +10,20 @@ package git
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/collector"
 	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	url "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/google/licensecheck"
 )
 
 var (
@@ -32,11 +34,13 @@ var (
 )
 
 type Repo struct {
-	Name    string
-	Owner   string
-	Source  string
-	URL     string
-	License []string
+	Name   string
+	Owner  string
+	Source string
+	URL    string
+	// SPDX identifiers of the licenses, and the expression combining them
+	License           []string
+	LicenseExpression string
 	//* is_maintained bool
 	Languages        []string
 	Ecosystems       []string
@@ -46,40 +50,56 @@ type Repo struct {
 	OrgCount         int
 	CommitFrequency  float64
 	EcoDeps          map[*langeco.Package]*langeco.Dependencies
+	// smallest number of authors covering BUS_FACTOR_COVERAGE of the
+	// commits in the last BUS_FACTOR_MONTHS months
+	BusFactor int
+	// fraction of the commits in the last BUS_FACTOR_MONTHS months made by
+	// the top author
+	TopAuthorShare float64
+	// authors with at least ACTIVE_MAINTAINER_COMMITS commits in the last
+	// year
+	ActiveMaintainers int
+	Hygiene           Hygiene
+	// hash of HEAD, and the manifests parsed at it
+	Commit    string
+	Manifests []*Manifest
+	// aggregation state of the commit log, see WalkLog
+	Checkpoint *LogCheckpoint
+	// .mailmap at HEAD, nil if there is none
+	Mailmap *Mailmap
+	// submodules and vendored copies of other projects at HEAD
+	Embeds []*Embed
+	// activity of every month since the first commit
+	Activity []MonthlyActivity
+	// version tags, and the release cadence, see applyReleases
+	Releases              []*Release
+	ReleasesLastYear      int
+	MedianReleaseInterval float64
+	LastRelease           time.Time
+	SignedReleaseRatio    float64
 }
 
 func NewRepo() Repo {
 	return Repo{
-		Name:             parser.UNKNOWN_NAME,
-		Owner:            parser.UNKNOWN_OWNER,
-		Source:           parser.UNKNOWN_SOURCE,
-		URL:              parser.UNKNOWN_URL,
-		License:          nil,
-		Languages:        nil,
-		Ecosystems:       nil,
-		CreatedSince:     parser.UNKNOWN_TIME,
-		UpdatedSince:     parser.UNKNOWN_TIME,
-		ContributorCount: parser.UNKNOWN_COUNT,
-		OrgCount:         parser.UNKNOWN_COUNT,
-		CommitFrequency:  parser.UNKNOWN_FREQUENCY,
+		Name:              parser.UNKNOWN_NAME,
+		Owner:             parser.UNKNOWN_OWNER,
+		Source:            parser.UNKNOWN_SOURCE,
+		URL:               parser.UNKNOWN_URL,
+		License:           nil,
+		Languages:         nil,
+		Ecosystems:        nil,
+		CreatedSince:      parser.UNKNOWN_TIME,
+		UpdatedSince:      parser.UNKNOWN_TIME,
+		ContributorCount:  parser.UNKNOWN_COUNT,
+		OrgCount:          parser.UNKNOWN_COUNT,
+		CommitFrequency:   parser.UNKNOWN_FREQUENCY,
+		BusFactor:         parser.UNKNOWN_COUNT,
+		TopAuthorShare:    parser.UNKNOWN_FREQUENCY,
+		ActiveMaintainers: parser.UNKNOWN_COUNT,
+		LastRelease:       parser.UNKNOWN_TIME,
 	}
 }
 
-func GetLicense(f *object.File) (string, error) {
-	text, err := f.Contents()
-	if err != nil {
-		return "", err
-	}
-	cov := licensecheck.Scan([]byte(text))
-	if len(cov.Match) == 0 {
-		return "", nil
-	}
-
-	license := cov.Match[0].ID
-
-	return license, nil
-}
-
 func getTopNKeys(m map[string]int64) []string {
 	keys := make([]string, 0, len(m))
 
@@ -97,106 +117,141 @@ func getTopNKeys(m map[string]int64) []string {
 	return keys
 }
 
-func (repo *Repo) WalkLog(r *git.Repository) error {
-	cIter, err := r.Log(&git.LogOptions{
-		//* From:  ref.Hash(),
-		All:   true,
-		Since: &parser.BEGIN_TIME,
-		Until: &parser.END_TIME,
-		Order: git.LogOrderCommitterTime,
-	})
-	if err != nil {
-		return err
+// authorConcentration returns the smallest number of authors whose
+// commits cover the given fraction of all commits, and the share of the
+// top author.
+func authorConcentration(commits map[string]int, coverage float64) (int, float64) {
+	counts := make([]int, 0, len(commits))
+	total := 0
+	for _, n := range commits {
+		counts = append(counts, n)
+		total += n
 	}
+	if total == 0 {
+		return 0, 0
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	busFactor := 0
+	covered := 0
+	for _, n := range counts {
+		covered += n
+		busFactor++
+		if float64(covered) >= coverage*float64(total) {
+			break
+		}
+	}
+	return busFactor, float64(counts[0]) / float64(total)
+}
 
-	contributors := make(map[string]int, 0)
-	orgs := make(map[string]int, 0)
-	var commit_count float64 = 0
+// countActiveMaintainers returns the number of authors with at least min
+// commits.
+func countActiveMaintainers(commits map[string]int, min int) int {
+	cnt := 0
+	for _, n := range commits {
+		if n >= min {
+			cnt++
+		}
+	}
+	return cnt
+}
 
-	latest_commit, err := cIter.Next()
+// WalkLog aggregates the commits reachable from HEAD and all refs. If
+// repo.Checkpoint is set by a previous walk, only the commits since then
+// are walked, unless refs are deleted or force-pushed. repo.Checkpoint is
+// then replaced by the checkpoint of this walk.
+func (repo *Repo) WalkLog(b Backend) error {
+	refs, err := b.Refs()
 	if err != nil {
 		return err
 	}
 
-	author := fmt.Sprintf("%s(%s)", latest_commit.Author.Name, latest_commit.Author.Email)
-	e := strings.Split(latest_commit.Author.Email, "@")
-	org := e[len(e)-1]
-
-	repo.UpdatedSince = latest_commit.Committer.When
-	contributors[author]++
-	orgs[org]++
-
-	if latest_commit.Author.When.After(parser.LAST_YEAR) {
-		commit_count++
-	}
-
-	created_since := latest_commit.Committer.When
-
-	err = cIter.ForEach(func(c *object.Commit) error {
-		author := fmt.Sprintf("%s(%s)", c.Author.Name, c.Author.Email)
-		e = strings.Split(c.Author.Email, "@")
-		org := e[len(e)-1]
-
-		//! It made sense that this `if` statement is not necessary but sometimes there are errors
-		if created_since.After(c.Committer.When) {
-			created_since = c.Committer.When
-		}
-		contributors[author]++
-		orgs[org]++
-
-		if created_since.After(parser.LAST_YEAR) {
-			commit_count++
+	state := newLogState()
+	walked := make([]plumbing.Hash, 0)
+	if cp := repo.Checkpoint; cp != nil {
+		if err := cp.resumable(b, refs); err != nil {
+			logger.Infof("Walking the full log of %s: %v", repo.URL, err)
+		} else {
+			state = cp.state()
+			for _, h := range cp.Refs {
+				walked = append(walked, plumbing.NewHash(h))
+			}
 		}
+	}
 
-		return nil
-	})
-
+	err = b.Log(slices.Collect(maps.Values(refs)), walked, state.add)
 	if err != nil {
 		return err
 	}
+	if state.commits == 0 {
+		return errNoCommits
+	}
+	state.apply(repo)
+	repo.Checkpoint = state.checkpoint(refs)
 
-	repo.CreatedSince = created_since
-	repo.ContributorCount = len(contributors)
-	repo.OrgCount = len(orgs)
-	repo.CommitFrequency = commit_count / 52
-
-	return nil
-}
-
-func (repo *Repo) WalkRepo(r *git.Repository) error {
-
-	ref, err := r.Head()
+	releases, err := b.Releases()
 	if err != nil {
-		return err
+		logger.Errorf("Failed to Get Releases for %v", err)
+	} else {
+		repo.applyReleases(releases)
 	}
 
-	commit, err := r.CommitObject(ref.Hash())
+	tags, err := b.Tags()
 	if err != nil {
-		return err
+		logger.Errorf("Failed to Get Tags for %v", err)
+		return nil
+	}
+	for _, t := range tags {
+		repo.Hygiene.Tags++
+		if t.PGPSignature != "" {
+			repo.Hygiene.SignedTags++
+		}
 	}
 
-	tree, err := commit.Tree()
+	return nil
+}
+
+// WalkRepo parses the files of HEAD. Their names are parsed first, and
+// the contents of those read are then streamed in one pass.
+func (repo *Repo) WalkRepo(b Backend) error {
+	head, files, err := b.Tree()
 	if err != nil {
 		return err
 	}
+	repo.Commit = head.String()
 
-	fIter := tree.Files()
 	led := NewLangEcoDeps(repo)
+	// sizes are unknown in partial clones, files are counted instead
+	led.countFiles = slices.ContainsFunc(files, func(f TreeFile) bool { return f.Size < 0 })
+	licenses := newLicenseDetector()
+	embeds := newEmbedDetector(repo.URL)
+	workspaces := newWorkspaceDetector()
+
+	needed := make([]TreeFile, 0)
+	for _, f := range files {
+		repo.Hygiene.parseFile(f.Name)
+		led.ParseName(f.Name, f.Size)
+		embeds.ParseName(f.Name)
+		if needsContents(&led, licenses, embeds, workspaces, f.Name) {
+			needed = append(needed, f)
+		} else {
+			licenses.ParseName(f.Name)
+		}
+	}
 
-	err = fIter.ForEach(func(f *object.File) error {
+	err = b.Files(needed, func(f *object.File) {
 		led.Parse(f)
-		filename := filepath.Base(f.Name)
-		if repo.License == nil {
-			if _, ok := parser.LICENSE_FILENAMES[filename]; ok {
-				license, err := GetLicense(f)
-				if err != nil {
-					logger.Error(err)
-				} else if license != "" {
-					repo.License = []string{license}
-				}
+		if f.Name == parser.MAILMAP {
+			contents, err := f.Contents()
+			if err != nil {
+				logger.Error(err)
+			} else {
+				repo.Mailmap = ParseMailmap(contents)
 			}
 		}
-		return nil
+		licenses.Parse(f)
+		embeds.Parse(f)
+		workspaces.Parse(f)
 	})
 
 	if err != nil {
@@ -206,6 +261,10 @@ func (repo *Repo) WalkRepo(r *git.Repository) error {
 	repo.Languages = getTopNKeys(led.languages)
 	repo.Ecosystems = getTopNKeys(led.ecosystems)
 	repo.EcoDeps = led.dependencies
+	repo.Manifests = led.manifests
+	workspaces.Assign(repo.Manifests)
+	repo.LicenseExpression, repo.License = licenses.Expression()
+	repo.Embeds = embeds.Embeds()
 	led.Merge(repo)
 	return nil
 }
@@ -220,11 +279,16 @@ func (repo *Repo) Show() {
 			"[%v]: %v\n"+
 			"[%v]: %v\n"+
 			"[%v]: %v    [%v]: %v\n"+
-			"[%v]: %v\n",
+			"[%v]: %v\n"+
+			"[%v]: %v    [%v]: %v    [%v]: %v\n"+
+			"[%v]: %v    [%v]: %v    [%v]: %v\n"+
+			"[%v]: %v    [%v]: %v\n"+
+			"[%v]: %v/%v    [%v]: %v/%v\n"+
+			"[%v]: %v    [%v]: %v    [%v]: %v    [%v]: %v\n",
 		"Repository Name", repo.Name,
 		"Source", repo.Source,
 		"Owner", repo.Owner,
-		"License", repo.License,
+		"License", repo.LicenseExpression,
 		"URL", repo.URL,
 		"Languages", repo.Languages,
 		"Ecosystems", repo.Ecosystems,
@@ -233,12 +297,33 @@ func (repo *Repo) Show() {
 		"Contributor Count", repo.ContributorCount,
 		"Organization Count", repo.OrgCount,
 		"Commit Frequency", repo.CommitFrequency,
+		"Bus Factor", repo.BusFactor,
+		"Top Author Share", repo.TopAuthorShare,
+		"Active Maintainers", repo.ActiveMaintainers,
+		"Security Policy", repo.Hygiene.SecurityPolicy,
+		"CODEOWNERS", repo.Hygiene.CodeOwners,
+		"Fuzzing", repo.Hygiene.Fuzzing,
+		"CI", repo.Hygiene.CISystems,
+		"Dependency Bots", repo.Hygiene.DependencyBots,
+		"Signed Commits", repo.Hygiene.SignedCommits, repo.Hygiene.Commits,
+		"Signed Tags", repo.Hygiene.SignedTags, repo.Hygiene.Tags,
+		"Releases Last Year", repo.ReleasesLastYear,
+		"Median Release Interval", repo.MedianReleaseInterval,
+		"Last Release", repo.LastRelease,
+		"Signed Release Ratio", repo.SignedReleaseRatio,
 	)
 }
 
 func ParseRepo(r *git.Repository) (*Repo, error) {
+	return ParseRepoFrom(r, nil)
+}
+
+// ParseRepoFrom parses the repository, and walks the log from the
+// checkpoint of the last parse if it is not nil.
+func ParseRepoFrom(r *git.Repository, cp *LogCheckpoint) (*Repo, error) {
 
 	repo := NewRepo()
+	repo.Checkpoint = cp
 
 	u, err := GetURL(r)
 	if err != nil {
@@ -266,13 +351,26 @@ func ParseRepo(r *git.Repository) (*Repo, error) {
 	repo.Owner = path[len(path)-2]
 	repo.Source = uu.Resource
 
-	err = repo.WalkRepo(r)
+	if collector.IsPartial(r) {
+		r, err = fetchContents(r)
+		if err != nil {
+			logger.Errorf("Failed to Fetch Contents for %v", err)
+			return nil, err
+		}
+	}
+
+	b, err := NewBackend(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.WalkRepo(b)
 	if err != nil {
 		logger.Errorf("Failed to Walk Repo for %v", err)
 		return nil, errWalkRepoFailed
 	}
 
-	err = repo.WalkLog(r)
+	err = repo.WalkLog(b)
 	if err != nil {
 		logger.Errorf("Failed to Walk Log for %v", err)
 		return nil, errWalkLogFailed
@@ -80,3 +80,32 @@ func TestParseLocalRepo(t *testing.T) {
 	}
 
 }
+
+func TestAuthorConcentration(t *testing.T) {
+	tests := []struct {
+		commits   map[string]int
+		busFactor int
+		topShare  float64
+	}{
+		{map[string]int{}, 0, 0},
+		{map[string]int{"a": 10}, 1, 1},
+		{map[string]int{"a": 6, "b": 2, "c": 2}, 1, 0.6},
+		{map[string]int{"a": 4, "b": 3, "c": 2, "d": 1}, 2, 0.4},
+		{map[string]int{"a": 1, "b": 1, "c": 1, "d": 1}, 2, 0.25},
+	}
+	for n, test := range tests {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			busFactor, topShare := authorConcentration(test.commits, 0.5)
+			if busFactor != test.busFactor || topShare != test.topShare {
+				t.Errorf("expected (%d, %v), got (%d, %v)", test.busFactor, test.topShare, busFactor, topShare)
+			}
+		})
+	}
+}
+
+func TestCountActiveMaintainers(t *testing.T) {
+	commits := map[string]int{"a": 5, "b": 3, "c": 2, "d": 1}
+	if n := countActiveMaintainers(commits, 3); n != 2 {
+		t.Errorf("expected 2 active maintainers, got %d", n)
+	}
+}
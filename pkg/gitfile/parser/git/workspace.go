@@ -0,0 +1,218 @@
+package git
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+// files declaring the members of workspaces, by their ecosystems
+var workspaceFiles = map[string]string{
+	"package.json":        parser.NPM,
+	"pnpm-workspace.yaml": parser.NPM,
+	"Cargo.toml":          parser.CARGO,
+	"pom.xml":             parser.MAVEN,
+	"go.work":             parser.GO,
+}
+
+// workspace is a directory whose root manifest declares the other
+// directories of the repository it builds and publishes together, e.g.
+// npm workspaces, Cargo workspaces, Maven modules or go.work.
+type workspace struct {
+	dir string
+	eco string
+	// patterns of the member directories relative to dir, see path.Match
+	members []string
+	exclude []string
+}
+
+// workspaceDetector finds the workspaces of monorepos, see WalkRepo.
+type workspaceDetector struct {
+	workspaces []*workspace
+}
+
+func newWorkspaceDetector() *workspaceDetector {
+	return &workspaceDetector{}
+}
+
+// NeedsContents reports whether Parse reads the contents of the file.
+func (d *workspaceDetector) NeedsContents(name string) bool {
+	_, ok := workspaceFiles[path.Base(name)]
+	return ok
+}
+
+// Parse parses a file whose contents are read, see NeedsContents.
+func (d *workspaceDetector) Parse(f *object.File) {
+	eco, ok := workspaceFiles[path.Base(f.Name)]
+	if !ok {
+		return
+	}
+	contents, err := f.Contents()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	members, exclude, err := parseWorkspace(path.Base(f.Name), contents)
+	if err != nil {
+		logger.Errorf("Failed to parse workspace of %s: %v", f.Name, err)
+		return
+	}
+	if len(members) == 0 {
+		return
+	}
+	d.workspaces = append(d.workspaces, &workspace{
+		dir:     path.Dir(f.Name),
+		eco:     eco,
+		members: members,
+		exclude: exclude,
+	})
+}
+
+// parseWorkspace returns the member patterns of the workspace a file
+// declares, and the patterns excluded from them. Files declaring no
+// workspace return none.
+func parseWorkspace(base, contents string) ([]string, []string, error) {
+	var members, exclude []string
+	switch base {
+	case "package.json":
+		// an array, or an object with packages in yarn v1
+		var v struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if err := json.Unmarshal([]byte(contents), &v); err != nil {
+			return nil, nil, err
+		}
+		if len(v.Workspaces) == 0 {
+			return nil, nil, nil
+		}
+		if err := json.Unmarshal(v.Workspaces, &members); err != nil {
+			var w struct {
+				Packages []string `json:"packages"`
+			}
+			if err := json.Unmarshal(v.Workspaces, &w); err != nil {
+				return nil, nil, err
+			}
+			members = w.Packages
+		}
+	case "pnpm-workspace.yaml":
+		var v struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal([]byte(contents), &v); err != nil {
+			return nil, nil, err
+		}
+		members = v.Packages
+	case "Cargo.toml":
+		var v struct {
+			Workspace struct {
+				Members []string `toml:"members"`
+				Exclude []string `toml:"exclude"`
+			} `toml:"workspace"`
+		}
+		if _, err := toml.Decode(contents, &v); err != nil {
+			return nil, nil, err
+		}
+		members, exclude = v.Workspace.Members, v.Workspace.Exclude
+	case "pom.xml":
+		var v struct {
+			Modules []string `xml:"modules>module"`
+		}
+		if err := xml.Unmarshal([]byte(contents), &v); err != nil {
+			return nil, nil, err
+		}
+		members = v.Modules
+	case "go.work":
+		f, err := modfile.ParseWork(base, []byte(contents), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, u := range f.Use {
+			members = append(members, u.Path)
+		}
+	}
+
+	// npm and pnpm negate patterns to exclude them
+	var ret []string
+	for _, m := range members {
+		if p, ok := strings.CutPrefix(strings.TrimSpace(m), "!"); ok {
+			exclude = append(exclude, p)
+		} else {
+			ret = append(ret, m)
+		}
+	}
+	return ret, exclude, nil
+}
+
+// matchDir reports whether the directory, relative to the workspace,
+// matches one of the patterns. Patterns ending with /** match the
+// directories under them at any depth.
+func matchDir(patterns []string, dir string) bool {
+	for _, p := range patterns {
+		p = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(p), "./"), "/")
+		if p == "" {
+			continue
+		}
+		p = path.Clean(p)
+		if prefix, ok := strings.CutSuffix(p, "/**"); ok {
+			if dir == prefix || strings.HasPrefix(dir, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceOf returns the directory of the workspace of the ecosystem a
+// manifest in the directory belongs to, as its root or as a member, and
+// false if it belongs to none. The innermost workspace is taken if they
+// are nested.
+func (d *workspaceDetector) workspaceOf(eco, dir string) (string, bool) {
+	ret, found := "", false
+	for _, w := range d.workspaces {
+		if w.eco != eco || found && len(w.dir) <= len(ret) {
+			continue
+		}
+		rel, ok := relDir(w.dir, dir)
+		if !ok {
+			continue
+		}
+		if rel == "." || matchDir(w.members, rel) && !matchDir(w.exclude, rel) {
+			ret, found = w.dir, true
+		}
+	}
+	return ret, found
+}
+
+// relDir returns the directory relative to the base directory, and false
+// if it is not under it.
+func relDir(base, dir string) (string, bool) {
+	if base == "." {
+		return dir, true
+	}
+	if dir == base {
+		return ".", true
+	}
+	rel, ok := strings.CutPrefix(dir, base+"/")
+	return rel, ok
+}
+
+// Assign sets the workspaces of the manifests.
+func (d *workspaceDetector) Assign(manifests []*Manifest) {
+	for _, m := range manifests {
+		if w, ok := d.workspaceOf(m.Package.Eco, m.Dir); ok {
+			m.Workspace = w
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"path"
+	"testing"
+
+	parser "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+func TestWorkspaceDetector(t *testing.T) {
+	files := map[string]string{
+		"package.json":            `{"name": "root", "private": true, "workspaces": ["packages/*", "!packages/internal", "tools/**"]}`,
+		"packages/a/package.json": `{"name": "@o/a", "version": "1.0.0"}`,
+		"legacy/package.json":     `{"name": "legacy", "workspaces": {"packages": ["plugins/*"]}}`,
+		"web/pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n",
+		"Cargo.toml":              "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/old\"]\n",
+		"crates/x/Cargo.toml":     "[package]\nname = \"x\"\n",
+		"java/pom.xml":            "<project><modules><module>core</module><module>./api</module></modules></project>",
+		"go.work":                 "go 1.22\n\nuse (\n\t.\n\t./sdk\n)\n",
+	}
+	d := newWorkspaceDetector()
+	for name, contents := range files {
+		if !d.NeedsContents(name) {
+			t.Errorf("Expected the contents of %s", name)
+		}
+		d.Parse(newMemoryFile(t, name, contents))
+	}
+	if d.NeedsContents("README.md") {
+		t.Errorf("Expected no contents of README.md")
+	}
+
+	manifest := func(eco, p string) *Manifest {
+		return &Manifest{Path: p, Dir: path.Dir(p), Package: langeco.Package{Eco: eco}}
+	}
+	tests := []struct {
+		manifest *Manifest
+		want     string
+	}{
+		{manifest(parser.NPM, "package-lock.json"), "."},
+		{manifest(parser.NPM, "docs/package.json"), ""},
+		{manifest(parser.NPM, "packages/a/package.json"), "."},
+		{manifest(parser.NPM, "packages/internal/package.json"), ""},
+		{manifest(parser.NPM, "packages/a/b/package.json"), ""},
+		{manifest(parser.NPM, "tools/x/y/package.json"), "."},
+		{manifest(parser.NPM, "legacy/plugins/p/package.json"), "legacy"},
+		{manifest(parser.NPM, "web/apps/site/package.json"), "web"},
+		{manifest(parser.CARGO, "crates/x/Cargo.toml"), "."},
+		{manifest(parser.CARGO, "crates/old/Cargo.toml"), ""},
+		// members of other ecosystems
+		{manifest(parser.NPM, "crates/x/package.json"), ""},
+		{manifest(parser.MAVEN, "java/core/pom.xml"), "java"},
+		{manifest(parser.MAVEN, "java/api/pom.xml"), "java"},
+		{manifest(parser.MAVEN, "java/pom.xml"), "java"},
+		{manifest(parser.GO, "sdk/go.mod"), "."},
+		{manifest(parser.GO, "examples/go.mod"), ""},
+	}
+	for _, tt := range tests {
+		d.Assign([]*Manifest{tt.manifest})
+		if tt.manifest.Workspace != tt.want {
+			t.Errorf("Workspace of %s = %q, want %q", tt.manifest.Path, tt.manifest.Workspace, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package parser
+
+// MAILMAP is the file mapping the names and emails of commits to the
+// canonical ones, see gitmailmap(5)
+const MAILMAP string = ".mailmap"
+
+// email domains of individuals, which are not organizations. Subdomains
+// are matched as well.
+var INDIVIDUAL_EMAIL_DOMAINS = map[string]bool{
+	// noreply addresses of forges
+	"users.noreply.github.com": true,
+	"noreply.github.com":       true,
+	"users.noreply.gitlab.com": true,
+	"noreply.gitlab.com":       true,
+	"users.noreply.gitee.com":  true,
+	"noreply.codeberg.org":     true,
+	// freemail
+	"gmail.com":             true,
+	"googlemail.com":        true,
+	"outlook.com":           true,
+	"hotmail.com":           true,
+	"hotmail.co.uk":         true,
+	"live.com":              true,
+	"msn.com":               true,
+	"yahoo.com":             true,
+	"yahoo.co.jp":           true,
+	"yahoo.co.uk":           true,
+	"yahoo.fr":              true,
+	"ymail.com":             true,
+	"aol.com":               true,
+	"icloud.com":            true,
+	"me.com":                true,
+	"mac.com":               true,
+	"protonmail.com":        true,
+	"protonmail.ch":         true,
+	"proton.me":             true,
+	"pm.me":                 true,
+	"tutanota.com":          true,
+	"fastmail.com":          true,
+	"fastmail.fm":           true,
+	"zoho.com":              true,
+	"mail.com":              true,
+	"gmx.com":               true,
+	"gmx.de":                true,
+	"gmx.net":               true,
+	"web.de":                true,
+	"posteo.de":             true,
+	"mailbox.org":           true,
+	"free.fr":               true,
+	"orange.fr":             true,
+	"laposte.net":           true,
+	"libero.it":             true,
+	"mail.ru":               true,
+	"yandex.ru":             true,
+	"yandex.com":            true,
+	"qq.com":                true,
+	"foxmail.com":           true,
+	"163.com":               true,
+	"126.com":               true,
+	"yeah.net":              true,
+	"sina.com":              true,
+	"sohu.com":              true,
+	"aliyun.com":            true,
+	"139.com":               true,
+	"naver.com":             true,
+	"hanmail.net":           true,
+	"riseup.net":            true,
+	"disroot.org":           true,
+	"users.sourceforge.net": true,
+	// placeholders of unconfigured git
+	"localhost":             true,
+	"localhost.localdomain": true,
+	"example.com":           true,
+	"example.org":           true,
+	"none":                  true,
+	"(none)":                true,
+}
+
+// curated organizations of email domains, for organizations with several
+// domains. Subdomains are matched as well. Other domains are organizations
+// by themselves.
+var ORGANIZATION_EMAIL_DOMAINS = map[string]string{
+	"redhat.com":        "Red Hat",
+	"fedoraproject.org": "Red Hat",
+	"ibm.com":           "IBM",
+	"intel.com":         "Intel",
+	"google.com":        "Google",
+	"chromium.org":      "Google",
+	"golang.org":        "Google",
+	"android.com":       "Google",
+	"microsoft.com":     "Microsoft",
+	"amazon.com":        "Amazon",
+	"amazon.de":         "Amazon",
+	"amazon.co.uk":      "Amazon",
+	"fb.com":            "Meta",
+	"meta.com":          "Meta",
+	"apple.com":         "Apple",
+	"suse.com":          "SUSE",
+	"suse.de":           "SUSE",
+	"suse.cz":           "SUSE",
+	"opensuse.org":      "SUSE",
+	"canonical.com":     "Canonical",
+	"ubuntu.com":        "Canonical",
+	"oracle.com":        "Oracle",
+	"huawei.com":        "Huawei",
+	"hisilicon.com":     "Huawei",
+	"alibaba-inc.com":   "Alibaba",
+	"alibaba.com":       "Alibaba",
+	"antgroup.com":      "Alibaba",
+	"bytedance.com":     "ByteDance",
+	"tencent.com":       "Tencent",
+	"nvidia.com":        "NVIDIA",
+	"amd.com":           "AMD",
+	"arm.com":           "Arm",
+	"linaro.org":        "Linaro",
+	"samsung.com":       "Samsung",
+	"vmware.com":        "VMware",
+	"broadcom.com":      "Broadcom",
+	"mozilla.com":       "Mozilla",
+	"mozilla.org":       "Mozilla",
+}
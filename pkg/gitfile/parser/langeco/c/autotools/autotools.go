@@ -0,0 +1,123 @@
+package autotools
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/pkgconfig"
+)
+
+var (
+	// macros are found anywhere, as they are often nested in the
+	// arguments of AS_IF or AC_ARG_WITH
+	macroRegexp   = regexp.MustCompile(`\b(AC_INIT|PKG_CHECK_MODULES|PKG_CHECK_MODULES_STATIC|AC_CHECK_LIB|AC_SEARCH_LIBS)\(`)
+	commentRegexp = regexp.MustCompile(`(?m)(^\s*#|\bdnl\b).*$`)
+)
+
+// arguments returns the arguments of a macro call after the opening
+// parenthesis, without their outer quotes.
+func arguments(s string) []string {
+	args := []string{}
+	quote, paren := 0, 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			quote++
+		case ']':
+			quote--
+		case '(':
+			if quote == 0 {
+				paren++
+			}
+		case ',', ')':
+			if quote > 0 {
+				continue
+			}
+			if s[i] == ')' && paren > 0 {
+				paren--
+				continue
+			}
+			if paren > 0 {
+				continue
+			}
+			args = append(args, unquote(s[start:i]))
+			if s[i] == ')' {
+				return args
+			}
+			start = i + 1
+		}
+	}
+	return args
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// Parse parses configure.ac. The package is declared by AC_INIT, and the
+// dependencies are the pkg-config modules of PKG_CHECK_MODULES and the
+// libraries of AC_CHECK_LIB and AC_SEARCH_LIBS, except the libraries of
+// the C library. Arguments with shell variables or m4 macros are skipped.
+func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	content = commentRegexp.ReplaceAllString(content, "")
+	pkg := &langeco.Package{}
+	deps := langeco.Dependencies{}
+	seen := make(map[string]bool)
+	add := func(dep langeco.Package) {
+		if !seen[dep.Name] {
+			seen[dep.Name] = true
+			deps = append(deps, dep)
+		}
+	}
+	addLib := func(lib string) {
+		if lib != "" && !parser.NATIVE_SYSTEM_LIBS[lib] && !strings.ContainsAny(lib, "$()[]") {
+			add(langeco.Package{Name: parser.NativeName(parser.NATIVE_LIB, lib), Eco: parser.NATIVE})
+		}
+	}
+	literal := func(s string) bool {
+		return !strings.ContainsAny(s, "$()[]") && !strings.Contains(s, "m4_")
+	}
+
+	for _, m := range macroRegexp.FindAllStringSubmatchIndex(content, -1) {
+		macro := content[m[2]:m[3]]
+		args := arguments(content[m[1]:])
+		if len(args) == 0 {
+			continue
+		}
+		switch macro {
+		case "AC_INIT":
+			if pkg.Name == "" && literal(args[0]) {
+				pkg.Name = args[0]
+				if len(args) > 1 && literal(args[1]) {
+					pkg.Version = args[1]
+				}
+			}
+		case "PKG_CHECK_MODULES", "PKG_CHECK_MODULES_STATIC":
+			if len(args) > 1 && literal(args[1]) {
+				for _, dep := range pkgconfig.ParseModules(args[1]) {
+					add(dep)
+				}
+			}
+		case "AC_CHECK_LIB":
+			addLib(args[0])
+		case "AC_SEARCH_LIBS":
+			if len(args) > 1 {
+				for _, lib := range strings.Fields(args[1]) {
+					addLib(lib)
+				}
+			}
+		}
+	}
+
+	if pkg.Name == "" && len(deps) == 0 {
+		return nil, nil, nil
+	}
+	return pkg, &deps, nil
+}
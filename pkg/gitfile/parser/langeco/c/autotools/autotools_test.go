@@ -0,0 +1,40 @@
+package autotools
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`AC_INIT([myapp], [2.1.0], [bugs@myapp.org])
+AC_PREREQ([2.69])
+dnl PKG_CHECK_MODULES([COMMENTED], [commented])
+# AC_CHECK_LIB([commented], [main])
+PKG_CHECK_MODULES([GLIB], [glib-2.0 >= 2.50 gio-2.0])
+AC_ARG_WITH([xml], [AS_HELP_STRING([--with-xml], [use libxml2])])
+AS_IF([test "x$with_xml" != xno], [
+  PKG_CHECK_MODULES([XML], [libxml-2.0], [], [AC_MSG_ERROR([libxml2 not found])])
+])
+PKG_CHECK_MODULES([EXTRA], [$EXTRA_MODULES])
+AC_CHECK_LIB([z], [inflate], [], [AC_MSG_ERROR([zlib not found])])
+AC_CHECK_LIB(m, sqrt)
+AC_SEARCH_LIBS([dlopen], [dl dld])
+AC_OUTPUT
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "myapp", Version: "2.1.0"}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "pkgconfig:glib-2.0", Version: ">=2.50", Eco: parser.NATIVE},
+		{Name: "pkgconfig:gio-2.0", Eco: parser.NATIVE},
+		{Name: "pkgconfig:libxml-2.0", Eco: parser.NATIVE},
+		{Name: "lib:z", Eco: parser.NATIVE},
+		{Name: "lib:dld", Eco: parser.NATIVE},
+	}, deps)
+
+	pkg, _, err = Parse(`AC_INIT([myapp], m4_esyscmd([build-aux/git-version-gen .tarball-version]))`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "myapp"}, pkg)
+}
@@ -0,0 +1,245 @@
+package cmake
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco/c/pkgconfig"
+	"github.com/samber/lo"
+)
+
+// command is a command invocation of a CMake file, with its arguments
+// unquoted. Variable references are kept as they are.
+type command struct {
+	name string
+	args []string
+}
+
+var versionRegexp = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// packages found by find_package which are build tools, not libraries
+var ignoredPackages = map[string]bool{
+	"PkgConfig": true,
+	"Threads":   true,
+}
+
+// keywords of pkg_check_modules and pkg_search_module before the modules
+var pkgConfigKeywords = map[string]bool{
+	"REQUIRED":                  true,
+	"QUIET":                     true,
+	"NO_CMAKE_PATH":             true,
+	"NO_CMAKE_ENVIRONMENT_PATH": true,
+	"IMPORTED_TARGET":           true,
+	"GLOBAL":                    true,
+}
+
+// scanner reads the command invocations of a CMake file, see
+// https://cmake.org/cmake/help/latest/manual/cmake-language.7.html
+type scanner struct {
+	s   string
+	pos int
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isIdentifier(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// bracket reads a bracket argument or comment at pos, e.g. [==[...]==],
+// and reports whether there is one.
+func (sc *scanner) bracket() (string, bool) {
+	i := sc.pos + 1
+	for i < len(sc.s) && sc.s[i] == '=' {
+		i++
+	}
+	if i >= len(sc.s) || sc.s[i] != '[' {
+		return "", false
+	}
+	closing := "]" + sc.s[sc.pos+1:i] + "]"
+	end := strings.Index(sc.s[i+1:], closing)
+	if end < 0 {
+		sc.pos = len(sc.s)
+		return sc.s[i+1:], true
+	}
+	sc.pos = i + 1 + end + len(closing)
+	return sc.s[i+1 : i+1+end], true
+}
+
+func (sc *scanner) comment() {
+	sc.pos++
+	if sc.pos < len(sc.s) && sc.s[sc.pos] == '[' {
+		if _, ok := sc.bracket(); ok {
+			return
+		}
+	}
+	for sc.pos < len(sc.s) && sc.s[sc.pos] != '\n' {
+		sc.pos++
+	}
+}
+
+func (sc *scanner) quoted() string {
+	var b strings.Builder
+	for sc.pos++; sc.pos < len(sc.s); sc.pos++ {
+		c := sc.s[sc.pos]
+		if c == '"' {
+			sc.pos++
+			break
+		}
+		if c == '\\' && sc.pos+1 < len(sc.s) {
+			sc.pos++
+			c = sc.s[sc.pos]
+			if c == '\n' {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// arguments reads the arguments of a command after the opening
+// parenthesis. Parentheses nested in the arguments, e.g. of if, are
+// dropped.
+func (sc *scanner) arguments() []string {
+	args := []string{}
+	depth := 1
+	for sc.pos < len(sc.s) {
+		c := sc.s[sc.pos]
+		switch {
+		case isSpace(c):
+			sc.pos++
+		case c == '#':
+			sc.comment()
+		case c == '(':
+			depth++
+			sc.pos++
+		case c == ')':
+			sc.pos++
+			if depth--; depth == 0 {
+				return args
+			}
+		case c == '"':
+			args = append(args, sc.quoted())
+		case c == '[':
+			if arg, ok := sc.bracket(); ok {
+				args = append(args, arg)
+				continue
+			}
+			fallthrough
+		default:
+			start := sc.pos
+			for sc.pos < len(sc.s) && !isSpace(sc.s[sc.pos]) && !strings.ContainsRune("()\"#", rune(sc.s[sc.pos])) {
+				sc.pos++
+			}
+			args = append(args, sc.s[start:sc.pos])
+		}
+	}
+	return args
+}
+
+// commands returns the command invocations of a CMake file.
+func commands(content string) []command {
+	sc := &scanner{s: content}
+	ret := []command{}
+	for sc.pos < len(sc.s) {
+		c := sc.s[sc.pos]
+		switch {
+		case c == '#':
+			sc.comment()
+		case isIdentifier(c, true):
+			start := sc.pos
+			for sc.pos < len(sc.s) && isIdentifier(sc.s[sc.pos], false) {
+				sc.pos++
+			}
+			name := sc.s[start:sc.pos]
+			for sc.pos < len(sc.s) && (sc.s[sc.pos] == ' ' || sc.s[sc.pos] == '\t') {
+				sc.pos++
+			}
+			if sc.pos < len(sc.s) && sc.s[sc.pos] == '(' {
+				sc.pos++
+				ret = append(ret, command{name: strings.ToLower(name), args: sc.arguments()})
+			}
+		default:
+			sc.pos++
+		}
+	}
+	return ret
+}
+
+// keyword returns the argument after the keyword, or an empty string.
+func keyword(args []string, key string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == key {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// Parse parses CMakeLists.txt. The package is declared by project, and
+// the dependencies are the packages of find_package, the pkg-config
+// modules of pkg_check_modules and pkg_search_module, and the contents of
+// FetchContent_Declare, by the URL of their repositories if they have
+// one. Arguments with variable references are skipped, as variables are
+// not evaluated. Files with neither return no package.
+func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg := &langeco.Package{}
+	deps := langeco.Dependencies{}
+	seen := make(map[string]bool)
+	add := func(dep langeco.Package) {
+		if strings.Contains(dep.Name, "${") || seen[dep.Name] {
+			return
+		}
+		if strings.Contains(dep.Version, "${") {
+			dep.Version = ""
+		}
+		seen[dep.Name] = true
+		deps = append(deps, dep)
+	}
+
+	for _, cmd := range commands(content) {
+		if len(cmd.args) == 0 {
+			continue
+		}
+		switch cmd.name {
+		case "project":
+			if pkg.Name == "" && !strings.Contains(cmd.args[0], "${") {
+				pkg.Name = cmd.args[0]
+				if v := keyword(cmd.args, "VERSION"); !strings.Contains(v, "${") {
+					pkg.Version = v
+				}
+			}
+		case "find_package":
+			if ignoredPackages[cmd.args[0]] {
+				continue
+			}
+			dep := langeco.Package{Name: parser.NativeName(parser.NATIVE_CMAKE, cmd.args[0]), Eco: parser.NATIVE}
+			if len(cmd.args) > 1 && versionRegexp.MatchString(cmd.args[1]) {
+				dep.Version = cmd.args[1]
+			}
+			add(dep)
+		case "pkg_check_modules", "pkg_search_module":
+			modules := lo.Reject(cmd.args[1:], func(arg string, _ int) bool { return pkgConfigKeywords[arg] })
+			for _, dep := range pkgconfig.ParseModules(strings.Join(modules, " ")) {
+				add(dep)
+			}
+		case "fetchcontent_declare":
+			dep := langeco.Package{Name: parser.NativeName(parser.NATIVE_CMAKE, cmd.args[0]), Eco: parser.NATIVE}
+			if repo := keyword(cmd.args, "GIT_REPOSITORY"); repo != "" {
+				dep.Name = parser.NativeName(parser.NATIVE_GIT, repo)
+				dep.Version = keyword(cmd.args, "GIT_TAG")
+			}
+			add(dep)
+		}
+	}
+
+	if pkg.Name == "" && len(deps) == 0 {
+		return nil, nil, nil
+	}
+	return pkg, &deps, nil
+}
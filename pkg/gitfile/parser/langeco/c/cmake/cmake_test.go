@@ -0,0 +1,50 @@
+package cmake
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`cmake_minimum_required(VERSION 3.14)
+project(myapp VERSION 1.2.3 LANGUAGES C CXX)
+
+#[[ find_package(Commented) ]]
+find_package(ZLIB 1.2 REQUIRED) # find_package(Commented)
+find_package(Threads REQUIRED)
+find_package (OpenSSL)
+find_package(${DEP_NAME})
+if(UNIX AND (NOT APPLE))
+  find_package(PkgConfig REQUIRED)
+  pkg_check_modules(GLIB REQUIRED IMPORTED_TARGET "glib-2.0>=2.50" gio-2.0 >= 2.60)
+  pkg_search_module(ZLIB zlib)
+endif()
+
+include(FetchContent)
+FetchContent_Declare(
+  googletest
+  GIT_REPOSITORY https://github.com/google/googletest.git
+  GIT_TAG        v1.14.0
+)
+FetchContent_Declare(json URL [=[https://example.org/json.tar.xz]=])
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "myapp", Version: "1.2.3"}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "cmake:ZLIB", Version: "1.2", Eco: parser.NATIVE},
+		{Name: "cmake:OpenSSL", Eco: parser.NATIVE},
+		{Name: "pkgconfig:glib-2.0", Version: ">=2.50", Eco: parser.NATIVE},
+		{Name: "pkgconfig:gio-2.0", Version: ">=2.60", Eco: parser.NATIVE},
+		{Name: "pkgconfig:zlib", Eco: parser.NATIVE},
+		{Name: "git:https://github.com/google/googletest.git", Version: "v1.14.0", Eco: parser.NATIVE},
+		{Name: "cmake:json", Eco: parser.NATIVE},
+	}, deps)
+
+	pkg, deps, err = Parse("add_subdirectory(src)\n")
+	require.NoError(t, err)
+	require.Nil(t, pkg)
+	require.Nil(t, deps)
+}
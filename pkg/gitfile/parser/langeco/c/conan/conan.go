@@ -1,9 +1,10 @@
 package conan
 
 import (
-	"encoding/json"
+	"sort"
 	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 	"golang.org/x/xerrors"
@@ -33,14 +34,44 @@ type Require struct {
 
 type Requires []Require
 
-// ToDo
+// parseV1 reads the graph of conan 1.x, node "0" is the consumer itself.
 func parseV1(lock LockFile) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	pkg := &langeco.Package{}
+	deps := langeco.Dependencies{}
+	ids := make([]string, 0, len(lock.GraphLock.Nodes))
+	for id := range lock.GraphLock.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		node := lock.GraphLock.Nodes[id]
+		if node.Ref == "" {
+			continue
+		}
+		name, version, err := ParsePackage(node.Ref)
+		if err != nil {
+			continue
+		}
+		if id == "0" {
+			pkg.Name, pkg.Version = name, version
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.CONAN})
+	}
+	return pkg, &deps, nil
 }
 
-// ToDo
+// parseV2 reads the flat requires of conan 2.x, which has no consumer.
 func parseV2(lock LockFile) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := langeco.Dependencies{}
+	for _, req := range lock.Requires {
+		name, version, err := ParsePackage(req.Dependency)
+		if err != nil {
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.CONAN})
+	}
+	return &langeco.Package{}, &deps, nil
 }
 
 func ParsePackage(text string) (string, string, error) {
@@ -81,7 +112,7 @@ func (r *Require) UnmarshalJSONWithMetadata(node jfather.Node) error {
 
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lock LockFile
-	if err := json.Unmarshal([]byte(content), &lock); err != nil {
+	if err := jfather.Unmarshal([]byte(content), &lock); err != nil {
 		return nil, nil, err
 	}
 
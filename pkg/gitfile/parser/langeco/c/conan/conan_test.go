@@ -1,21 +1,60 @@
 package conan
 
 import (
-	"fmt"
-	"io"
-	"os"
 	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	file, _ := os.Open("")
-	defer file.Close()
-	data, _ := io.ReadAll(file)
-	t.Run("Parse Conan", func(t *testing.T) {
-		pkg, deps, _ := Parse(string(data))
-		fmt.Println(*pkg)
-		for index, dep := range *deps {
-			fmt.Println(index, dep)
-		}
-	})
+	tests := []struct {
+		name     string
+		content  string
+		wantPkg  *langeco.Package
+		wantDeps *langeco.Dependencies
+	}{
+		{
+			name: "conan 1.x",
+			content: `{
+  "graph_lock": {
+    "nodes": {
+      "0": {"ref": "myapp/1.0", "requires": ["1", "2"]},
+      "1": {"ref": "zlib/1.2.11@user/stable"},
+      "2": {"ref": "openssl/1.1.1k#f3c5d2e1"}
+    }
+  },
+  "version": "0.4"
+}`,
+			wantPkg: &langeco.Package{Name: "myapp", Version: "1.0"},
+			wantDeps: &langeco.Dependencies{
+				{Name: "zlib", Version: "1.2.11", Eco: parser.CONAN},
+				{Name: "openssl", Version: "1.1.1k", Eco: parser.CONAN},
+			},
+		},
+		{
+			name: "conan 2.x",
+			content: `{
+  "version": "0.5",
+  "requires": [
+    "zlib/1.3#b3b71bfe8dd07abc7b82ff2bd0eac021%1700000000.0",
+    "fmt/10.1.1"
+  ]
+}`,
+			wantPkg: &langeco.Package{},
+			wantDeps: &langeco.Dependencies{
+				{Name: "zlib", Version: "1.3", Eco: parser.CONAN},
+				{Name: "fmt", Version: "10.1.1", Eco: parser.CONAN},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, deps, err := Parse(tt.content)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPkg, pkg)
+			require.Equal(t, tt.wantDeps, deps)
+		})
+	}
 }
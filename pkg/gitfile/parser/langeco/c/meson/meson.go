@@ -0,0 +1,212 @@
+package meson
+
+import (
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+const (
+	tokenIdentifier = iota
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind  int
+	value string
+}
+
+// dependencies provided by meson itself, see
+// https://mesonbuild.com/Dependencies.html#dependencies-with-custom-lookup-functionality
+var builtinDependencies = map[string]bool{
+	"threads":         true,
+	"openmp":          true,
+	"dl":              true,
+	"appleframeworks": true,
+}
+
+func isIdentifier(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// tokenize returns the identifiers, string literals and punctuation of a
+// meson file, without comments. Escapes in strings are kept as they are.
+func tokenize(s string) []token {
+	ret := []token{}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '\'' || (c == 'f' && i+1 < len(s) && s[i+1] == '\''):
+			if c == 'f' {
+				i++
+			}
+			if strings.HasPrefix(s[i:], "'''") {
+				end := strings.Index(s[i+3:], "'''")
+				if end < 0 {
+					end = len(s) - i - 3
+				}
+				ret = append(ret, token{tokenString, s[i+3 : i+3+end]})
+				i = min(len(s), i+3+end+3)
+				continue
+			}
+			start := i + 1
+			for i++; i < len(s) && s[i] != '\'' && s[i] != '\n'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+			ret = append(ret, token{tokenString, s[start:min(i, len(s))]})
+			i++
+		case isIdentifier(c, true):
+			start := i
+			for i < len(s) && isIdentifier(s[i], false) {
+				i++
+			}
+			ret = append(ret, token{tokenIdentifier, s[start:i]})
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		default:
+			ret = append(ret, token{tokenPunct, string(c)})
+			i++
+		}
+	}
+	return ret
+}
+
+// call is a function or method call with the arguments which are string
+// literals or lists of them, other arguments are nil.
+type call struct {
+	name   string
+	args   [][]string
+	kwargs map[string][]string
+}
+
+// stringValue returns the strings of a value which is a string literal or
+// a list of string literals, or nil.
+func stringValue(tokens []token) []string {
+	if len(tokens) == 1 && tokens[0].kind == tokenString {
+		return []string{tokens[0].value}
+	}
+	if len(tokens) < 2 || tokens[0].value != "[" || tokens[len(tokens)-1].value != "]" {
+		return nil
+	}
+	ret := []string{}
+	for i, t := range tokens[1 : len(tokens)-1] {
+		if i%2 == 0 && t.kind != tokenString || i%2 == 1 && t.value != "," {
+			return nil
+		}
+		if t.kind == tokenString {
+			ret = append(ret, t.value)
+		}
+	}
+	return ret
+}
+
+// calls returns every call of the functions or methods of names, nested
+// calls included.
+func calls(tokens []token, names map[string]bool) []call {
+	ret := []call{}
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].kind != tokenIdentifier || !names[tokens[i].value] || tokens[i+1].value != "(" {
+			continue
+		}
+		c := call{name: tokens[i].value, kwargs: make(map[string][]string)}
+		depth := 0
+		start := i + 2
+	args:
+		for j := i + 2; j < len(tokens); j++ {
+			t := tokens[j]
+			if t.kind == tokenPunct {
+				switch t.value {
+				case "(", "[", "{":
+					depth++
+					continue
+				case ")", "]", "}":
+					if depth > 0 {
+						depth--
+						continue
+					}
+				case ",":
+					if depth > 0 {
+						continue
+					}
+				default:
+					continue
+				}
+				arg := tokens[start:j]
+				if len(arg) > 2 && arg[0].kind == tokenIdentifier && arg[1].value == ":" {
+					c.kwargs[arg[0].value] = stringValue(arg[2:])
+				} else if len(arg) > 0 {
+					c.args = append(c.args, stringValue(arg))
+				}
+				start = j + 1
+				if t.value == ")" {
+					break args
+				}
+			}
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// Parse parses meson.build. The package is declared by project, and the
+// dependencies are the names of dependency, which are pkg-config modules
+// unless they are found by CMake or other methods, and the libraries of
+// find_library. Files with neither return no package.
+func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	pkg := &langeco.Package{}
+	deps := langeco.Dependencies{}
+	seen := make(map[string]bool)
+	add := func(dep langeco.Package) {
+		if !seen[dep.Name] {
+			seen[dep.Name] = true
+			deps = append(deps, dep)
+		}
+	}
+
+	names := map[string]bool{"project": true, "dependency": true, "find_library": true}
+	for _, c := range calls(tokenize(content), names) {
+		if len(c.args) == 0 || len(c.args[0]) == 0 || c.args[0][0] == "" {
+			continue
+		}
+		name := c.args[0][0]
+		switch c.name {
+		case "project":
+			if pkg.Name == "" {
+				pkg.Name = name
+				if v := c.kwargs["version"]; len(v) == 1 {
+					pkg.Version = v[0]
+				}
+			}
+		case "dependency":
+			if builtinDependencies[name] {
+				continue
+			}
+			kind := parser.NATIVE_PKGCONFIG
+			if m := c.kwargs["method"]; len(m) == 1 && m[0] == "cmake" {
+				kind = parser.NATIVE_CMAKE
+			}
+			add(langeco.Package{
+				Name:    parser.NativeName(kind, name),
+				Version: strings.Join(strings.Fields(strings.Join(c.kwargs["version"], ", ")), ""),
+				Eco:     parser.NATIVE,
+			})
+		case "find_library":
+			if !parser.NATIVE_SYSTEM_LIBS[name] {
+				add(langeco.Package{Name: parser.NativeName(parser.NATIVE_LIB, name), Eco: parser.NATIVE})
+			}
+		}
+	}
+
+	if pkg.Name == "" && len(deps) == 0 {
+		return nil, nil, nil
+	}
+	return pkg, &deps, nil
+}
@@ -0,0 +1,43 @@
+package meson
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`project('myapp', 'c',
+  version : '0.3.1',
+  license : 'MIT',
+)
+
+cc = meson.get_compiler('c')
+# dependency('commented')
+deps = [
+  dependency('glib-2.0', version : ['>= 2.50', '< 3.0']),
+  dependency('zlib', required : get_option('zlib')),
+  dependency('threads'),
+  dependency('Boost', method : 'cmake', modules : ['system']),
+  dependency('', required : false),
+  cc.find_library('m', required : false),
+  cc.find_library('z'),
+]
+executable('myapp', 'main.c', dependencies : deps)
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "myapp", Version: "0.3.1"}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "pkgconfig:glib-2.0", Version: ">=2.50,<3.0", Eco: parser.NATIVE},
+		{Name: "pkgconfig:zlib", Eco: parser.NATIVE},
+		{Name: "cmake:Boost", Eco: parser.NATIVE},
+		{Name: "lib:z", Eco: parser.NATIVE},
+	}, deps)
+
+	pkg, deps, err = Parse("subdir('src')\n")
+	require.NoError(t, err)
+	require.Nil(t, pkg)
+	require.Nil(t, deps)
+}
@@ -0,0 +1,44 @@
+package pkgconfig
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+var operatorRegexp = regexp.MustCompile(`(>=|<=|!=|=|>|<)`)
+
+func isOperator(token string) bool {
+	switch token {
+	case ">=", "<=", "!=", "=", ">", "<":
+		return true
+	}
+	return false
+}
+
+// ParseModules parses a list of pkg-config modules with optional version
+// constraints, as in PKG_CHECK_MODULES or the Requires of .pc files, e.g.
+// "glib-2.0 >= 2.50, gio-2.0" or "glib-2.0>=2.50".
+func ParseModules(spec string) langeco.Dependencies {
+	spec = strings.ReplaceAll(spec, ",", " ")
+	tokens := strings.Fields(operatorRegexp.ReplaceAllString(spec, " $1 "))
+
+	deps := langeco.Dependencies{}
+	for i := 0; i < len(tokens); i++ {
+		if isOperator(tokens[i]) || strings.Contains(tokens[i], "$") {
+			continue
+		}
+		dep := langeco.Package{
+			Name: parser.NativeName(parser.NATIVE_PKGCONFIG, tokens[i]),
+			Eco:  parser.NATIVE,
+		}
+		if i+2 < len(tokens) && isOperator(tokens[i+1]) {
+			dep.Version = tokens[i+1] + tokens[i+2]
+			i += 2
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
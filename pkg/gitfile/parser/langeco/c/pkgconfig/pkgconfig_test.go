@@ -0,0 +1,19 @@
+package pkgconfig
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModules(t *testing.T) {
+	require.Equal(t, langeco.Dependencies{
+		{Name: "pkgconfig:glib-2.0", Version: ">=2.50", Eco: parser.NATIVE},
+		{Name: "pkgconfig:gio-2.0", Eco: parser.NATIVE},
+		{Name: "pkgconfig:libxml-2.0", Version: ">=2.9", Eco: parser.NATIVE},
+		{Name: "pkgconfig:zlib", Eco: parser.NATIVE},
+	}, ParseModules("glib-2.0 >= 2.50, gio-2.0 libxml-2.0>=2.9 zlib $EXTRA"))
+	require.Empty(t, ParseModules(""))
+}
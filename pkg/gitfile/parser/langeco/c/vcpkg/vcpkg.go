@@ -0,0 +1,66 @@
+package vcpkg
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+var (
+	ErrDecodingFailed = errors.New("decoding vcpkg.json failed")
+)
+
+type manifest struct {
+	Name          string            `json:"name"`
+	Version       string            `json:"version"`
+	VersionSemver string            `json:"version-semver"`
+	VersionDate   string            `json:"version-date"`
+	VersionString string            `json:"version-string"`
+	Dependencies  []json.RawMessage `json:"dependencies"`
+}
+
+// dependency is a dependency of the object form, the string form is only
+// the name.
+type dependency struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"version>="`
+	Host       bool   `json:"host"`
+}
+
+// Parse parses vcpkg.json. Dependencies are named by their vcpkg ports,
+// host dependencies, which are build tools, are skipped.
+func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
+	var m manifest
+	if err := json.Unmarshal([]byte(content), &m); err != nil {
+		return nil, nil, ErrDecodingFailed
+	}
+
+	pkg := &langeco.Package{Name: m.Name}
+	for _, v := range []string{m.Version, m.VersionSemver, m.VersionDate, m.VersionString} {
+		if v != "" {
+			pkg.Version = v
+			break
+		}
+	}
+
+	deps := make(langeco.Dependencies, 0, len(m.Dependencies))
+	for _, raw := range m.Dependencies {
+		var d dependency
+		if err := json.Unmarshal(raw, &d.Name); err != nil {
+			if err := json.Unmarshal(raw, &d); err != nil {
+				return nil, nil, ErrDecodingFailed
+			}
+		}
+		if d.Name == "" || d.Host {
+			continue
+		}
+		dep := langeco.Package{Name: parser.NativeName(parser.NATIVE_VCPKG, d.Name), Eco: parser.NATIVE}
+		if d.MinVersion != "" {
+			dep.Version = ">=" + d.MinVersion
+		}
+		deps = append(deps, dep)
+	}
+	return pkg, &deps, nil
+}
@@ -0,0 +1,32 @@
+package vcpkg
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`{
+  "name": "myapp",
+  "version-semver": "1.0.0",
+  "dependencies": [
+    "zlib",
+    {"name": "fmt", "version>=": "10.1.1"},
+    {"name": "vcpkg-cmake", "host": true},
+    {"name": "openssl", "platform": "!windows"}
+  ]
+}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "myapp", Version: "1.0.0"}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "vcpkg:zlib", Eco: parser.NATIVE},
+		{Name: "vcpkg:fmt", Version: ">=10.1.1", Eco: parser.NATIVE},
+		{Name: "vcpkg:openssl", Eco: parser.NATIVE},
+	}, deps)
+
+	_, _, err = Parse("{")
+	require.ErrorIs(t, err, ErrDecodingFailed)
+}
@@ -1,7 +1,32 @@
 package elixir
 
-import "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+import (
+	"regexp"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+// e.g. "cowboy": {:hex, :cowboy, "2.9.0", "2c72...", [:make, :rebar3], [...], "hexpm", "4fb0..."},
+var hexDepRegexp = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*\{\s*:hex\s*,\s*:"?[^,]+"?\s*,\s*"([^"]+)"`)
+
+// e.g. "plug": {:git, "https://github.com/elixir-plug/plug.git", "9f6b...", []},
+var gitDepRegexp = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*\{\s*:git\s*,\s*"[^"]*"\s*,\s*"([^"]+)"`)
+
+// Parse parses mix.lock. The lock file does not have the root package, so
+// the returned package is empty.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := langeco.Dependencies{}
+	for _, line := range strings.Split(content, "\n") {
+		m := hexDepRegexp.FindStringSubmatch(line)
+		if m == nil {
+			m = gitDepRegexp.FindStringSubmatch(line)
+		}
+		if m == nil {
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: m[1], Version: m[2], Eco: parser.ELIXIR})
+	}
+	return &langeco.Package{}, &deps, nil
 }
@@ -1 +1,22 @@
 package elixir
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	_, deps, err := Parse(`%{
+  "cowboy": {:hex, :cowboy, "2.10.0", "ff9ffeff91dae4ae270dd975642997afe2a1179d94b1887863e43f681a203e26", [:make, :rebar3], [{:cowlib, "2.12.1", [hex: :cowlib, repo: "hexpm", optional: false]}], "hexpm", "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b"},
+  "plug": {:git, "https://github.com/elixir-plug/plug.git", "9f6b1c8e2c1e0f0b0a2f6f0c5f4b9d6b2a6d1e3f", []},
+}
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "cowboy", Version: "2.10.0", Eco: parser.ELIXIR},
+		{Name: "plug", Version: "9f6b1c8e2c1e0f0b0a2f6f0c5f4b9d6b2a6d1e3f", Eco: parser.ELIXIR},
+	}, deps)
+}
@@ -19,3 +19,29 @@ func TestParse(t *testing.T) {
 		}
 	})
 }
+
+func TestParseCoordinates(t *testing.T) {
+	pkg, deps, err := Parse(`<project>
+  <groupId>org.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <properties><scala.version>2.2.0</scala.version></properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.scala-lang.modules</groupId>
+      <artifactId>scala-xml_2.13</artifactId>
+      <version>${scala.version}</version>
+    </dependency>
+  </dependencies>
+</project>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name != "org.example:app" {
+		t.Errorf("Expected org.example:app, got %s", pkg.Name)
+	}
+	// group and artifact are kept apart, as artifacts may contain dots
+	if len(*deps) != 1 || (*deps)[0].Name != "org.scala-lang.modules:scala-xml_2.13" || (*deps)[0].Version != "2.2.0" {
+		t.Errorf("Wrong dependencies %+v", *deps)
+	}
+}
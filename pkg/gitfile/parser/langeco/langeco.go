@@ -1,20 +1,5 @@
 package langeco
 
-import (
-	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
-)
-
-var (
-	SUPPORTED_ECOS = map[string]bool{
-		parser.NPM:   true,
-		parser.GO:    true,
-		parser.MAVEN: true,
-		parser.CARGO: true,
-		parser.PYPI:  true,
-		parser.NUGET: true,
-		parser.REBAR: true,
-	}
-
 /*
 	TRUSTED_FILES = map[string]string{
 		parser.NPM:   NPM_PACKAGE_LOCK,
@@ -25,7 +10,6 @@ var (
 		parser.NUGET: NUGET_CONFIG, //* NUGET_PROPS
 	}
 */
-)
 
 const (
 	NUGET_CONFIG        = "packages.config"
@@ -45,6 +29,26 @@ const (
 	REBAR_LOCK					= "rebar.lock"
 )
 
+// patterns of lock files and metadata, matched against the base name, or
+// the trailing path if the pattern has a slash
+const (
+	CONAN_LOCK     = "conan.lock"
+	CONDA_META     = "conda-meta/*.json"
+	MIX_LOCK       = "mix.lock"
+	COMPOSER_LOCK  = "composer.lock"
+	SWIFT_RESOLVED = "Package.resolved"
+	PNPM_LOCK      = "pnpm-lock.yaml"
+	YARN_LOCK      = "yarn.lock"
+	POETRY_LOCK    = "poetry.lock"
+	PIPFILE_LOCK   = "Pipfile.lock"
+	GEMFILE_LOCK   = "Gemfile.lock"
+	GEMSPEC        = "*.gemspec"
+	CMAKE_LISTS    = "CMakeLists.txt"
+	MESON_BUILD    = "meson.build"
+	CONFIGURE_AC   = "configure.ac"
+	VCPKG_JSON     = "vcpkg.json"
+)
+
 type Package struct {
 	Name    string
 	Version string
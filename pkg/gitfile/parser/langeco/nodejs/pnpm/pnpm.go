@@ -2,7 +2,10 @@ package pnpm
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"gopkg.in/yaml.v3"
 )
@@ -38,6 +41,43 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	keys := make([]string, 0, len(lockFile.Packages))
+	for key := range lockFile.Packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	deps := make(langeco.Dependencies, 0, len(keys))
+	for _, key := range keys {
+		info := lockFile.Packages[key]
+		if info.IsDev {
+			continue
+		}
+		name, version := parsePackageKey(key)
+		if info.Name != "" {
+			name, version = info.Name, info.Version
+		}
+		if name == "" {
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.NPM})
+	}
+	return &langeco.Package{}, &deps, nil
+}
+
+// parsePackageKey splits the key of packages into name and version, e.g.
+// /lodash/4.17.21 before lockfile v6, /@babel/core@7.0.0(supports-color@8.1.1)
+// in v6 and lodash@4.17.21 since v9.
+func parsePackageKey(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i >= 0 {
+		key = key[:i]
+	}
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	if i := strings.LastIndex(key, "/"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
 }
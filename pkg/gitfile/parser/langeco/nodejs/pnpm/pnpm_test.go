@@ -1 +1,42 @@
 package pnpm
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	_, deps, err := Parse(`lockfileVersion: '6.0'
+packages:
+  /@babel/core@7.23.2(supports-color@8.1.1):
+    resolution: {integrity: sha512-x}
+    dev: false
+  /lodash@4.17.21:
+    resolution: {integrity: sha512-y}
+    dev: false
+  /jest@29.7.0:
+    resolution: {integrity: sha512-z}
+    dev: true
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "@babel/core", Version: "7.23.2", Eco: parser.NPM},
+		{Name: "lodash", Version: "4.17.21", Eco: parser.NPM},
+	}, deps)
+}
+
+func TestParsePackageKey(t *testing.T) {
+	for key, want := range map[string][2]string{
+		"/lodash/4.17.21":              {"lodash", "4.17.21"},
+		"/@types/node/20.8.0":          {"@types/node", "20.8.0"},
+		"/@types/node@20.8.0":          {"@types/node", "20.8.0"},
+		"lodash@4.17.21":               {"lodash", "4.17.21"},
+		"/ws@8.14.2(bufferutil@4.0.8)": {"ws", "8.14.2"},
+	} {
+		name, version := parsePackageKey(key)
+		require.Equal(t, want, [2]string{name, version}, key)
+	}
+}
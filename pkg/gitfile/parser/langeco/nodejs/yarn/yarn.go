@@ -2,7 +2,9 @@ package yarn
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
@@ -25,7 +27,60 @@ type Dependency struct {
 	Name    string
 }
 
+// Parse parses yarn.lock of yarn classic and berry. The lock file does
+// not have the root package, so the returned package is empty.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := langeco.Dependencies{}
+	var lib *Library
+	flush := func() {
+		if lib != nil && lib.Name != "" {
+			deps = append(deps, langeco.Package{Name: lib.Name, Version: lib.Version, Eco: parser.NPM})
+		}
+		lib = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			// e.g. "@babel/core@^7.0.0", "@babel/core@^7.1.0":
+			flush()
+			if !strings.HasSuffix(line, ":") {
+				return nil, nil, ErrParsingFailed
+			}
+			patterns := strings.Split(strings.TrimSuffix(line, ":"), ",")
+			for i, p := range patterns {
+				patterns[i] = strings.Trim(strings.TrimSpace(p), `"`)
+			}
+			name := packageName(patterns[0])
+			// __metadata of berry and the workspaces themselves
+			if name == "" || strings.Contains(patterns[0], "@workspace:") {
+				continue
+			}
+			lib = &Library{Patterns: patterns, Name: name}
+			continue
+		}
+		if lib == nil {
+			continue
+		}
+		// version "1.0.0" in classic, version: 1.0.0 in berry
+		field := strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(field, "version"); ok && (strings.HasPrefix(v, " ") || strings.HasPrefix(v, ":")) {
+			lib.Version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(v, ":")), `"`)
+		}
+	}
+	flush()
+	return &langeco.Package{}, &deps, nil
+}
+
+// packageName returns the name of a pattern, e.g. @babel/core of
+// @babel/core@npm:^7.0.0, or an empty string if it has no version.
+func packageName(pattern string) string {
+	i := strings.LastIndex(pattern, "@")
+	if i <= 0 {
+		return ""
+	}
+	return pattern[:i]
 }
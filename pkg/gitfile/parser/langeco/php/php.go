@@ -2,7 +2,9 @@ package php
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 )
@@ -24,14 +26,26 @@ type packageInfo struct {
 	EndLine   int
 }
 
-// ToDo
+// Parse parses composer.lock. The lock file does not have the root
+// package, so the returned package is empty.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 	var lockFile lockFile
 	if err := jfather.Unmarshal([]byte(content), &lockFile); err != nil {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	deps := make(langeco.Dependencies, 0, len(lockFile.Packages))
+	for _, pkg := range lockFile.Packages {
+		if pkg.Name == "" {
+			continue
+		}
+		deps = append(deps, langeco.Package{
+			Name:    pkg.Name,
+			Version: strings.TrimPrefix(pkg.Version, "v"),
+			Eco:     parser.COMPOSER,
+		})
+	}
+	return &langeco.Package{}, &deps, nil
 }
 
 // UnmarshalJSONWithMetadata needed to detect start and end lines of deps
@@ -1 +1,30 @@
 package php
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`{
+    "packages": [
+        {"name": "monolog/monolog", "version": "v3.5.0", "require": {"php": ">=8.1"}},
+        {"name": "psr/log", "version": "3.0.0"}
+    ],
+    "packages-dev": [
+        {"name": "phpunit/phpunit", "version": "10.5.0"}
+    ]
+}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "monolog/monolog", Version: "3.5.0", Eco: parser.COMPOSER},
+		{Name: "psr/log", Version: "3.0.0", Eco: parser.COMPOSER},
+	}, deps)
+
+	_, _, err = Parse("{")
+	require.ErrorIs(t, err, ErrDecodingFailed)
+}
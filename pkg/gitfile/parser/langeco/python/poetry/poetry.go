@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/BurntSushi/toml"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
@@ -31,6 +32,12 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	// ToDo
-	return nil, nil, nil
+	deps := make(langeco.Dependencies, 0, len(lockfile.Packages))
+	for _, pkg := range lockfile.Packages {
+		if pkg.Category == "dev" {
+			continue
+		}
+		deps = append(deps, langeco.Package{Name: pkg.Name, Version: pkg.Version, Eco: parser.PYPI})
+	}
+	return &langeco.Package{}, &deps, nil
 }
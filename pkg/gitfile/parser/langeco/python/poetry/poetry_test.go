@@ -1 +1,34 @@
 package poetry
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	_, deps, err := Parse(`[[package]]
+name = "certifi"
+version = "2023.7.22"
+category = "main"
+
+[[package]]
+name = "pytest"
+version = "7.4.2"
+category = "dev"
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "certifi", Version: "2023.7.22", Eco: parser.PYPI},
+		{Name: "requests", Version: "2.31.0", Eco: parser.PYPI},
+	}, deps)
+}
@@ -2,7 +2,10 @@ package pipenv
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 	"github.com/liamg/jfather"
 )
@@ -30,9 +33,20 @@ func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
 		return nil, nil, ErrDecodingFailed
 	}
 
-	pkg := langeco.Package{
-		Name: lockFile.Meta["sources"][0].Name,
+	// the sources in _meta are indexes, e.g. pypi, not the project itself
+	names := make([]string, 0, len(lockFile.Default))
+	for name := range lockFile.Default {
+		names = append(names, name)
 	}
-	// ToDo
-	return &pkg, nil, nil
+	sort.Strings(names)
+
+	deps := make(langeco.Dependencies, 0, len(names))
+	for _, name := range names {
+		deps = append(deps, langeco.Package{
+			Name:    name,
+			Version: strings.TrimPrefix(lockFile.Default[name].Version, "=="),
+			Eco:     parser.PYPI,
+		})
+	}
+	return &langeco.Package{}, &deps, nil
 }
@@ -1 +1,28 @@
 package pipenv
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`{
+    "_meta": {"sources": [{"name": "pypi", "url": "https://pypi.org/simple"}]},
+    "default": {
+        "urllib3": {"version": "==2.0.7"},
+        "requests": {"version": "==2.31.0"}
+    },
+    "develop": {
+        "pytest": {"version": "==7.4.2"}
+    }
+}`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "requests", Version: "2.31.0", Eco: parser.PYPI},
+		{Name: "urllib3", Version: "2.0.7", Eco: parser.PYPI},
+	}, deps)
+}
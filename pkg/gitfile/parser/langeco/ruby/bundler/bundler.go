@@ -1,10 +1,57 @@
 package bundler
 
 import (
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
 	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
 )
 
+// Parse parses Gemfile.lock. The spec in the PATH section is the gem of
+// the repository itself, and the specs in the GEM and GIT sections are
+// its dependencies.
 func Parse(content string) (*langeco.Package, *langeco.Dependencies, error) {
-	// ToDo
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	pkg := &langeco.Package{}
+	deps := langeco.Dependencies{}
+
+	var section string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section = line
+			continue
+		}
+		// gems are indented by 4 spaces, and their requirements by 6
+		if !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue
+		}
+		name, version := parseSpec(strings.TrimSpace(line))
+		switch section {
+		case "PATH":
+			if pkg.Name == "" {
+				pkg.Name, pkg.Version = name, version
+			}
+		case "GEM", "GIT":
+			deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.GEMS})
+		}
+	}
+	return pkg, &deps, nil
+}
+
+// parseSpec splits a spec like rack (2.2.3) or nokogiri (1.15.4-x86_64-linux)
+// into name and version.
+func parseSpec(spec string) (string, string) {
+	name, version, ok := strings.Cut(spec, " ")
+	if !ok {
+		return name, ""
+	}
+	version = strings.Trim(version, "()")
+	// platform specific gems
+	if i := strings.Index(version, "-"); i >= 0 {
+		version = version[:i]
+	}
+	return name, version
 }
@@ -1 +1,40 @@
 package bundler
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pkg, deps, err := Parse(`PATH
+  remote: .
+  specs:
+    mygem (0.1.0)
+      rack (>= 2.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    rack (2.2.8)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  mygem!
+
+BUNDLED WITH
+   2.4.10
+`)
+	require.NoError(t, err)
+	require.Equal(t, &langeco.Package{Name: "mygem", Version: "0.1.0"}, pkg)
+	require.Equal(t, &langeco.Dependencies{
+		{Name: "nokogiri", Version: "1.15.4", Eco: parser.GEMS},
+		{Name: "rack", Version: "2.2.8", Eco: parser.GEMS},
+	}, deps)
+}
@@ -1,7 +1,81 @@
 package swift
 
-import "github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
 
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+)
+
+var (
+	ErrDecodingFailed = errors.New("decoding Package.resolved failed")
+)
+
+type pin struct {
+	// version 1
+	Package       string `json:"package"`
+	RepositoryURL string `json:"repositoryURL"`
+	// version 2 and 3
+	Identity string `json:"identity"`
+	Location string `json:"location"`
+	State    struct {
+		Version  string `json:"version"`
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+	} `json:"state"`
+}
+
+type resolved struct {
+	Version int `json:"version"`
+	Object  struct {
+		Pins []pin `json:"pins"`
+	} `json:"object"`
+	Pins []pin `json:"pins"`
+}
+
+// Parse parses Package.resolved. Swift packages are named by their
+// repositories without the scheme, e.g. github.com/apple/swift-nio. The
+// file does not have the root package, so the returned package is empty.
 func Parse(contents string) (*langeco.Package, *langeco.Dependencies, error) {
-	return &langeco.Package{}, &langeco.Dependencies{}, nil
+	var r resolved
+	if err := json.Unmarshal([]byte(contents), &r); err != nil {
+		return nil, nil, ErrDecodingFailed
+	}
+	pins := r.Pins
+	if r.Version == 1 {
+		pins = r.Object.Pins
+	}
+
+	deps := make(langeco.Dependencies, 0, len(pins))
+	for _, p := range pins {
+		location := p.Location
+		if location == "" {
+			location = p.RepositoryURL
+		}
+		name := repoName(location)
+		if name == "" {
+			name = p.Identity
+		}
+		if name == "" {
+			name = p.Package
+		}
+		version := p.State.Version
+		if version == "" {
+			version = p.State.Revision
+		}
+		deps = append(deps, langeco.Package{Name: name, Version: version, Eco: parser.SWIFT})
+	}
+	return &langeco.Package{}, &deps, nil
+}
+
+func repoName(location string) string {
+	if i := strings.Index(location, "://"); i >= 0 {
+		location = location[i+3:]
+	} else if rest, ok := strings.CutPrefix(location, "git@"); ok {
+		// scp-like syntax, git@github.com:apple/swift-nio.git
+		location = strings.Replace(rest, ":", "/", 1)
+	}
+	return strings.TrimSuffix(location, ".git")
 }
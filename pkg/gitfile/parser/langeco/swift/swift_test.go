@@ -1 +1,49 @@
 package swift
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "version 1",
+			content: `{
+  "object": {
+    "pins": [
+      {"package": "swift-nio", "repositoryURL": "https://github.com/apple/swift-nio.git", "state": {"branch": null, "revision": "abc", "version": "2.58.0"}},
+      {"package": "Alamofire", "repositoryURL": "git@github.com:Alamofire/Alamofire.git", "state": {"branch": "main", "revision": "def", "version": null}}
+    ]
+  },
+  "version": 1
+}`,
+		},
+		{
+			name: "version 2",
+			content: `{
+  "pins": [
+    {"identity": "swift-nio", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-nio.git", "state": {"revision": "abc", "version": "2.58.0"}},
+    {"identity": "alamofire", "kind": "remoteSourceControl", "location": "git@github.com:Alamofire/Alamofire.git", "state": {"branch": "main", "revision": "def"}}
+  ],
+  "version": 2
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, deps, err := Parse(tt.content)
+			require.NoError(t, err)
+			require.Equal(t, &langeco.Dependencies{
+				{Name: "github.com/apple/swift-nio", Version: "2.58.0", Eco: parser.SWIFT},
+				{Name: "github.com/Alamofire/Alamofire", Version: "def", Eco: parser.SWIFT},
+			}, deps)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package parser
+
+// license files are the files at the root whose uppercase base name is one
+// of these, optionally followed by an extension or a suffix like -MIT
+var LICENSE_FILE_PREFIXES = []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"}
+
+// LICENSES_DIR holds one file per license named by its SPDX identifier,
+// see https://reuse.software/spec/
+const LICENSES_DIR string = "LICENSES"
+
+// LICENSE_MANIFESTS are the manifests at the root with a license field
+var LICENSE_MANIFESTS = map[string]bool{
+	"package.json":   true,
+	"Cargo.toml":     true,
+	"pom.xml":        true,
+	"pyproject.toml": true,
+}
+
+// SPDX-License-Identifier headers are read from the first
+// LICENSE_HEADER_BYTES bytes of at most LICENSE_HEADER_FILES source files
+const (
+	LICENSE_HEADER_FILES int = 2000
+	LICENSE_HEADER_BYTES int = 4096
+)
+
+// directories of vendored third-party code, whose licenses are not the
+// licenses of the repository
+var VENDORED_DIRS = map[string]bool{
+	"vendor":           true,
+	"vendors":          true,
+	"third_party":      true,
+	"third-party":      true,
+	"thirdparty":       true,
+	"3rdparty":         true,
+	"node_modules":     true,
+	"bower_components": true,
+}
+
+// deprecated SPDX identifiers, and identifiers of licensecheck which are
+// not SPDX identifiers
+var DEPRECATED_LICENSE_IDS = map[string]string{
+	"GPL-1.0":              "GPL-1.0-only",
+	"GPL-1.0+":             "GPL-1.0-or-later",
+	"GPL-2.0":              "GPL-2.0-only",
+	"GPL-2.0+":             "GPL-2.0-or-later",
+	"GPL-3.0":              "GPL-3.0-only",
+	"GPL-3.0+":             "GPL-3.0-or-later",
+	"LGPL-2.0":             "LGPL-2.0-only",
+	"LGPL-2.0+":            "LGPL-2.0-or-later",
+	"LGPL-2.1":             "LGPL-2.1-only",
+	"LGPL-2.1+":            "LGPL-2.1-or-later",
+	"LGPL-3.0":             "LGPL-3.0-only",
+	"LGPL-3.0+":            "LGPL-3.0-or-later",
+	"AGPL-1.0":             "AGPL-1.0-only",
+	"AGPL-3.0":             "AGPL-3.0-only",
+	"AGPL-3.0+":            "AGPL-3.0-or-later",
+	"GFDL-1.1":             "GFDL-1.1-only",
+	"GFDL-1.2":             "GFDL-1.2-only",
+	"GFDL-1.3":             "GFDL-1.3-only",
+	"GPL-2.0-or-3.0":       "GPL-2.0-only OR GPL-3.0-only",
+	"BSD-2-Clause-FreeBSD": "BSD-2-Clause",
+	"BSD-2-Clause-NetBSD":  "BSD-2-Clause",
+}
+
+// SPDX identifiers of license names in manifests, by the name in
+// lowercase, with "the", "license" and "version" removed and punctuation
+// replaced by spaces
+var LICENSE_ALIASES = map[string]string{
+	"mit":                 "MIT",
+	"expat":               "MIT",
+	"apache 2":            "Apache-2.0",
+	"apache 2 0":          "Apache-2.0",
+	"apache2":             "Apache-2.0",
+	"apache software 2 0": "Apache-2.0",
+	"asl 2 0":             "Apache-2.0",
+	// the trove classifier, used by Apache-2.0 projects
+	"apache software":               "Apache-2.0",
+	"bsd 2 clause":                  "BSD-2-Clause",
+	"simplified bsd":                "BSD-2-Clause",
+	"freebsd":                       "BSD-2-Clause",
+	"bsd 3 clause":                  "BSD-3-Clause",
+	"new bsd":                       "BSD-3-Clause",
+	"modified bsd":                  "BSD-3-Clause",
+	"revised bsd":                   "BSD-3-Clause",
+	"gplv2":                         "GPL-2.0-only",
+	"gpl 2":                         "GPL-2.0-only",
+	"gnu general public 2":          "GPL-2.0-only",
+	"gplv2+":                        "GPL-2.0-or-later",
+	"gnu general public 2 or later": "GPL-2.0-or-later",
+	"gplv3":                         "GPL-3.0-only",
+	"gpl 3":                         "GPL-3.0-only",
+	"gnu general public 3":          "GPL-3.0-only",
+	"gplv3+":                        "GPL-3.0-or-later",
+	"gnu general public 3 or later": "GPL-3.0-or-later",
+	"lgplv2":                        "LGPL-2.0-only",
+	"lgpl 2 1":                      "LGPL-2.1-only",
+	"lgplv2 1":                      "LGPL-2.1-only",
+	"gnu lesser general public 2 1": "LGPL-2.1-only",
+	"lgplv3":                        "LGPL-3.0-only",
+	"lgpl 3":                        "LGPL-3.0-only",
+	"gnu lesser general public 3":   "LGPL-3.0-only",
+	"agplv3":                        "AGPL-3.0-only",
+	"agpl 3":                        "AGPL-3.0-only",
+	"gnu affero general public 3":   "AGPL-3.0-only",
+	"mpl 2":                         "MPL-2.0",
+	"mpl 2 0":                       "MPL-2.0",
+	"mozilla public 2 0":            "MPL-2.0",
+	"epl 1 0":                       "EPL-1.0",
+	"eclipse public 1 0":            "EPL-1.0",
+	"epl 2 0":                       "EPL-2.0",
+	"eclipse public 2 0":            "EPL-2.0",
+	"isc":                           "ISC",
+	"unlicense":                     "Unlicense",
+	"cc0":                           "CC0-1.0",
+	"cc0 1 0":                       "CC0-1.0",
+	"cc0 1 0 universal":             "CC0-1.0",
+	"boost software 1 0":            "BSL-1.0",
+	"boost":                         "BSL-1.0",
+	"zlib":                          "Zlib",
+	"zlib libpng":                   "Zlib",
+	"python software foundation":    "PSF-2.0",
+	"psf":                           "PSF-2.0",
+	"artistic 2 0":                  "Artistic-2.0",
+	"eupl 1 2":                      "EUPL-1.2",
+	"european union public 1 2":     "EUPL-1.2",
+	"cddl 1 0":                      "CDDL-1.0",
+	"wtfpl":                         "WTFPL",
+	"postgresql":                    "PostgreSQL",
+}
@@ -0,0 +1,47 @@
+package parser
+
+import "strings"
+
+// NATIVE is the ecosystem of the dependencies of C/C++ build systems on
+// system libraries, which are not packages of a registry. Their names are
+// prefixed by the kind of name, see NativeName.
+const NATIVE = "native"
+
+// kinds of native dependency names
+const (
+	// module of pkg-config, e.g. glib-2.0
+	NATIVE_PKGCONFIG = "pkgconfig"
+	// package of CMake find_package, e.g. ZLIB
+	NATIVE_CMAKE = "cmake"
+	// library linked with -l, e.g. z
+	NATIVE_LIB = "lib"
+	// port of vcpkg, e.g. zlib
+	NATIVE_VCPKG = "vcpkg"
+	// repository fetched at build time, by its URL
+	NATIVE_GIT = "git"
+)
+
+// NativeName returns the name of a native dependency of the kind, e.g.
+// pkgconfig:glib-2.0.
+func NativeName(kind, name string) string {
+	return kind + ":" + name
+}
+
+// SplitNativeName returns the kind and the name of a native dependency
+// name, or false if it has no kind.
+func SplitNativeName(s string) (string, string, bool) {
+	return strings.Cut(s, ":")
+}
+
+// libraries of the C library, which are not dependencies on other
+// projects
+var NATIVE_SYSTEM_LIBS = map[string]bool{
+	"c":       true,
+	"m":       true,
+	"dl":      true,
+	"rt":      true,
+	"pthread": true,
+	"util":    true,
+	"resolv":  true,
+	"crypt":   true,
+}
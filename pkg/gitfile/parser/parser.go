@@ -24,6 +24,14 @@ const (
 	LANGUAGE_THRESHOLD  int = 0
 	ECOSYSTEM_THRESHOLD int = 0
 	TOP_N               int = 5
+
+	// commits of the last BUS_FACTOR_MONTHS months are counted for the bus
+	// factor and the top author share
+	BUS_FACTOR_MONTHS   int     = 24
+	BUS_FACTOR_COVERAGE float64 = 0.5
+	// authors with at least this many commits in the last year are active
+	// maintainers
+	ACTIVE_MAINTAINER_COMMITS int = 3
 )
 
 var (
@@ -34,14 +42,9 @@ var (
 	NOW          = time.Now()
 	LAST_YEAR    = NOW.AddDate(-1, 0, 0)
 	LAST_90_DAYS = NOW.AddDate(0, 0, -90)
-)
 
-// ToDo Try to cover more
-var LICENSE_FILENAMES = map[string]bool{
-	"LICENSE":     true,
-	"LICENSE.md":  true,
-	"LICENSE.txt": true,
-}
+	BUS_FACTOR_SINCE = NOW.AddDate(0, -BUS_FACTOR_MONTHS, 0)
+)
 
 // * https://github.com/github-linguist/linguist/blob/master/lib/linguist/languages.yml
 var LANGUAGE_EXTENSIONS = map[string]string{
@@ -1493,11 +1496,13 @@ const (
 	NUGET    = "nuget"
 	COMPOSER = "COMPOSER"
 	BUNDLER  = "bundler"
+	SWIFT    = "swift"
 	REBAR	 = "rebar"
 )
 
 var ECOSYSTEM_MAP = map[string]string{
 	"conanfile.py": CONAN,
+	"conan.lock":   CONAN,
 	//* "environment.yaml":  "conda",
 	//* "environment.yml":   "conda",
 	//* packagename.json: "conda"
@@ -1512,6 +1517,7 @@ var ECOSYSTEM_MAP = map[string]string{
 	".npmrc":                   NPM,
 	"node_modules":             NPM,
 	"yarn.lock":                YARN,
+	"pnpm-lock.yaml":           NPM,
 	"packages.config":          NUGET,
 	"package.lock.json":        NUGET,
 	"Directory.Build.props":    NUGET,
@@ -1523,6 +1529,7 @@ var ECOSYSTEM_MAP = map[string]string{
 	"setup.py":                 PYPI,
 	"Pipfile":                  PYPI,
 	"Pipfile.lock":             PYPI,
+	"poetry.lock":              PYPI,
 	"pyproject.toml":           PYPI,
 	"requirements.txt":         PYPI,
 	"Cargo.toml":               CARGO,
@@ -1537,4 +1544,10 @@ var ECOSYSTEM_MAP = map[string]string{
 	"rebar.config":             REBAR,
 	"rebar.lock":               REBAR,
 	"rebar.config.lock":        REBAR,
+	"Package.swift":            SWIFT,
+	"Package.resolved":         SWIFT,
+	"CMakeLists.txt":           NATIVE,
+	"meson.build":              NATIVE,
+	"configure.ac":             NATIVE,
+	"vcpkg.json":               NATIVE,
 }
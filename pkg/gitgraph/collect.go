@@ -0,0 +1,130 @@
+package gitgraph
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/samber/lo"
+)
+
+const insertBatchSize = 1000
+
+// Load reads the manifests of the latest collected commit of every git
+// link, records the packages they declare in their directories into the
+// resolver, and returns their dependencies of lang ecosystems and their
+// native dependencies. Other ecosystems are skipped.
+func Load(ac storage.AppDatabaseContext, r *Resolver) ([]Dependency, []NativeDependency, error) {
+	repo := repository.NewGitManifestRepository(ac)
+
+	manifests, err := repo.QueryLatest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query manifests: %w", err)
+	}
+	type manifest struct {
+		link string
+		dir  string
+		eco  string
+	}
+	byID := make(map[int64]manifest)
+	for m := range manifests {
+		mf := manifest{link: *m.GitLink, dir: path.Dir(*m.ManifestPath), eco: lo.FromPtr(lo.FromPtr(m.Ecosystem))}
+		byID[*m.ID] = mf
+		if t, ok := EcosystemType(mf.eco); ok && m.Name != nil && *m.Name != nil {
+			r.Declare(t, **m.Name, mf.link, mf.dir)
+		}
+	}
+
+	deps, err := repo.QueryLatestDependencies()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	ret := make([]Dependency, 0)
+	native := make([]NativeDependency, 0)
+	for d := range deps {
+		mf, ok := byID[*d.ManifestID]
+		if !ok || d.Name == nil || *d.Name == nil {
+			continue
+		}
+		eco := lo.FromPtr(lo.FromPtr(d.Ecosystem))
+		if eco == "" {
+			eco = mf.eco
+		}
+		if eco == parser.NATIVE {
+			native = append(native, NativeDependency{GitLink: mf.link, Name: **d.Name})
+			continue
+		}
+		t, ok := EcosystemType(eco)
+		if !ok {
+			continue
+		}
+		ret = append(ret, Dependency{GitLink: mf.link, Dir: mf.dir, Type: t, Name: **d.Name})
+	}
+	logger.Infof("%d dependencies and %d native dependencies of %d manifests loaded", len(ret), len(native), len(byID))
+	return ret, native, nil
+}
+
+// Collect writes the graph of every lang ecosystem of types into
+// git_relationships, replacing the edges of the last run, and writes the
+// metrics of every git link in it, summed up over its packages, into
+// lang_ecosystems. Links the collectors of the registry already wrote are
+// skipped, as their metrics are relative to the whole registry rather
+// than to the graph.
+func Collect(ac storage.AppDatabaseContext, graphs map[repository.LangEcosystemType][]*langcollector.Package, types []repository.LangEcosystemType) error {
+	relRepo := repository.NewGitRelationshipRepository(ac)
+	langRepo := repository.NewLangEcoLinkRepository(ac)
+
+	for _, t := range types {
+		pkgs := graphs[t]
+		edges := Edges(pkgs)
+		if err := relRepo.ReplaceBySource(t.String(), edges); err != nil {
+			return fmt.Errorf("failed to update %s relationships: %w", t, err)
+		}
+		if len(edges) == 0 {
+			logger.Infof("No %s relationships resolved", t)
+			continue
+		}
+
+		rows, err := withoutRegistryLinks(langRepo, t, langcollector.Aggregate(t, pkgs, langcollector.Analyze(pkgs)))
+		if err != nil {
+			return fmt.Errorf("failed to query %s registry links: %w", t, err)
+		}
+		for _, chunk := range lo.Chunk(rows, insertBatchSize) {
+			if err := langRepo.BatchInsertOrUpdate(chunk); err != nil {
+				return fmt.Errorf("failed to update %s: %w", t, err)
+			}
+		}
+		logger.Infof("%d %s relationships between %d packages updated", len(edges), t, len(pkgs))
+	}
+	return nil
+}
+
+// withoutRegistryLinks drops the rows of links the collectors of the
+// registry of t own, and marks the others as written by the graph.
+func withoutRegistryLinks(repo repository.LangEcoLinkRepository, t repository.LangEcosystemType, rows []*repository.LangEcosystem) ([]*repository.LangEcosystem, error) {
+	links, err := repo.QueryRegistryLinks(t)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool)
+	for link := range links {
+		owned[link] = true
+	}
+	ret := make([]*repository.LangEcosystem, 0, len(rows))
+	for _, row := range rows {
+		if owned[*row.GitLink] {
+			continue
+		}
+		row.Source = sqlutil.ToNullable(repository.LangEcosystemSourceGitGraph)
+		ret = append(ret, row)
+	}
+	if skipped := len(rows) - len(ret); skipped > 0 {
+		logger.Infof("%d %s git links skipped, as they are collected from the registry", skipped, t)
+	}
+	return ret, nil
+}
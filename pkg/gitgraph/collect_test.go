@@ -0,0 +1,47 @@
+package gitgraph
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// registryLinks is a lang_ecosystems table only knowing the links written
+// by the collectors of registries
+type registryLinks struct {
+	repository.LangEcoLinkRepository
+	links map[repository.LangEcosystemType][]string
+}
+
+func (r *registryLinks) QueryRegistryLinks(typ repository.LangEcosystemType) (iter.Seq[string], error) {
+	return slices.Values(r.links[typ]), nil
+}
+
+func TestWithoutRegistryLinks(t *testing.T) {
+	repo := &registryLinks{links: map[repository.LangEcosystemType][]string{
+		repository.RubyGems: {"https://github.com/rails/rails"},
+		repository.Npm:      {"https://github.com/a/only-in-npm"},
+	}}
+	rows := []*repository.LangEcosystem{
+		{GitLink: lo.ToPtr("https://github.com/rails/rails"), Type: lo.ToPtr(repository.RubyGems)},
+		{GitLink: lo.ToPtr("https://github.com/a/only-in-npm"), Type: lo.ToPtr(repository.RubyGems)},
+		{GitLink: lo.ToPtr("https://github.com/a/unpublished"), Type: lo.ToPtr(repository.RubyGems)},
+	}
+
+	got, err := withoutRegistryLinks(repo, repository.RubyGems, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := lo.Map(got, func(row *repository.LangEcosystem, _ int) string { return *row.GitLink })
+	if want := []string{"https://github.com/a/only-in-npm", "https://github.com/a/unpublished"}; !slices.Equal(links, want) {
+		t.Errorf("Expected %v, got %v", want, links)
+	}
+	for _, row := range got {
+		if lo.FromPtr(lo.FromPtr(row.Source)) != repository.LangEcosystemSourceGitGraph {
+			t.Errorf("Expected source of %s to be %s", *row.GitLink, repository.LangEcosystemSourceGitGraph)
+		}
+	}
+}
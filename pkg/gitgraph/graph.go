@@ -0,0 +1,252 @@
+// Package gitgraph builds the dependency graph between git links from the
+// manifests collected from the repositories, instead of the dependency
+// graphs of deps.dev or the language registries.
+//
+// Every dependency declared by a manifest is resolved to the git link
+// which publishes the package, by the packages the repositories declare
+// themselves and the registry links listed by the link enumerators. The
+// edges are written into git_relationships with the lang ecosystem as the
+// source, and the dependents and pagerank of every git link in the graph
+// of each lang ecosystem are written into lang_ecosystems.
+package gitgraph
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// lang ecosystems of the ecosystems set by the manifest parsers
+var ecosystemTypes = map[string]repository.LangEcosystemType{
+	parser.NPM:      repository.Npm,
+	parser.GO:       repository.Go,
+	parser.MAVEN:    repository.Maven,
+	parser.PYPI:     repository.Pypi,
+	parser.NUGET:    repository.NuGet,
+	parser.DOTNET:   repository.NuGet,
+	parser.CARGO:    repository.Cargo,
+	parser.GEMS:     repository.RubyGems,
+	parser.COMPOSER: repository.Packagist,
+	parser.ELIXIR:   repository.Hex,
+	parser.REBAR:    repository.Hex,
+	parser.CONAN:    repository.Conan,
+	parser.CONDA:    repository.Conda,
+	parser.SWIFT:    repository.Swift,
+}
+
+// EcosystemType returns the lang ecosystem of an ecosystem set by the
+// manifest parsers, and false if it is not a lang ecosystem.
+func EcosystemType(eco string) (repository.LangEcosystemType, bool) {
+	t, ok := ecosystemTypes[eco]
+	return t, ok
+}
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName returns the name packages are compared by. Registries
+// are case insensitive in practice, and PyPI also treats runs of -, _
+// and . the same (PEP 503).
+func normalizeName(t repository.LangEcosystemType, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if t == repository.Pypi {
+		name = pypiSeparators.ReplaceAllString(name, "-")
+	}
+	return name
+}
+
+type packageKey struct {
+	typ  repository.LangEcosystemType
+	name string
+}
+
+// declaration is a package declared by the manifest in dir of the
+// repository of link.
+type declaration struct {
+	link string
+	dir  string
+}
+
+// Resolver resolves packages of lang ecosystems to the git links
+// publishing them.
+type Resolver struct {
+	declared map[packageKey][]declaration
+	registry map[packageKey]string
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{
+		declared: make(map[packageKey][]declaration),
+		registry: make(map[packageKey]string),
+	}
+}
+
+// Declare records that the repository of link declares the package in one
+// of its manifests, in the directory dir. Packages of monorepos are
+// declared in the subdirectories they are published from, the first one
+// is kept if several manifests of a repository declare the package.
+func (r *Resolver) Declare(t repository.LangEcosystemType, name, link, dir string) {
+	k := packageKey{t, normalizeName(t, name)}
+	if k.name == "" || link == "" || slices.ContainsFunc(r.declared[k], func(d declaration) bool { return d.link == link }) {
+		return
+	}
+	r.declared[k] = append(r.declared[k], declaration{link, dir})
+}
+
+// AddRegistryLink records the git link a registry lists for the package.
+func (r *Resolver) AddRegistryLink(t repository.LangEcosystemType, name, link string) {
+	k := packageKey{t, normalizeName(t, name)}
+	if k.name == "" || link == "" {
+		return
+	}
+	r.registry[k] = link
+}
+
+// Resolve returns the git link publishing the package, or an empty string
+// if it is unknown.
+//
+// Go modules and Swift packages are named by their repositories. Other
+// packages are resolved to the only repository declaring them. If none
+// or several repositories declare a package, e.g. forks, the registry
+// link decides.
+func (r *Resolver) Resolve(t repository.LangEcosystemType, name string) string {
+	link, _ := r.resolve(t, name)
+	return link
+}
+
+// resolve returns the git link publishing the package, and the directory
+// of the repository the package is declared in, empty if the repository
+// does not declare it.
+func (r *Resolver) resolve(t repository.LangEcosystemType, name string) (string, string) {
+	k := packageKey{t, normalizeName(t, name)}
+	declared := r.declared[k]
+	declaredBy := func(link string) (string, bool) {
+		for _, d := range declared {
+			if d.link == link {
+				return d.dir, true
+			}
+		}
+		return "", false
+	}
+
+	if t == repository.Go || t == repository.Swift {
+		if link := langcollector.NormalizeGitLink(name); link != "" {
+			dir, _ := declaredBy(link)
+			return link, dir
+		}
+	}
+	if len(declared) == 1 {
+		return declared[0].link, declared[0].dir
+	}
+	link, ok := r.registry[k]
+	if !ok {
+		return "", ""
+	}
+	if len(declared) == 0 {
+		return link, ""
+	}
+	if dir, ok := declaredBy(link); ok {
+		return link, dir
+	}
+	return "", ""
+}
+
+// Dependency is a package the manifest in Dir of the repository of GitLink
+// depends on.
+type Dependency struct {
+	GitLink string
+	Dir     string
+	Type    repository.LangEcosystemType
+	Name    string
+}
+
+// NativeDependency is a native dependency of the repository of GitLink,
+// named as by parser.NativeName.
+type NativeDependency struct {
+	GitLink string
+	Name    string
+}
+
+// nodeName returns the name of the node of the package in the directory
+// of the repository of link, which is the link itself at the root.
+func nodeName(link, dir string) string {
+	if dir == "" || dir == "." {
+		return link
+	}
+	return link + "/" + dir
+}
+
+// Build resolves the dependencies and returns the graph of every lang
+// ecosystem. Every package a repository publishes is a node named by its
+// git link and its directory, see nodeName, which depends on the packages
+// its dependencies resolve to. The metrics of the nodes are summed up by
+// their git links, see langcollector.Aggregate, so packages of monorepos
+// are attributed to the repository. Unresolved dependencies and
+// dependencies between the packages of a repository are dropped, but the
+// packages are still nodes of the graph.
+func Build(r *Resolver, deps []Dependency) map[repository.LangEcosystemType][]*langcollector.Package {
+	nodes := make(map[repository.LangEcosystemType]map[string]*langcollector.Package)
+	node := func(t repository.LangEcosystemType, link, dir string) *langcollector.Package {
+		if nodes[t] == nil {
+			nodes[t] = make(map[string]*langcollector.Package)
+		}
+		name := nodeName(link, dir)
+		n, ok := nodes[t][name]
+		if !ok {
+			n = &langcollector.Package{Name: name, GitLink: link}
+			nodes[t][name] = n
+		}
+		return n
+	}
+
+	for _, d := range deps {
+		from := node(d.Type, d.GitLink, d.Dir)
+		link, dir := r.resolve(d.Type, d.Name)
+		if link == "" || link == d.GitLink {
+			continue
+		}
+		to := node(d.Type, link, dir)
+		if !slices.Contains(from.Depends, to.Name) {
+			from.Depends = append(from.Depends, to.Name)
+		}
+	}
+
+	ret := make(map[repository.LangEcosystemType][]*langcollector.Package, len(nodes))
+	for t, m := range nodes {
+		pkgs := lo.Values(m)
+		slices.SortFunc(pkgs, func(a, b *langcollector.Package) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		ret[t] = pkgs
+	}
+	return ret
+}
+
+// Edges returns the relationships between the git links of the graph.
+func Edges(pkgs []*langcollector.Package) []*repository.GitRelationship {
+	links := make(map[string]string, len(pkgs))
+	for _, pkg := range pkgs {
+		links[pkg.Name] = pkg.GitLink
+	}
+	type edge struct{ from, to string }
+	seen := make(map[edge]bool)
+	ret := make([]*repository.GitRelationship, 0)
+	for _, pkg := range pkgs {
+		for _, dep := range pkg.Depends {
+			e := edge{pkg.GitLink, links[dep]}
+			if e.to == "" || seen[e] {
+				continue
+			}
+			seen[e] = true
+			ret = append(ret, &repository.GitRelationship{
+				Fromgitlink: lo.ToPtr(e.from),
+				Togitlink:   lo.ToPtr(e.to),
+			})
+		}
+	}
+	return ret
+}
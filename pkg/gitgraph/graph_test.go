@@ -0,0 +1,144 @@
+package gitgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+func TestResolve(t *testing.T) {
+	r := NewResolver()
+	r.Declare(repository.Pypi, "Zope.Interface", "https://github.com/zopefoundation/zope.interface", ".")
+	r.Declare(repository.Npm, "left-pad", "https://github.com/a/left-pad", ".")
+	r.Declare(repository.Npm, "left-pad", "https://github.com/b/left-pad", ".")
+	r.Declare(repository.Npm, "forked", "https://github.com/a/forked", ".")
+	r.Declare(repository.Npm, "forked", "https://github.com/b/forked", ".")
+	r.AddRegistryLink(repository.Npm, "left-pad", "https://github.com/b/left-pad")
+	r.AddRegistryLink(repository.Npm, "forked", "https://github.com/c/forked")
+	r.AddRegistryLink(repository.Cargo, "serde", "https://github.com/serde-rs/serde")
+
+	tests := []struct {
+		typ  repository.LangEcosystemType
+		name string
+		want string
+	}{
+		{repository.Pypi, "zope_interface", "https://github.com/zopefoundation/zope.interface"},
+		// the registry link decides among repositories declaring it
+		{repository.Npm, "left-pad", "https://github.com/b/left-pad"},
+		// unless it is none of them
+		{repository.Npm, "forked", ""},
+		{repository.Cargo, "Serde", "https://github.com/serde-rs/serde"},
+		{repository.Go, "github.com/spf13/pflag/v2", "https://github.com/spf13/pflag"},
+		{repository.Swift, "github.com/apple/swift-nio", "https://github.com/apple/swift-nio"},
+		{repository.Npm, "github.com/apple/swift-nio", ""},
+		{repository.Maven, "unknown", ""},
+	}
+	for _, tt := range tests {
+		if got := r.Resolve(tt.typ, tt.name); got != tt.want {
+			t.Errorf("Resolve(%s, %s) = %q, want %q", tt.typ, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := NewResolver()
+	r.Declare(repository.Npm, "a", "https://github.com/o/a", ".")
+	r.Declare(repository.Npm, "b", "https://github.com/o/b", ".")
+	r.AddRegistryLink(repository.Npm, "c", "https://github.com/o/c")
+
+	deps := []Dependency{
+		{GitLink: "https://github.com/o/a", Type: repository.Npm, Name: "b"},
+		{GitLink: "https://github.com/o/a", Type: repository.Npm, Name: "c"},
+		// duplicated, self and unresolved dependencies are dropped
+		{GitLink: "https://github.com/o/a", Type: repository.Npm, Name: "b"},
+		{GitLink: "https://github.com/o/a", Type: repository.Npm, Name: "a"},
+		{GitLink: "https://github.com/o/b", Type: repository.Npm, Name: "unknown"},
+		{GitLink: "https://github.com/o/b", Type: repository.Cargo, Name: "c"},
+	}
+	graphs := Build(r, deps)
+
+	npm := graphs[repository.Npm]
+	if len(npm) != 3 {
+		t.Fatalf("Expected 3 npm git links, got %d", len(npm))
+	}
+	if npm[0].Name != "https://github.com/o/a" || strings.Join(npm[0].Depends, ",") != "https://github.com/o/b,https://github.com/o/c" {
+		t.Errorf("Wrong dependencies of %s: %v", npm[0].Name, npm[0].Depends)
+	}
+	if len(npm[1].Depends) != 0 || len(npm[2].Depends) != 0 {
+		t.Errorf("Expected no dependencies of b and c, got %v and %v", npm[1].Depends, npm[2].Depends)
+	}
+	if cargo := graphs[repository.Cargo]; len(cargo) != 1 || len(cargo[0].Depends) != 0 {
+		t.Errorf("Expected a single cargo git link without dependencies, got %v", cargo)
+	}
+
+	edges := Edges(npm)
+	if len(edges) != 2 || *edges[0].Fromgitlink != "https://github.com/o/a" || *edges[1].Togitlink != "https://github.com/o/c" {
+		t.Errorf("Wrong edges: %d", len(edges))
+	}
+}
+
+func TestBuildMonorepo(t *testing.T) {
+	r := NewResolver()
+	r.Declare(repository.Npm, "@o/core", "https://github.com/o/mono", "packages/core")
+	r.Declare(repository.Npm, "@o/cli", "https://github.com/o/mono", "packages/cli")
+	r.Declare(repository.Go, "github.com/o/mono/sdk", "https://github.com/o/mono", "sdk")
+
+	deps := []Dependency{
+		{GitLink: "https://github.com/o/a", Dir: ".", Type: repository.Npm, Name: "@o/core"},
+		{GitLink: "https://github.com/o/a", Dir: ".", Type: repository.Npm, Name: "@o/cli"},
+		{GitLink: "https://github.com/o/b", Dir: "web", Type: repository.Npm, Name: "@o/core"},
+		// dependencies between the packages of the monorepo are dropped
+		{GitLink: "https://github.com/o/mono", Dir: "packages/cli", Type: repository.Npm, Name: "@o/core"},
+		{GitLink: "https://github.com/o/a", Dir: ".", Type: repository.Go, Name: "github.com/o/mono/sdk"},
+	}
+	graphs := Build(r, deps)
+
+	npm := graphs[repository.Npm]
+	names := make([]string, 0, len(npm))
+	for _, pkg := range npm {
+		names = append(names, pkg.Name)
+	}
+	want := "https://github.com/o/a,https://github.com/o/b/web,https://github.com/o/mono/packages/cli,https://github.com/o/mono/packages/core"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("Wrong npm packages: %v", names)
+	}
+	if strings.Join(npm[0].Depends, ",") != "https://github.com/o/mono/packages/core,https://github.com/o/mono/packages/cli" {
+		t.Errorf("Wrong dependencies of %s: %v", npm[0].Name, npm[0].Depends)
+	}
+	if len(npm[2].Depends) != 0 || npm[2].GitLink != "https://github.com/o/mono" {
+		t.Errorf("Wrong package %s: %v", npm[2].Name, npm[2].Depends)
+	}
+	if golang := graphs[repository.Go]; len(golang) != 2 || golang[1].Name != "https://github.com/o/mono/sdk" {
+		t.Errorf("Wrong go packages: %v", golang)
+	}
+
+	// the packages are summed up by their git links
+	rows := langcollector.Aggregate(repository.Npm, npm, langcollector.Analyze(npm))
+	for _, row := range rows {
+		if *row.GitLink == "https://github.com/o/mono" && *row.DepCount != 3 {
+			t.Errorf("Expected 3 dependents of the monorepo, got %d", *row.DepCount)
+		}
+	}
+
+	// edges are between git links
+	edges := Edges(npm)
+	if len(edges) != 2 {
+		t.Errorf("Expected 2 edges, got %d", len(edges))
+	}
+}
+
+func TestReadRegistryLinks(t *testing.T) {
+	// cargo, and npm without a homepage
+	input := "serde\nhttps://github.com/serde-rs/serde\n1.0.0\n100\n10\n\n\n" +
+		"noop\n\n1.0.0\n1\n1\n\n\n" +
+		"left-pad\n\ngit+https://github.com/stevemao/left-pad.git\n\n\n\n"
+	links, err := ReadRegistryLinks(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links["serde"] != "https://github.com/serde-rs/serde" {
+		t.Errorf("Wrong links: %v", links)
+	}
+}
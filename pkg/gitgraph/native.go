@@ -0,0 +1,162 @@
+package gitgraph
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+// nativeAliases are the distro packages of native dependencies which are
+// not found by their names, by the lowercase native name.
+var nativeAliases = map[string][]string{
+	"cmake:png":            {"libpng", "libpng16"},
+	"cmake:jpeg":           {"libjpeg-turbo", "libjpeg"},
+	"cmake:gtest":          {"googletest", "gtest"},
+	"cmake:gmock":          {"googletest", "gtest"},
+	"cmake:sqlite3":        {"sqlite3", "sqlite"},
+	"cmake:eigen3":         {"eigen3", "eigen"},
+	"cmake:lua":            {"lua5.4", "lua"},
+	"cmake:qt5":            {"qtbase-opensource-src", "qt5-base", "qt5-qtbase"},
+	"cmake:qt6":            {"qt6-base", "qt6-qtbase"},
+	"pkgconfig:libcrypto":  {"openssl"},
+	"pkgconfig:libssl":     {"openssl"},
+	"pkgconfig:gtk+-3.0":   {"gtk+3.0", "gtk3"},
+	"pkgconfig:x11":        {"libx11"},
+	"pkgconfig:libsystemd": {"systemd"},
+	"lib:z":                {"zlib"},
+	"lib:crypto":           {"openssl"},
+	"lib:ssl":              {"openssl"},
+	"lib:bz2":              {"bzip2"},
+	"lib:lzma":             {"xz-utils", "xz"},
+	"lib:ncursesw":         {"ncurses"},
+	"lib:readline":         {"readline"},
+	"vcpkg:gtest":          {"googletest", "gtest"},
+	"vcpkg:qtbase":         {"qtbase-opensource-src", "qt6-base", "qt6-qtbase"},
+}
+
+// API versions of pkg-config modules, e.g. glib-2.0 or libxml-2.0
+var apiVersionRegexp = regexp.MustCompile(`^(.+?)-?(\d+)(\.\d+)*$`)
+
+// nativeCandidates returns the names of the distro packages a native
+// dependency may be packaged as, in order of preference: the aliases,
+// the name, the name without its API version or with its major version,
+// each with and without the lib prefix, and the development packages of
+// Debian and Fedora.
+func nativeCandidates(kind, name string) []string {
+	name = strings.ToLower(name)
+	ret := slices.Clone(nativeAliases[parser.NativeName(kind, name)])
+
+	names := []string{name}
+	if m := apiVersionRegexp.FindStringSubmatch(name); m != nil && len(m[1]) > 2 && kind != parser.NATIVE_LIB {
+		names = append(names, m[1], m[1]+m[2])
+	}
+	for _, n := range names {
+		base := strings.TrimPrefix(n, "lib")
+		ret = append(ret, n, "lib"+base, base)
+	}
+	for _, n := range slices.Clone(ret) {
+		ret = append(ret, n+"-dev", n+"-devel")
+	}
+	return lo.Uniq(lo.Compact(ret))
+}
+
+// NativeResolver resolves the native dependencies of C/C++ build systems,
+// see parser.NativeName, to git links by the distro package tables.
+type NativeResolver struct {
+	packages map[string]string
+}
+
+func NewNativeResolver() *NativeResolver {
+	return &NativeResolver{packages: make(map[string]string)}
+}
+
+// AddDistPackage records the git link of a distro package. The package of
+// the distro added first is kept if several distros have it.
+func (r *NativeResolver) AddDistPackage(name, link string) {
+	name = strings.ToLower(name)
+	if _, ok := r.packages[name]; ok || name == "" || link == "" {
+		return
+	}
+	r.packages[name] = link
+}
+
+// Resolve returns the git link of a native dependency, or an empty string
+// if it is unknown. Repositories fetched at build time are named by their
+// URLs, other dependencies are resolved to the first distro package of
+// their candidates which has a git link.
+func (r *NativeResolver) Resolve(name string) string {
+	kind, name, ok := parser.SplitNativeName(name)
+	if !ok {
+		return ""
+	}
+	if kind == parser.NATIVE_GIT {
+		return langcollector.NormalizeGitLink(name)
+	}
+	for _, c := range nativeCandidates(kind, name) {
+		if link, ok := r.packages[c]; ok {
+			return link
+		}
+	}
+	return ""
+}
+
+// LoadDistPackages records the git links of the packages of the distros,
+// in order of preference.
+func LoadDistPackages(ac storage.AppDatabaseContext, r *NativeResolver, dists []repository.DistPackageTablePrefix) error {
+	for _, dist := range dists {
+		pkgs, err := repository.NewDistPackageRepository(ac, dist).Query()
+		if err != nil {
+			return fmt.Errorf("failed to query %s packages: %w", dist, err)
+		}
+		n := 0
+		for p := range pkgs {
+			if p.Package != nil && p.GitLink != nil {
+				r.AddDistPackage(*p.Package, *p.GitLink)
+				n++
+			}
+		}
+		logger.Infof("%d %s packages with git links loaded", n, dist)
+	}
+	return nil
+}
+
+// BuildNative resolves the native dependencies and returns the edges
+// between the git links. Unresolved dependencies and dependencies on the
+// repository itself are dropped.
+func BuildNative(r *NativeResolver, deps []NativeDependency) []*repository.GitRelationship {
+	type edge struct{ from, to string }
+	seen := make(map[edge]bool)
+	ret := make([]*repository.GitRelationship, 0)
+	for _, d := range deps {
+		to := r.Resolve(d.Name)
+		e := edge{d.GitLink, to}
+		if to == "" || to == d.GitLink || seen[e] {
+			continue
+		}
+		seen[e] = true
+		ret = append(ret, &repository.GitRelationship{
+			Fromgitlink: lo.ToPtr(e.from),
+			Togitlink:   lo.ToPtr(e.to),
+		})
+	}
+	return ret
+}
+
+// CollectNative writes the native edges into git_relationships with the
+// native source, replacing the edges of the last run. They are not a lang
+// ecosystem, so no metrics are written into lang_ecosystems.
+func CollectNative(ac storage.AppDatabaseContext, edges []*repository.GitRelationship) error {
+	if err := repository.NewGitRelationshipRepository(ac).ReplaceBySource(parser.NATIVE, edges); err != nil {
+		return fmt.Errorf("failed to update %s relationships: %w", parser.NATIVE, err)
+	}
+	logger.Infof("%d %s relationships updated", len(edges), parser.NATIVE)
+	return nil
+}
@@ -0,0 +1,57 @@
+package gitgraph
+
+import "testing"
+
+func TestNativeResolve(t *testing.T) {
+	r := NewNativeResolver()
+	// debian first, then fedora
+	r.AddDistPackage("glib2.0", "https://gitlab.gnome.org/GNOME/glib")
+	r.AddDistPackage("zlib", "https://github.com/madler/zlib")
+	r.AddDistPackage("openssl", "https://github.com/openssl/openssl")
+	r.AddDistPackage("libpng1.6", "https://github.com/pnggroup/libpng")
+	r.AddDistPackage("libxml2", "https://gitlab.gnome.org/GNOME/libxml2")
+	r.AddDistPackage("zlib", "https://github.com/fedora/zlib")
+	r.AddDistPackage("glib2", "https://gitlab.gnome.org/GNOME/glib")
+	r.AddDistPackage("libpng", "https://github.com/pnggroup/libpng")
+	r.AddDistPackage("fmt", "https://github.com/fmtlib/fmt")
+	r.AddDistPackage("libuv-devel", "https://github.com/libuv/libuv")
+
+	tests := map[string]string{
+		"pkgconfig:glib-2.0":   "https://gitlab.gnome.org/GNOME/glib",
+		"pkgconfig:zlib":       "https://github.com/madler/zlib",
+		"pkgconfig:libxml-2.0": "https://gitlab.gnome.org/GNOME/libxml2",
+		"pkgconfig:libcrypto":  "https://github.com/openssl/openssl",
+		"pkgconfig:uv":         "https://github.com/libuv/libuv",
+		"cmake:ZLIB":           "https://github.com/madler/zlib",
+		"cmake:OpenSSL":        "https://github.com/openssl/openssl",
+		"cmake:PNG":            "https://github.com/pnggroup/libpng",
+		"cmake:LibXml2":        "https://gitlab.gnome.org/GNOME/libxml2",
+		"lib:z":                "https://github.com/madler/zlib",
+		"lib:xml2":             "https://gitlab.gnome.org/GNOME/libxml2",
+		"vcpkg:fmt":            "https://github.com/fmtlib/fmt",
+		"git:https://github.com/google/googletest.git": "https://github.com/google/googletest",
+		"cmake:Unknown": "",
+		"zlib":          "",
+	}
+	for name, want := range tests {
+		if got := r.Resolve(name); got != want {
+			t.Errorf("Resolve(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuildNative(t *testing.T) {
+	r := NewNativeResolver()
+	r.AddDistPackage("zlib", "https://github.com/madler/zlib")
+
+	edges := BuildNative(r, []NativeDependency{
+		{GitLink: "https://github.com/o/a", Name: "pkgconfig:zlib"},
+		// duplicated, self and unresolved dependencies are dropped
+		{GitLink: "https://github.com/o/a", Name: "cmake:ZLIB"},
+		{GitLink: "https://github.com/madler/zlib", Name: "lib:z"},
+		{GitLink: "https://github.com/o/a", Name: "pkgconfig:unknown"},
+	})
+	if len(edges) != 1 || *edges[0].Fromgitlink != "https://github.com/o/a" || *edges[0].Togitlink != "https://github.com/madler/zlib" {
+		t.Errorf("Wrong edges: %d", len(edges))
+	}
+}
@@ -0,0 +1,47 @@
+package gitgraph
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+)
+
+// ReadRegistryLinks reads the text output of a package link enumerator,
+// e.g. cargo or npm, and returns the git link of every package.
+//
+// The enumerators write the fields of a package line by line, starting
+// with the name, and end every package with an empty line. The first
+// field which is a git link is taken, and packages without one are
+// skipped. An empty field ends a package early, and the fields after it
+// are lost.
+func ReadRegistryLinks(r io.Reader) (map[string]string, error) {
+	ret := make(map[string]string)
+	var record []string
+
+	flush := func() {
+		if len(record) > 1 {
+			for _, field := range record[1:] {
+				if link := langcollector.NormalizeGitLink(field); link != "" {
+					ret[record[0]] = link
+					break
+				}
+			}
+		}
+		record = record[:0]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		record = append(record, line)
+	}
+	flush()
+	return ret, scanner.Err()
+}
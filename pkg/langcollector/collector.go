@@ -0,0 +1,218 @@
+// Package langcollector collects the impact and pagerank of packages in
+// language registries which are not covered by deps.dev, and writes them
+// into lang_ecosystems.
+//
+// Every registry builds the dependency graph of its latest releases. The
+// impact of a package is its number of direct dependents divided by the
+// number of packages in the registry, the same as the distribution
+// collectors, and packages of the same git link are summed up.
+package langcollector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/samber/lo"
+)
+
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 20
+	insertBatchSize    = 1000
+)
+
+// Package is the latest release of a package in a registry.
+type Package struct {
+	Name string
+	// normalized git link, empty if the source is unknown
+	GitLink string
+	// names of the packages it depends on in the same registry
+	Depends []string
+}
+
+// Registry fetches all packages of a language registry.
+type Registry interface {
+	Type() repository.LangEcosystemType
+	Packages() ([]*Package, error)
+}
+
+// Metric is the position of a package in the dependency graph.
+type Metric struct {
+	Dependents int
+	PageRank   float64
+}
+
+// Analyze counts the direct dependents of every package and computes the
+// pagerank of the dependency graph. Dependencies not in the registry are
+// ignored.
+func Analyze(pkgs []*Package) map[string]*Metric {
+	index := make(map[string]int, len(pkgs))
+	for i, pkg := range pkgs {
+		index[pkg.Name] = i
+	}
+
+	edges := make([][]int, len(pkgs))
+	dependents := make([]int, len(pkgs))
+	for i, pkg := range pkgs {
+		seen := make(map[int]bool)
+		for _, dep := range pkg.Depends {
+			j, ok := index[dep]
+			if !ok || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			edges[i] = append(edges[i], j)
+			dependents[j]++
+		}
+	}
+
+	n := float64(len(pkgs))
+	ranks := make([]float64, len(pkgs))
+	for i := range ranks {
+		ranks[i] = 1 / n
+	}
+	for it := 0; it < pageRankIterations; it++ {
+		next := make([]float64, len(pkgs))
+		for i := range next {
+			next[i] = (1 - pageRankDamping) / n
+		}
+		for i, deps := range edges {
+			for _, j := range deps {
+				next[j] += pageRankDamping * ranks[i] / float64(len(deps))
+			}
+		}
+		ranks = next
+	}
+
+	ret := make(map[string]*Metric, len(pkgs))
+	for i, pkg := range pkgs {
+		ret[pkg.Name] = &Metric{Dependents: dependents[i], PageRank: ranks[i]}
+	}
+	return ret
+}
+
+// Aggregate sums up the metrics of packages with the same git link.
+// Packages without a git link are skipped.
+func Aggregate(t repository.LangEcosystemType, pkgs []*Package, metrics map[string]*Metric) []*repository.LangEcosystem {
+	byLink := make(map[string]*repository.LangEcosystem)
+	for _, pkg := range pkgs {
+		m, ok := metrics[pkg.Name]
+		if pkg.GitLink == "" || !ok {
+			continue
+		}
+		impact := float64(m.Dependents) / float64(len(pkgs))
+		le, ok := byLink[pkg.GitLink]
+		if !ok {
+			byLink[pkg.GitLink] = &repository.LangEcosystem{
+				GitLink:           lo.ToPtr(pkg.GitLink),
+				Type:              lo.ToPtr(t),
+				DepCount:          lo.ToPtr(m.Dependents),
+				LangEcoImpact:     lo.ToPtr(impact),
+				Lang_eco_pagerank: lo.ToPtr(m.PageRank),
+			}
+			continue
+		}
+		*le.DepCount += m.Dependents
+		*le.LangEcoImpact += impact
+		*le.Lang_eco_pagerank += m.PageRank
+	}
+	return lo.Values(byLink)
+}
+
+// Collect fetches the packages of the registry and writes the metrics of
+// every git link into lang_ecosystems.
+func Collect(ac storage.AppDatabaseContext, r Registry) error {
+	pkgs, err := r.Packages()
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s packages: %w", r.Type(), err)
+	}
+	if len(pkgs) == 0 {
+		return fmt.Errorf("no %s packages fetched", r.Type())
+	}
+	logger.Infof("%d %s packages fetched", len(pkgs), r.Type())
+
+	rows := Aggregate(r.Type(), pkgs, Analyze(pkgs))
+	repo := repository.NewLangEcoLinkRepository(ac)
+	for _, chunk := range lo.Chunk(rows, insertBatchSize) {
+		if err := repo.BatchInsertOrUpdate(chunk); err != nil {
+			return fmt.Errorf("failed to update %s: %w", r.Type(), err)
+		}
+	}
+	logger.Infof("%d %s git links updated", len(rows), r.Type())
+	return nil
+}
+
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+func get(url string) ([]byte, error) {
+	return getAccept(url, "")
+}
+
+func getAccept(url, accept string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func getJSON(url string, v any) error {
+	return getJSONAccept(url, "", v)
+}
+
+func getJSONAccept(url, accept string, v any) error {
+	body, err := getAccept(url, accept)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// fetchAll calls fetch for every name with the given number of workers,
+// and returns the packages in no particular order. Names fetch fails for
+// are logged and skipped.
+func fetchAll(names []string, workers int, fetch func(name string) (*Package, error)) []*Package {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ret := make([]*Package, 0, len(names))
+	sem := make(chan struct{}, max(workers, 1))
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pkg, err := fetch(name)
+			if err != nil {
+				logger.Warnf("Failed to fetch package %s: %v", name, err)
+				return
+			}
+			if pkg == nil {
+				return
+			}
+			mu.Lock()
+			ret = append(ret, pkg)
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return ret
+}
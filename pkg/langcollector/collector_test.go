@@ -0,0 +1,144 @@
+package langcollector
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+func TestAnalyze(t *testing.T) {
+	pkgs := []*Package{
+		{Name: "a", Depends: []string{"c"}},
+		// duplicated, self and unknown dependencies are ignored
+		{Name: "b", Depends: []string{"c", "c", "b", "x"}},
+		{Name: "c"},
+	}
+	metrics := Analyze(pkgs)
+	if metrics["c"].Dependents != 2 || metrics["a"].Dependents != 0 || metrics["b"].Dependents != 0 {
+		t.Errorf("Wrong dependents: a=%d b=%d c=%d", metrics["a"].Dependents, metrics["b"].Dependents, metrics["c"].Dependents)
+	}
+	if metrics["c"].PageRank <= metrics["a"].PageRank {
+		t.Errorf("Expected c to rank higher than a, got %f <= %f", metrics["c"].PageRank, metrics["a"].PageRank)
+	}
+	if math.Abs(metrics["a"].PageRank-metrics["b"].PageRank) > 1e-9 {
+		t.Errorf("Expected a and b to rank the same, got %f and %f", metrics["a"].PageRank, metrics["b"].PageRank)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	pkgs := []*Package{
+		{Name: "a", GitLink: "https://github.com/o/r"},
+		{Name: "b", GitLink: "https://github.com/o/r"},
+		{Name: "c"},
+		{Name: "d", GitLink: "https://github.com/o/d"},
+	}
+	metrics := map[string]*Metric{
+		"a": {Dependents: 1, PageRank: 0.1},
+		"b": {Dependents: 3, PageRank: 0.2},
+		"c": {Dependents: 5, PageRank: 0.3},
+		"d": {Dependents: 0, PageRank: 0.4},
+	}
+	rows := Aggregate(repository.Hex, pkgs, metrics)
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 git links, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if *row.Type != repository.Hex {
+			t.Errorf("Expected type hex, got %s", *row.Type)
+		}
+		if *row.GitLink != "https://github.com/o/r" {
+			continue
+		}
+		if *row.DepCount != 4 || math.Abs(*row.LangEcoImpact-1.0) > 1e-9 || math.Abs(*row.Lang_eco_pagerank-0.3) > 1e-9 {
+			t.Errorf("Wrong aggregation: count=%d impact=%f pagerank=%f", *row.DepCount, *row.LangEcoImpact, *row.Lang_eco_pagerank)
+		}
+	}
+}
+
+func TestNormalizeGitLink(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://github.com/rails/rails":          "https://github.com/rails/rails",
+		"http://www.GitHub.com/rails/rails.git":   "https://github.com/rails/rails",
+		"git+ssh://git@github.com/a/b.git":        "https://github.com/a/b",
+		"git@gitlab.com:a/b.git":                  "https://gitlab.com/a/b",
+		"github.com/a/b/tree/main/sub":            "https://github.com/a/b",
+		"git://github.com/a/b":                    "https://github.com/a/b",
+		"https://rubyonrails.org":                 "",
+		"https://github.com/a":                    "",
+		"ssh://git@github.com:22/a/b.git":         "https://github.com/a/b",
+		"https://github.com/a/b?tab=readme#usage": "https://github.com/a/b",
+		"": "",
+		" https://codeberg.org/forgejo/forgejo/issues ": "https://codeberg.org/forgejo/forgejo",
+	} {
+		if got := NormalizeGitLink(raw); got != want {
+			t.Errorf("NormalizeGitLink(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestParseCabal(t *testing.T) {
+	pkg := parseCabal(`name:          lens
+homepage:      http://github.com/ekmett/lens/
+-- build-depends: commented
+source-repository head
+  type: git
+  location: https://github.com/ekmett/lens.git
+
+library
+  build-depends:
+      base >= 4.9 && < 5
+    , array >=0.5
+    , containers
+  exposed-modules: Control.Lens
+
+test-suite doctests
+  Build-Depends: base, doctest >= 0.11, lens
+`)
+	if pkg.GitLink != "https://github.com/ekmett/lens" {
+		t.Errorf("Wrong git link %s", pkg.GitLink)
+	}
+	want := []string{"base", "array", "containers", "doctest", "lens"}
+	if !slices.Equal(pkg.Depends, want) {
+		t.Errorf("Expected depends %v, got %v", want, pkg.Depends)
+	}
+}
+
+func TestParseConanfile(t *testing.T) {
+	pkg := parseConanfile(`class OpenSSLConan(ConanFile):
+    name = "openssl"
+    url = "https://github.com/conan-io/conan-center-index"
+    homepage = "https://github.com/openssl/openssl"
+
+    def requirements(self):
+        self.requires("zlib/[>=1.2.11 <2]")
+        self.requires(f"zlib/{self._zlib}")
+        self.requires('brotli/1.1.0', transitive_headers=True)
+
+    def build_requirements(self):
+        self.tool_requires("nasm/2.16.01")
+`)
+	if pkg.GitLink != "https://github.com/openssl/openssl" {
+		t.Errorf("Wrong git link %s", pkg.GitLink)
+	}
+	want := []string{"zlib", "brotli"}
+	if !slices.Equal(pkg.Depends, want) {
+		t.Errorf("Expected depends %v, got %v", want, pkg.Depends)
+	}
+}
+
+func TestParsePackageSwift(t *testing.T) {
+	deps := parsePackageSwift(`let package = Package(
+    name: "Vapor",
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-nio.git", from: "2.65.0"),
+        .package(name: "Crypto", url: "https://github.com/apple/swift-crypto.git", "1.0.0"..<"4.0.0"),
+        .package(path: "../Local"),
+    ]
+)`)
+	want := []string{"https://github.com/apple/swift-nio", "https://github.com/apple/swift-crypto"}
+	if !slices.Equal(deps, want) {
+		t.Errorf("Expected depends %v, got %v", want, deps)
+	}
+}
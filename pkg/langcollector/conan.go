@@ -0,0 +1,99 @@
+package langcollector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/go-git/go-git/v5"
+)
+
+const conanCenterIndex = "https://github.com/conan-io/conan-center-index.git"
+
+type conanRegistry struct {
+	downloadDir string
+}
+
+// NewConanRegistry reads the recipes of ConanCenter from a clone of
+// conan-center-index in downloadDir.
+func NewConanRegistry(downloadDir string) Registry {
+	return &conanRegistry{downloadDir: downloadDir}
+}
+
+func (r *conanRegistry) Type() repository.LangEcosystemType {
+	return repository.Conan
+}
+
+func (r *conanRegistry) Packages() ([]*Package, error) {
+	dir := filepath.Join(r.downloadDir, "conan-center-index")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		logger.Infof("Cloning %s", conanCenterIndex)
+		if _, err := git.PlainClone(dir, false, &git.CloneOptions{URL: conanCenterIndex, Depth: 1}); err != nil {
+			return nil, err
+		}
+	} else if repo, err := git.PlainOpen(dir); err == nil {
+		if wt, err := repo.Worktree(); err == nil {
+			if err := wt.Pull(&git.PullOptions{Depth: 1}); err != nil && err != git.NoErrAlreadyUpToDate {
+				logger.Warnf("Failed to update conan-center-index: %v", err)
+			}
+		}
+	}
+
+	recipes, err := os.ReadDir(filepath.Join(dir, "recipes"))
+	if err != nil {
+		return nil, err
+	}
+	var ret []*Package
+	for _, recipe := range recipes {
+		if !recipe.IsDir() {
+			continue
+		}
+		content, err := readConanfile(filepath.Join(dir, "recipes", recipe.Name()))
+		if err != nil {
+			logger.Warnf("Failed to read recipe %s: %v", recipe.Name(), err)
+			continue
+		}
+		pkg := parseConanfile(content)
+		pkg.Name = recipe.Name()
+		ret = append(ret, pkg)
+	}
+	return ret, nil
+}
+
+// readConanfile reads the conanfile.py of the "all" folder, or of the
+// first folder which has one.
+func readConanfile(recipeDir string) (string, error) {
+	if content, err := os.ReadFile(filepath.Join(recipeDir, "all", "conanfile.py")); err == nil {
+		return string(content), nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(recipeDir, "*", "conanfile.py"))
+	if len(matches) == 0 {
+		return "", os.ErrNotExist
+	}
+	content, err := os.ReadFile(matches[0])
+	return string(content), err
+}
+
+var (
+	conanHomepage = regexp.MustCompile(`(?m)^\s*homepage\s*=\s*["']([^"']+)["']`)
+	conanRequires = regexp.MustCompile(`self\.requires\(\s*f?["']([^/"'{]+)/`)
+)
+
+// parseConanfile reads the homepage and the requirements of a recipe.
+// The url attribute is always conan-center-index and is not used.
+func parseConanfile(content string) *Package {
+	pkg := &Package{}
+	if m := conanHomepage.FindStringSubmatch(content); m != nil {
+		pkg.GitLink = NormalizeGitLink(m[1])
+	}
+	seen := make(map[string]bool)
+	for _, m := range conanRequires.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			pkg.Depends = append(pkg.Depends, m[1])
+		}
+	}
+	return pkg
+}
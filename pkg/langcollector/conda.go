@@ -0,0 +1,77 @@
+package langcollector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+const condaChannel = "https://conda.anaconda.org/conda-forge"
+
+var condaSubdirs = []string{"noarch", "linux-64"}
+
+type condaRegistry struct{}
+
+// NewCondaRegistry fetches the packages of the conda-forge channel.
+func NewCondaRegistry() Registry {
+	return &condaRegistry{}
+}
+
+func (r *condaRegistry) Type() repository.LangEcosystemType {
+	return repository.Conda
+}
+
+type condaRecord struct {
+	Name    string   `json:"name"`
+	Depends []string `json:"depends"`
+}
+
+func (r *condaRegistry) Packages() ([]*Package, error) {
+	var channel struct {
+		Packages map[string]struct {
+			DevURL    string `json:"dev_url"`
+			Home      string `json:"home"`
+			SourceURL string `json:"source_url"`
+		} `json:"packages"`
+	}
+	if err := getJSON(condaChannel+"/channeldata.json", &channel); err != nil {
+		return nil, err
+	}
+
+	pkgs := make(map[string]*Package, len(channel.Packages))
+	for name, meta := range channel.Packages {
+		pkgs[name] = &Package{Name: name, GitLink: firstGitLink(meta.DevURL, meta.Home, meta.SourceURL)}
+	}
+
+	// current_repodata only has the latest versions
+	for _, subdir := range condaSubdirs {
+		var repodata struct {
+			Packages      map[string]*condaRecord `json:"packages"`
+			PackagesConda map[string]*condaRecord `json:"packages.conda"`
+		}
+		if err := getJSON(fmt.Sprintf("%s/%s/current_repodata.json", condaChannel, subdir), &repodata); err != nil {
+			return nil, err
+		}
+		for _, records := range []map[string]*condaRecord{repodata.Packages, repodata.PackagesConda} {
+			for _, rec := range records {
+				pkg, ok := pkgs[rec.Name]
+				if !ok {
+					continue
+				}
+				for _, dep := range rec.Depends {
+					// e.g. "python >=3.8"
+					if fields := strings.Fields(dep); len(fields) > 0 {
+						pkg.Depends = append(pkg.Depends, fields[0])
+					}
+				}
+			}
+		}
+	}
+
+	ret := make([]*Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		ret = append(ret, pkg)
+	}
+	return ret, nil
+}
@@ -0,0 +1,60 @@
+package langcollector
+
+import (
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/url"
+)
+
+// forges whose git links are always https://host/owner/repo
+var forges = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"gitee.com":     true,
+	"codeberg.org":  true,
+}
+
+// NormalizeGitLink turns a repository or homepage URL of a package into
+// the git link used in all_gitlinks, e.g. git+ssh://git@github.com/a/b.git
+// into https://github.com/a/b. It returns an empty string if the URL is
+// not on a known forge.
+func NormalizeGitLink(raw string) string {
+	s := strings.TrimSpace(raw)
+	if len(s) < 2 {
+		return ""
+	}
+	if !strings.Contains(s, "://") && !url.IsSsh(s) {
+		// bare host and path, github.com/a/b
+		s = "https://" + s
+	}
+
+	u, err := url.ParseURL(s)
+	if err != nil || u.Protocol == "file" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Resource), "www.")
+	if !forges[host] {
+		return ""
+	}
+	parts := strings.FieldsFunc(u.Pathname, func(r rune) bool { return r == '/' })
+	if len(parts) < 2 {
+		return ""
+	}
+	owner := parts[0]
+	repo := strings.TrimSuffix(parts[1], ".git")
+	if owner == "" || repo == "" {
+		return ""
+	}
+	return "https://" + host + "/" + owner + "/" + repo
+}
+
+// firstGitLink returns the first URL which can be normalized.
+func firstGitLink(urls ...string) string {
+	for _, u := range urls {
+		if link := NormalizeGitLink(u); link != "" {
+			return link
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package langcollector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type hackageRegistry struct {
+	workers int
+}
+
+// NewHackageRegistry fetches the packages of hackage.haskell.org.
+func NewHackageRegistry(workers int) Registry {
+	return &hackageRegistry{workers: workers}
+}
+
+func (r *hackageRegistry) Type() repository.LangEcosystemType {
+	return repository.Hackage
+}
+
+func (r *hackageRegistry) Packages() ([]*Package, error) {
+	var list []struct {
+		PackageName string `json:"packageName"`
+	}
+	if err := getJSONAccept("https://hackage.haskell.org/packages/", "application/json", &list); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list))
+	for _, p := range list {
+		names = append(names, p.PackageName)
+	}
+	return fetchAll(names, r.workers, func(name string) (*Package, error) {
+		body, err := get(fmt.Sprintf("https://hackage.haskell.org/package/%s/%s.cabal", name, name))
+		if err != nil {
+			return nil, err
+		}
+		pkg := parseCabal(string(body))
+		pkg.Name = name
+		return pkg, nil
+	}), nil
+}
+
+var cabalDepName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*`)
+
+// parseCabal reads the source repository and the build-depends of all
+// components from a .cabal file.
+func parseCabal(content string) *Package {
+	pkg := &Package{}
+	var homepage, location string
+	seen := make(map[string]bool)
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		field, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "homepage":
+			homepage = strings.TrimSpace(value)
+		case "location":
+			if location == "" {
+				location = strings.TrimSpace(value)
+			}
+		case "build-depends":
+			// the value continues on the lines indented deeper than the field
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			for i+1 < len(lines) {
+				next := strings.TrimRight(lines[i+1], " \t\r")
+				if strings.TrimSpace(next) != "" && len(next)-len(strings.TrimLeft(next, " \t")) <= indent {
+					break
+				}
+				value += "," + next
+				i++
+			}
+			for _, dep := range strings.Split(value, ",") {
+				dep = strings.TrimSpace(dep)
+				if strings.HasPrefix(dep, "--") {
+					continue
+				}
+				name := cabalDepName.FindString(dep)
+				if name != "" && !seen[name] {
+					seen[name] = true
+					pkg.Depends = append(pkg.Depends, name)
+				}
+			}
+		}
+	}
+	pkg.GitLink = firstGitLink(location, homepage)
+	return pkg
+}
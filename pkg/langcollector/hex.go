@@ -0,0 +1,76 @@
+package langcollector
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type hexRegistry struct {
+	workers int
+}
+
+// NewHexRegistry fetches the packages of hex.pm.
+func NewHexRegistry(workers int) Registry {
+	return &hexRegistry{workers: workers}
+}
+
+func (r *hexRegistry) Type() repository.LangEcosystemType {
+	return repository.Hex
+}
+
+type hexPackage struct {
+	Name string `json:"name"`
+	Meta struct {
+		Links map[string]string `json:"links"`
+	} `json:"meta"`
+	LatestStableVersion string `json:"latest_stable_version"`
+	LatestVersion       string `json:"latest_version"`
+}
+
+func (r *hexRegistry) Packages() ([]*Package, error) {
+	all := make(map[string]*hexPackage)
+	var names []string
+	for page := 1; ; page++ {
+		var pkgs []*hexPackage
+		if err := getJSON(fmt.Sprintf("https://hex.pm/api/packages?sort=name&page=%d", page), &pkgs); err != nil {
+			return nil, err
+		}
+		if len(pkgs) == 0 {
+			break
+		}
+		for _, p := range pkgs {
+			all[p.Name] = p
+			names = append(names, p.Name)
+		}
+	}
+
+	return fetchAll(names, r.workers, func(name string) (*Package, error) {
+		p := all[name]
+		links := slices.Sorted(maps.Values(p.Meta.Links))
+		pkg := &Package{Name: name, GitLink: firstGitLink(links...)}
+		version := p.LatestStableVersion
+		if version == "" {
+			version = p.LatestVersion
+		}
+		if version == "" {
+			return pkg, nil
+		}
+		var release struct {
+			Requirements map[string]struct {
+				Optional bool `json:"optional"`
+			} `json:"requirements"`
+		}
+		if err := getJSON(fmt.Sprintf("https://hex.pm/api/packages/%s/releases/%s", name, version), &release); err != nil {
+			return nil, err
+		}
+		for dep, req := range release.Requirements {
+			if !req.Optional {
+				pkg.Depends = append(pkg.Depends, dep)
+			}
+		}
+		return pkg, nil
+	}), nil
+}
@@ -0,0 +1,65 @@
+package langcollector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type packagistRegistry struct {
+	workers int
+}
+
+// NewPackagistRegistry fetches the packages of packagist.org.
+func NewPackagistRegistry(workers int) Registry {
+	return &packagistRegistry{workers: workers}
+}
+
+func (r *packagistRegistry) Type() repository.LangEcosystemType {
+	return repository.Packagist
+}
+
+type packagistVersion struct {
+	Source struct {
+		URL string `json:"url"`
+	} `json:"source"`
+	Homepage string            `json:"homepage"`
+	Require  map[string]string `json:"require"`
+}
+
+// isPlatformPackage reports whether a requirement is the php runtime or
+// an extension instead of a package.
+func isPlatformPackage(name string) bool {
+	return !strings.Contains(name, "/")
+}
+
+func (r *packagistRegistry) Packages() ([]*Package, error) {
+	var list struct {
+		PackageNames []string `json:"packageNames"`
+	}
+	if err := getJSON("https://packagist.org/packages/list.json", &list); err != nil {
+		return nil, err
+	}
+	return fetchAll(list.PackageNames, r.workers, func(name string) (*Package, error) {
+		var meta struct {
+			Packages map[string][]*packagistVersion `json:"packages"`
+		}
+		if err := getJSON(fmt.Sprintf("https://repo.packagist.org/p2/%s.json", name), &meta); err != nil {
+			return nil, err
+		}
+		// versions are listed from the latest
+		versions := meta.Packages[name]
+		if len(versions) == 0 {
+			return nil, nil
+		}
+		latest := versions[0]
+		pkg := &Package{Name: name, GitLink: firstGitLink(latest.Source.URL, latest.Homepage)}
+		for dep := range latest.Require {
+			if !isPlatformPackage(dep) {
+				pkg.Depends = append(pkg.Depends, dep)
+			}
+		}
+		return pkg, nil
+	}), nil
+}
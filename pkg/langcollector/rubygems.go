@@ -0,0 +1,52 @@
+package langcollector
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type rubyGemsRegistry struct {
+	workers int
+}
+
+// NewRubyGemsRegistry fetches the gems of rubygems.org.
+func NewRubyGemsRegistry(workers int) Registry {
+	return &rubyGemsRegistry{workers: workers}
+}
+
+func (r *rubyGemsRegistry) Type() repository.LangEcosystemType {
+	return repository.RubyGems
+}
+
+type rubyGem struct {
+	Name          string `json:"name"`
+	SourceCodeURI string `json:"source_code_uri"`
+	HomepageURI   string `json:"homepage_uri"`
+	Dependencies  struct {
+		Runtime []struct {
+			Name string `json:"name"`
+		} `json:"runtime"`
+	} `json:"dependencies"`
+}
+
+func (r *rubyGemsRegistry) Packages() ([]*Package, error) {
+	body, err := get("https://rubygems.org/names")
+	if err != nil {
+		return nil, err
+	}
+	names := strings.Fields(string(body))
+	return fetchAll(names, r.workers, func(name string) (*Package, error) {
+		var gem rubyGem
+		if err := getJSON(fmt.Sprintf("https://rubygems.org/api/v1/gems/%s.json", url.PathEscape(name)), &gem); err != nil {
+			return nil, err
+		}
+		pkg := &Package{Name: name, GitLink: firstGitLink(gem.SourceCodeURI, gem.HomepageURI)}
+		for _, dep := range gem.Dependencies.Runtime {
+			pkg.Depends = append(pkg.Depends, dep.Name)
+		}
+		return pkg, nil
+	}), nil
+}
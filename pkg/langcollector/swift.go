@@ -0,0 +1,59 @@
+package langcollector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+type swiftRegistry struct {
+	workers int
+}
+
+// NewSwiftRegistry fetches the packages listed by the Swift Package Index.
+// Swift packages are named by their git links.
+func NewSwiftRegistry(workers int) Registry {
+	return &swiftRegistry{workers: workers}
+}
+
+func (r *swiftRegistry) Type() repository.LangEcosystemType {
+	return repository.Swift
+}
+
+func (r *swiftRegistry) Packages() ([]*Package, error) {
+	var urls []string
+	if err := getJSON("https://raw.githubusercontent.com/SwiftPackageIndex/PackageList/main/packages.json", &urls); err != nil {
+		return nil, err
+	}
+	var links []string
+	for _, u := range urls {
+		// only github is served by raw.githubusercontent.com
+		if link := NormalizeGitLink(u); strings.HasPrefix(link, "https://github.com/") {
+			links = append(links, link)
+		}
+	}
+	return fetchAll(links, r.workers, func(link string) (*Package, error) {
+		manifest, err := get(fmt.Sprintf("https://raw.githubusercontent.com/%s/HEAD/Package.swift",
+			strings.TrimPrefix(link, "https://github.com/")))
+		if err != nil {
+			return nil, err
+		}
+		return &Package{Name: link, GitLink: link, Depends: parsePackageSwift(string(manifest))}, nil
+	}), nil
+}
+
+var swiftPackageURL = regexp.MustCompile(`\.package\s*\(\s*(?:name\s*:\s*"[^"]*"\s*,\s*)?url\s*:\s*"([^"]+)"`)
+
+// parsePackageSwift returns the normalized git links of the dependencies
+// declared in Package.swift.
+func parsePackageSwift(content string) []string {
+	var ret []string
+	for _, m := range swiftPackageURL.FindAllStringSubmatch(content, -1) {
+		if link := NormalizeGitLink(m[1]); link != "" {
+			ret = append(ret, link)
+		}
+	}
+	return ret
+}
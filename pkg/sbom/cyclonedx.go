@@ -0,0 +1,115 @@
+package sbom
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CycloneDX 1.5, see https://cyclonedx.org/docs/1.5/json/
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type               string        `json:"type"`
+	BOMRef             string        `json:"bom-ref,omitempty"`
+	Name               string        `json:"name"`
+	Version            string        `json:"version,omitempty"`
+	PURL               string        `json:"purl,omitempty"`
+	Licenses           []cdxLicense  `json:"licenses,omitempty"`
+	ExternalReferences []cdxRef      `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxLicense struct {
+	Expression string `json:"expression"`
+}
+
+type cdxRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func cdxComponentOf(n *node) cdxComponent {
+	c := cdxComponent{
+		Type:    "library",
+		BOMRef:  n.ref,
+		Name:    n.pkg.Name,
+		Version: n.version,
+		PURL:    n.purl,
+	}
+	if n.pkg.Eco != "" {
+		c.Properties = append(c.Properties, cdxProperty{"opensift:ecosystem", n.pkg.Eco})
+	}
+	if n.constraint != "" {
+		c.Properties = append(c.Properties, cdxProperty{"opensift:versionConstraint", n.constraint})
+	}
+	for _, path := range n.manifests {
+		c.Properties = append(c.Properties, cdxProperty{"opensift:manifest", path})
+	}
+	for _, w := range n.workspaces {
+		c.Properties = append(c.Properties, cdxProperty{"opensift:workspace", w})
+	}
+	return c
+}
+
+// CycloneDX returns the CycloneDX 1.5 JSON SBOM of the document. The
+// repository is the component of the metadata, and version constraints,
+// which are not versions, are properties of the components.
+func CycloneDX(d *Document) ([]byte, error) {
+	g := d.graph()
+
+	root := cdxComponentOf(g.root)
+	root.Type = "application"
+	root.ExternalReferences = []cdxRef{{Type: "vcs", URL: d.GitLink}}
+	if d.LicenseExpression != "" {
+		root.Licenses = []cdxLicense{{Expression: d.LicenseExpression}}
+	}
+
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + d.serial().String(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: d.Created.Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: "OpenSift"}}},
+			Component: root,
+		},
+		Components:   make([]cdxComponent, 0, len(g.nodes)),
+		Dependencies: make([]cdxDependency, 0, len(g.nodes)+1),
+	}
+	for _, n := range append([]*node{g.root}, g.nodes...) {
+		if n != g.root {
+			bom.Components = append(bom.Components, cdxComponentOf(n))
+		}
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: n.ref, DependsOn: append([]string{}, n.depends...)})
+	}
+	return json.MarshalIndent(bom, "", "  ")
+}
@@ -0,0 +1,148 @@
+package sbom
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+)
+
+// purl types of the ecosystems set by the manifest parsers, see
+// https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst
+var purlTypes = map[string]string{
+	parser.NPM:      "npm",
+	parser.YARN:     "npm",
+	parser.GO:       "golang",
+	parser.MAVEN:    "maven",
+	parser.GRADLE:   "maven",
+	parser.PYPI:     "pypi",
+	parser.NUGET:    "nuget",
+	parser.DOTNET:   "nuget",
+	parser.CARGO:    "cargo",
+	parser.GEMS:     "gem",
+	parser.BUNDLER:  "gem",
+	parser.COMPOSER: "composer",
+	parser.ELIXIR:   "hex",
+	parser.REBAR:    "hex",
+	parser.CONAN:    "conan",
+	parser.CONDA:    "conda",
+	parser.SWIFT:    "swift",
+	parser.DART:     "pub",
+	parser.JULIA:    "julia",
+}
+
+// purl types of the forges of git links
+var gitPurlTypes = map[string]string{
+	"github.com":    "github",
+	"gitlab.com":    "gitlab",
+	"bitbucket.org": "bitbucket",
+}
+
+var (
+	exactVersionRegexp = regexp.MustCompile(`^v?\d[0-9A-Za-z.+_-]*$`)
+	pypiSeparators     = regexp.MustCompile(`[-_.]+`)
+)
+
+// exactVersion returns the version if it is a single version, e.g. of
+// lock files, and an empty string for version constraints.
+func exactVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "=="), "=")
+	if exactVersionRegexp.MatchString(v) {
+		return v
+	}
+	return ""
+}
+
+func escape(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
+
+func format(typ, namespace, name, version string) string {
+	var b strings.Builder
+	b.WriteString("pkg:" + typ + "/")
+	if namespace != "" {
+		for _, s := range strings.Split(namespace, "/") {
+			b.WriteString(escape(s) + "/")
+		}
+	}
+	b.WriteString(escape(name))
+	if version != "" {
+		b.WriteString("@" + escape(version))
+	}
+	return b.String()
+}
+
+// splitLast splits a name at the last slash into its namespace and name.
+func splitLast(name string) (string, string) {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// GitPURL returns the purl of a git link, by the forge if it has a purl
+// type, or a generic purl with the URL.
+func GitPURL(link, version string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	p := strings.Trim(u.Path, "/")
+	if typ, ok := gitPurlTypes[u.Host]; ok {
+		namespace, name := splitLast(p)
+		return format(typ, namespace, name, version)
+	}
+	_, name := splitLast(p)
+	return format("generic", "", name, version) + "?vcs_url=" + url.QueryEscape("git+"+link)
+}
+
+// PURL returns the purl of a package of the ecosystem, with its version if
+// it is an exact version. Packages of unknown ecosystems have generic
+// purls, and native dependencies have generic purls with the kind of their
+// name as the namespace, unless they are git repositories.
+func PURL(eco, name, version string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	version = exactVersion(version)
+
+	if eco == parser.NATIVE {
+		kind, n, ok := parser.SplitNativeName(name)
+		if !ok {
+			return format("generic", "", name, version)
+		}
+		if kind == parser.NATIVE_GIT {
+			if link := langcollector.NormalizeGitLink(n); link != "" {
+				return GitPURL(link, version)
+			}
+			_, base := splitLast(strings.TrimSuffix(n, ".git"))
+			return format("generic", "", base, version) + "?vcs_url=" + url.QueryEscape("git+"+n)
+		}
+		return format("generic", kind, n, version)
+	}
+
+	typ, ok := purlTypes[eco]
+	if !ok {
+		return format("generic", "", name, version)
+	}
+	namespace := ""
+	switch typ {
+	case "npm":
+		if strings.HasPrefix(name, "@") {
+			namespace, name = splitLast(name)
+		}
+	case "maven":
+		// group:artifact, artifacts may contain dots themselves
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			namespace, name = group, artifact
+		}
+	case "golang", "composer", "swift":
+		namespace, name = splitLast(name)
+	case "pypi":
+		name = pypiSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return format(typ, namespace, name, version)
+}
@@ -0,0 +1,37 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+)
+
+func TestPURL(t *testing.T) {
+	tests := []struct {
+		eco, name, version string
+		want               string
+	}{
+		{parser.NPM, "@babel/core", "7.23.0", "pkg:npm/%40babel/core@7.23.0"},
+		{parser.NPM, "lodash", "^4.17.21", "pkg:npm/lodash"},
+		{parser.GO, "github.com/spf13/pflag", "v1.0.5", "pkg:golang/github.com/spf13/pflag@v1.0.5"},
+		{parser.MAVEN, "org.apache.commons:commons-lang3", "3.14.0", "pkg:maven/org.apache.commons/commons-lang3@3.14.0"},
+		{parser.MAVEN, "org.scala-lang.modules:scala-xml_2.13", "2.2.0", "pkg:maven/org.scala-lang.modules/scala-xml_2.13@2.2.0"},
+		{parser.PYPI, "Zope_Interface", "==6.1", "pkg:pypi/zope-interface@6.1"},
+		{parser.COMPOSER, "monolog/monolog", "3.5.0", "pkg:composer/monolog/monolog@3.5.0"},
+		{parser.ELIXIR, "phoenix", "1.7.10", "pkg:hex/phoenix@1.7.10"},
+		{parser.NATIVE, "pkgconfig:glib-2.0", ">=2.50", "pkg:generic/pkgconfig/glib-2.0"},
+		{parser.NATIVE, "git:https://github.com/google/googletest.git", "v1.14.0", "pkg:github/google/googletest@v1.14.0"},
+		{parser.NATIVE, "git:https://example.org/libs/foo.git", "", "pkg:generic/foo?vcs_url=git%2Bhttps%3A%2F%2Fexample.org%2Flibs%2Ffoo.git"},
+		{"unknown", "foo", "1.0", "pkg:generic/foo@1.0"},
+		{parser.NPM, " ", "1.0", ""},
+	}
+	for _, tt := range tests {
+		if got := PURL(tt.eco, tt.name, tt.version); got != tt.want {
+			t.Errorf("PURL(%s, %s, %s) = %q, want %q", tt.eco, tt.name, tt.version, got, tt.want)
+		}
+	}
+
+	if got := GitPURL("https://gitlab.com/group/sub/repo", "abc"); got != "pkg:gitlab/group/sub/repo@abc" {
+		t.Errorf("Wrong purl of a git link: %q", got)
+	}
+}
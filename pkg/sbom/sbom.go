@@ -0,0 +1,232 @@
+// Package sbom exports the packages a repository declares in its
+// manifests and their dependencies as software bills of materials, in
+// CycloneDX or SPDX JSON, with the purl of every package.
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/git"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser/langeco"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// formats of Marshal
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+var ErrUnknownFormat = errors.New("unknown SBOM format")
+
+// Package is a package of an ecosystem, whose version may be a version
+// constraint.
+type Package struct {
+	Eco     string
+	Name    string
+	Version string
+}
+
+// Manifest is a manifest or lock file of the repository. The package is
+// empty if the manifest does not declare one.
+type Manifest struct {
+	Path string
+	// directory of the workspace the manifest belongs to, empty if none
+	Workspace    string
+	Package      Package
+	Dependencies []Package
+}
+
+// Document is a repository at a commit, which an SBOM is made of.
+type Document struct {
+	GitLink           string
+	Commit            string
+	LicenseExpression string
+	Manifests         []Manifest
+	Created           time.Time
+}
+
+// FromRepo returns the document of a parsed repository.
+func FromRepo(gitLink string, repo *git.Repo) *Document {
+	d := &Document{
+		GitLink:           gitLink,
+		Commit:            repo.Commit,
+		LicenseExpression: repo.LicenseExpression,
+		Created:           time.Now().UTC(),
+	}
+	for _, m := range repo.Manifests {
+		d.Manifests = append(d.Manifests, Manifest{
+			Path:      m.Path,
+			Workspace: m.Workspace,
+			Package:   Package{Eco: m.Package.Eco, Name: m.Package.Name, Version: m.Package.Version},
+			Dependencies: lo.Map(m.Dependencies, func(p langeco.Package, _ int) Package {
+				return Package{Eco: p.Eco, Name: p.Name, Version: p.Version}
+			}),
+		})
+	}
+	return d
+}
+
+// Load returns the document of the latest collection of a git link, by
+// its git metrics and manifests.
+func Load(ac storage.AppDatabaseContext, gitLink string) (*Document, error) {
+	d := &Document{GitLink: gitLink, Created: time.Now().UTC()}
+
+	metric, err := repository.NewGitMetricsRepository(ac).QueryByLink(gitLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query git metrics: %w", err)
+	}
+	if metric != nil {
+		d.LicenseExpression = lo.FromPtr(lo.FromPtr(metric.LicenseExpression))
+	}
+
+	repo := repository.NewGitManifestRepository(ac)
+	manifests, err := repo.QueryByLink(gitLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manifests: %w", err)
+	}
+	for m := range manifests {
+		d.Commit = *m.CommitHash
+		deps, err := repo.QueryDependencies(*m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query dependencies: %w", err)
+		}
+		manifest := Manifest{
+			Path:      *m.ManifestPath,
+			Workspace: lo.FromPtr(lo.FromPtr(m.Workspace)),
+			Package:   Package{Eco: lo.FromPtr(lo.FromPtr(m.Ecosystem)), Name: lo.FromPtr(lo.FromPtr(m.Name)), Version: lo.FromPtr(lo.FromPtr(m.Version))},
+		}
+		for dep := range deps {
+			manifest.Dependencies = append(manifest.Dependencies, Package{
+				Eco:     lo.FromPtr(lo.FromPtr(dep.Ecosystem)),
+				Name:    lo.FromPtr(lo.FromPtr(dep.Name)),
+				Version: lo.FromPtr(lo.FromPtr(dep.VersionConstraint)),
+			})
+		}
+		d.Manifests = append(d.Manifests, manifest)
+	}
+	return d, nil
+}
+
+// Marshal returns the SBOM of the document in the format.
+func Marshal(d *Document, format string) ([]byte, error) {
+	switch format {
+	case FormatCycloneDX:
+		return CycloneDX(d)
+	case FormatSPDX:
+		return SPDX(d)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// node is a package of the dependency graph of a document.
+type node struct {
+	ref     string
+	pkg     Package
+	purl    string
+	version string
+	// version constraint if the version is not exact
+	constraint string
+	// the packages declared by manifests, and the paths of the manifests
+	declared   bool
+	manifests  []string
+	workspaces []string
+	depends    []string
+}
+
+type graph struct {
+	root  *node
+	nodes []*node
+	byRef map[string]*node
+}
+
+// name returns the name of the repository of a git link, e.g.
+// github.com/o/r.
+func (d *Document) name() string {
+	return strings.TrimPrefix(strings.TrimPrefix(d.GitLink, "https://"), "http://")
+}
+
+// serial returns a UUID of the document, which is the same for the same
+// repository, commit and creation time.
+func (d *Document) serial() uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(d.GitLink+"@"+d.Commit+"@"+d.Created.Format(time.RFC3339)))
+}
+
+// graph returns the dependency graph of the document. The repository
+// depends on the packages its manifests declare, and on the dependencies
+// of manifests which declare none. Packages are identified by their purl
+// and version constraint, and unnamed packages are dropped. The package
+// names the collector gives to manifests without one, the name of the
+// repository followed by the directory of the manifest in subdirectories,
+// are taken as unnamed.
+func (d *Document) graph() *graph {
+	g := &graph{root: &node{
+		ref:     GitPURL(d.GitLink, d.Commit),
+		pkg:     Package{Name: d.name(), Version: d.Commit},
+		purl:    GitPURL(d.GitLink, d.Commit),
+		version: d.Commit,
+	}}
+	if g.root.ref == "" {
+		g.root.ref = d.GitLink
+	}
+	g.byRef = map[string]*node{g.root.ref: g.root}
+	byKey := make(map[string]*node)
+
+	get := func(p Package, unnamed string) *node {
+		p.Name, p.Version = strings.TrimSpace(p.Name), strings.TrimSpace(p.Version)
+		if p.Name == "" || p.Name == d.name() || p.Name == unnamed {
+			return nil
+		}
+		purl := PURL(p.Eco, p.Name, p.Version)
+		version := exactVersion(p.Version)
+		constraint := ""
+		if version == "" {
+			constraint = p.Version
+		}
+		key := p.Eco + "\x00" + p.Name + "\x00" + p.Version
+		if n, ok := byKey[key]; ok {
+			return n
+		}
+		n := &node{ref: purl, pkg: p, purl: purl, version: version, constraint: constraint}
+		for i := 1; g.byRef[n.ref] != nil; i++ {
+			n.ref = fmt.Sprintf("%s#%d", purl, i)
+		}
+		g.byRef[n.ref] = n
+		byKey[key] = n
+		g.nodes = append(g.nodes, n)
+		return n
+	}
+	dependOn := func(from, to *node) {
+		if !slices.Contains(from.depends, to.ref) {
+			from.depends = append(from.depends, to.ref)
+		}
+	}
+
+	for _, m := range d.Manifests {
+		parent := get(m.Package, d.name()+"/"+path.Dir(m.Path))
+		if parent == nil {
+			parent = g.root
+		} else {
+			parent.declared = true
+			parent.manifests = append(parent.manifests, m.Path)
+			if m.Workspace != "" && !slices.Contains(parent.workspaces, m.Workspace) {
+				parent.workspaces = append(parent.workspaces, m.Workspace)
+			}
+			dependOn(g.root, parent)
+		}
+		for _, dep := range m.Dependencies {
+			if n := get(dep, ""); n != nil && n != parent {
+				dependOn(parent, n)
+			}
+		}
+	}
+	return g
+}
@@ -0,0 +1,95 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/gitfile/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() *Document {
+	return &Document{
+		GitLink:           "https://github.com/o/r",
+		Commit:            "448d5cc217d76fbdc6d0d36d20fe15792bccd18c",
+		LicenseExpression: "MIT",
+		Created:           time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Manifests: []Manifest{
+			{
+				Path:    "web/package.json",
+				Package: Package{Eco: parser.NPM, Name: "web", Version: "1.0.0"},
+				Dependencies: []Package{
+					{Eco: parser.NPM, Name: "lodash", Version: "^4.17.21"},
+					{Eco: parser.NPM, Name: "@babel/core", Version: "7.23.0"},
+				},
+			},
+			{
+				// the name given to manifests without one
+				Path:    "go.mod",
+				Package: Package{Eco: parser.GO, Name: "github.com/o/r", Version: " "},
+				Dependencies: []Package{
+					{Eco: parser.GO, Name: "github.com/spf13/pflag", Version: "v1.0.5"},
+					{Eco: parser.NPM, Name: "lodash", Version: "^4.17.21"},
+				},
+			},
+		},
+	}
+}
+
+func TestCycloneDX(t *testing.T) {
+	data, err := Marshal(testDocument(), FormatCycloneDX)
+	require.NoError(t, err)
+
+	var bom cdxBOM
+	require.NoError(t, json.Unmarshal(data, &bom))
+	require.Equal(t, "CycloneDX", bom.BOMFormat)
+	require.Equal(t, "pkg:github/o/r@448d5cc217d76fbdc6d0d36d20fe15792bccd18c", bom.Metadata.Component.PURL)
+	require.Equal(t, []cdxLicense{{Expression: "MIT"}}, bom.Metadata.Component.Licenses)
+
+	purls := make([]string, 0)
+	for _, c := range bom.Components {
+		purls = append(purls, c.PURL)
+	}
+	require.Equal(t, []string{"pkg:npm/web@1.0.0", "pkg:npm/lodash", "pkg:npm/%40babel/core@7.23.0", "pkg:golang/github.com/spf13/pflag@v1.0.5"}, purls)
+	require.Contains(t, bom.Components[1].Properties, cdxProperty{"opensift:versionConstraint", "^4.17.21"})
+
+	require.Equal(t, []cdxDependency{
+		{Ref: bom.Metadata.Component.BOMRef, DependsOn: []string{"pkg:npm/web@1.0.0", "pkg:golang/github.com/spf13/pflag@v1.0.5", "pkg:npm/lodash"}},
+		{Ref: "pkg:npm/web@1.0.0", DependsOn: []string{"pkg:npm/lodash", "pkg:npm/%40babel/core@7.23.0"}},
+		{Ref: "pkg:npm/lodash", DependsOn: []string{}},
+		{Ref: "pkg:npm/%40babel/core@7.23.0", DependsOn: []string{}},
+		{Ref: "pkg:golang/github.com/spf13/pflag@v1.0.5", DependsOn: []string{}},
+	}, bom.Dependencies)
+
+	// the same for the same document
+	again, err := CycloneDX(testDocument())
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+}
+
+func TestSPDX(t *testing.T) {
+	data, err := Marshal(testDocument(), FormatSPDX)
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	require.Len(t, doc.Packages, 5)
+	require.Equal(t, "git+https://github.com/o/r@448d5cc217d76fbdc6d0d36d20fe15792bccd18c", doc.Packages[0].DownloadLocation)
+	require.Equal(t, "MIT", doc.Packages[0].LicenseDeclared)
+	require.Equal(t, "version constraint ^4.17.21", doc.Packages[2].Comment)
+	require.Equal(t, []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:npm/lodash"}}, doc.Packages[2].ExternalRefs)
+
+	require.Equal(t, []spdxRelationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Repository"},
+		{"SPDXRef-Repository", "CONTAINS", "SPDXRef-Package-1"},
+		{"SPDXRef-Repository", "DEPENDS_ON", "SPDXRef-Package-4"},
+		{"SPDXRef-Repository", "DEPENDS_ON", "SPDXRef-Package-2"},
+		{"SPDXRef-Package-1", "DEPENDS_ON", "SPDXRef-Package-2"},
+		{"SPDXRef-Package-1", "DEPENDS_ON", "SPDXRef-Package-3"},
+	}, doc.Relationships)
+
+	_, err = Marshal(testDocument(), "xml")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,121 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const spdxNoAssertion = "NOASSERTION"
+
+// SPDX 2.3, see https://spdx.github.io/spdx-spec/v2.3/
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func spdxPackageOf(n *node, id string) spdxPackage {
+	p := spdxPackage{
+		SPDXID:           id,
+		Name:             n.pkg.Name,
+		VersionInfo:      n.version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+	}
+	if n.constraint != "" {
+		p.Comment = "version constraint " + n.constraint
+	}
+	if n.purl != "" {
+		p.ExternalRefs = []spdxExternalRef{{"PACKAGE-MANAGER", "purl", n.purl}}
+	}
+	return p
+}
+
+// SPDX returns the SPDX 2.3 JSON SBOM of the document, which describes the
+// repository. The repository contains the packages its manifests declare,
+// and version constraints, which are not versions, are comments of the
+// packages.
+func SPDX(d *Document) ([]byte, error) {
+	g := d.graph()
+
+	root := spdxPackageOf(g.root, "SPDXRef-Repository")
+	root.DownloadLocation = "git+" + d.GitLink
+	if d.Commit != "" {
+		root.DownloadLocation += "@" + d.Commit
+	}
+	if d.LicenseExpression != "" {
+		root.LicenseDeclared = d.LicenseExpression
+	}
+	root.PrimaryPackagePurpose = "SOURCE"
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              d.name(),
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", g.root.pkg.Name, d.serial()),
+		CreationInfo: spdxCreationInfo{
+			Created:  d.Created.Format(time.RFC3339),
+			Creators: []string{"Tool: OpenSift"},
+		},
+		Packages:      []spdxPackage{root},
+		Relationships: []spdxRelationship{{"SPDXRef-DOCUMENT", "DESCRIBES", root.SPDXID}},
+	}
+
+	ids := map[string]string{g.root.ref: root.SPDXID}
+	for i, n := range g.nodes {
+		ids[n.ref] = fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		p := spdxPackageOf(n, ids[n.ref])
+		p.PrimaryPackagePurpose = "LIBRARY"
+		doc.Packages = append(doc.Packages, p)
+	}
+	for _, n := range append([]*node{g.root}, g.nodes...) {
+		for _, ref := range n.depends {
+			typ := "DEPENDS_ON"
+			if n == g.root && g.byRef[ref].declared {
+				typ = "CONTAINS"
+			}
+			doc.Relationships = append(doc.Relationships, spdxRelationship{ids[n.ref], typ, ids[ref]})
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
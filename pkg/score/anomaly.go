@@ -0,0 +1,217 @@
+package score
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// Dimensions compared between rounds, named after the columns in scores.
+const (
+	AnomalyDimensionDist = "dist_score"
+	AnomalyDimensionLang = "lang_score"
+	AnomalyDimensionGit  = "git_score"
+)
+
+// AnomalyOptions controls when a change of a dimension score is abnormal.
+// A change is flagged if both limits are exceeded.
+type AnomalyOptions struct {
+	// robust z-score of the change among the changes of all links in the
+	// same dimension
+	ZScore float64
+	// change relative to the previous score, e.g. 0.5 for 50%
+	MinRelativeChange float64
+}
+
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{ZScore: 5, MinRelativeChange: 0.5}
+}
+
+// dimensionScores is the dimension scores of a link in a round.
+type dimensionScores map[string]float64
+
+// anomalyScoreFloor is the smallest scale changes of a dimension score are
+// measured against, relative to the typical score of the dimension.
+const anomalyScoreFloor = 0.01
+
+// typicalScore returns the median of the non-zero previous scores of the
+// links in the dimension, or 0 if there is none.
+func typicalScore(links []string, prev map[string]dimensionScores, dimension string) float64 {
+	scores := []float64{}
+	for _, link := range links {
+		if p := math.Abs(prev[link][dimension]); p != 0 {
+			scores = append(scores, p)
+		}
+	}
+	if len(scores) == 0 {
+		return 0
+	}
+	slices.Sort(scores)
+	return quantile(scores, 0.5)
+}
+
+// findAnomalies compares the dimension scores of the links in both rounds.
+// It returns the abnormal changes and the number of compared links.
+func findAnomalies(prev, cur map[string]dimensionScores, opts AnomalyOptions) ([]*repository.ScoreAnomaly, int) {
+	links := make([]string, 0, len(cur))
+	for link := range cur {
+		if _, ok := prev[link]; ok {
+			links = append(links, link)
+		}
+	}
+	slices.Sort(links)
+
+	ret := []*repository.ScoreAnomaly{}
+	for _, dimension := range []string{AnomalyDimensionDist, AnomalyDimensionLang, AnomalyDimensionGit} {
+		deltas := make([]float64, len(links))
+		for i, link := range links {
+			deltas[i] = cur[link][dimension] - prev[link][dimension]
+		}
+		sorted := slices.Clone(deltas)
+		slices.Sort(sorted)
+		if len(sorted) == 0 {
+			continue
+		}
+		level := typicalScore(links, prev, dimension)
+		if level == 0 {
+			// no link had a score in the dimension before
+			continue
+		}
+		median, mad := medianAndMAD(sorted)
+		// most links do not change between rounds, so MAD is often 0. The
+		// floor keeps tiny changes from getting a huge z-score, and the
+		// relative change limit keeps small ones from being flagged.
+		floor := anomalyScoreFloor * level
+		scale := math.Max(mad/madScale, floor)
+
+		for i, link := range links {
+			p := prev[link][dimension]
+			if p == 0 {
+				// the first score in the dimension, e.g. a new project
+				// or the first package in a distribution
+				continue
+			}
+			z := (deltas[i] - median) / scale
+			relative := math.Abs(deltas[i]) / math.Max(math.Abs(p), floor)
+			if math.Abs(z) < opts.ZScore || relative < opts.MinRelativeChange {
+				continue
+			}
+			c := cur[link][dimension]
+			ret = append(ret, &repository.ScoreAnomaly{
+				GitLink:   sqlutil.ToData(link),
+				Dimension: sqlutil.ToData(dimension),
+				Previous:  sqlutil.ToData(p),
+				Current:   sqlutil.ToData(c),
+				Delta:     sqlutil.ToData(deltas[i]),
+				ZScore:    sqlutil.ToData(z),
+			})
+		}
+	}
+	return ret, len(links)
+}
+
+func fetchDimensionScores(ac storage.AppDatabaseContext, round int) (map[string]dimensionScores, error) {
+	scoresIter, err := repository.NewScoreRepository(ac).QueryByRound(round)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]dimensionScores)
+	for s := range scoresIter {
+		if s.GitLink == nil {
+			continue
+		}
+		d := dimensionScores{}
+		for dimension, v := range map[string]*float64{
+			AnomalyDimensionDist: s.DistScore,
+			AnomalyDimensionLang: s.LangScore,
+			AnomalyDimensionGit:  s.GitScore,
+		} {
+			if v != nil {
+				d[dimension] = *v
+			}
+		}
+		ret[*s.GitLink] = d
+	}
+	return ret, nil
+}
+
+// DetectAnomalies compares round with the latest published round before
+// it, replaces the anomalies stored for round and records the anomaly rate
+// of the round. Blocked rounds are never the baseline, so an anomalous
+// round does not hide the anomalies of the next one. It returns the number
+// of links with at least one anomaly, the number of compared links and the
+// baseline round, which is 0 if there is no published round before.
+func DetectAnomalies(ac storage.AppDatabaseContext, round int, opts AnomalyOptions) (anomalous int, compared int, baseline int, err error) {
+	baseline, err = repository.NewScoreRepository(ac).GetPublishedRoundBefore(round)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	prev := map[string]dimensionScores{}
+	if baseline > 0 {
+		prev, err = fetchDimensionScores(ac, baseline)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	cur, err := fetchDimensionScores(ac, round)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	anomalies, compared := findAnomalies(prev, cur, opts)
+	linkSet := make(map[string]struct{})
+	for _, a := range anomalies {
+		a.Round = &round
+		linkSet[*a.GitLink] = struct{}{}
+	}
+	anomalous = len(linkSet)
+
+	repo := repository.NewScoreAnomalyRepository(ac)
+	if err := repo.DeleteByRound(round); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := repo.BatchInsert(anomalies); err != nil {
+		return 0, 0, 0, err
+	}
+	err = repository.NewScoreRoundRepository(ac).InsertOrUpdate(&repository.ScoreRound{
+		Round:          &round,
+		ComparedLinks:  &compared,
+		AnomalousLinks: &anomalous,
+	})
+	return anomalous, compared, baseline, err
+}
+
+// PublishRound unblocks the round if it is finished and its anomaly rate is
+// at most maxRate, otherwise the round is blocked. DetectAnomalies must be
+// called before. It returns whether the round is published and its anomaly
+// rate.
+func PublishRound(ac storage.AppDatabaseContext, round int, maxRate float64) (bool, float64, error) {
+	roundRepo := repository.NewScoreRoundRepository(ac)
+	r, err := roundRepo.GetByRound(round)
+	if err != nil {
+		return false, 0, err
+	}
+	if r == nil {
+		return false, 0, fmt.Errorf("round %d is not recorded", round)
+	}
+	rate := AnomalyRate(r)
+	published := r.Finished != nil && *r.Finished && rate <= maxRate
+	err = roundRepo.InsertOrUpdate(&repository.ScoreRound{
+		Round:   &round,
+		Blocked: sqlutil.ToData(!published),
+	})
+	return published, rate, err
+}
+
+// AnomalyRate returns the fraction of compared links with anomalies in
+// the round, or 0 if the round is not compared.
+func AnomalyRate(r *repository.ScoreRound) float64 {
+	if r == nil || r.ComparedLinks == nil || r.AnomalousLinks == nil || *r.ComparedLinks == 0 {
+		return 0
+	}
+	return float64(*r.AnomalousLinks) / float64(*r.ComparedLinks)
+}
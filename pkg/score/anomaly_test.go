@@ -0,0 +1,67 @@
+package score
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindAnomalies(t *testing.T) {
+	prev := map[string]dimensionScores{}
+	cur := map[string]dimensionScores{}
+	for i := range 100 {
+		link := fmt.Sprintf("https://example.com/repo%d", i)
+		prev[link] = dimensionScores{AnomalyDimensionDist: 1, AnomalyDimensionLang: 0.5, AnomalyDimensionGit: 0.8}
+		// small drift on every link
+		cur[link] = dimensionScores{AnomalyDimensionDist: 1 + float64(i%3)*0.01, AnomalyDimensionLang: 0.5, AnomalyDimensionGit: 0.8}
+	}
+	// a distribution which returns half of the packages
+	cur["https://example.com/repo1"][AnomalyDimensionDist] = 0.4
+	// git metrics reset to zero
+	cur["https://example.com/repo2"][AnomalyDimensionGit] = 0
+	// links only in one round are not compared
+	cur["https://example.com/new"] = dimensionScores{AnomalyDimensionDist: 100}
+
+	anomalies, compared := findAnomalies(prev, cur, DefaultAnomalyOptions())
+	if compared != 100 {
+		t.Errorf("Expected 100 compared links, got %d", compared)
+	}
+	if len(anomalies) != 2 {
+		t.Fatalf("Expected 2 anomalies, got %d", len(anomalies))
+	}
+	if *anomalies[0].GitLink != "https://example.com/repo1" || *anomalies[0].Dimension != AnomalyDimensionDist {
+		t.Errorf("Unexpected anomaly: %s %s", *anomalies[0].GitLink, *anomalies[0].Dimension)
+	}
+	if *anomalies[1].GitLink != "https://example.com/repo2" || *anomalies[1].Dimension != AnomalyDimensionGit {
+		t.Errorf("Unexpected anomaly: %s %s", *anomalies[1].GitLink, *anomalies[1].Dimension)
+	}
+}
+
+func TestFindAnomaliesFromZero(t *testing.T) {
+	prev := map[string]dimensionScores{}
+	cur := map[string]dimensionScores{}
+	for i := range 100 {
+		link := fmt.Sprintf("https://example.com/repo%d", i)
+		// no link had a language ecosystem score before
+		prev[link] = dimensionScores{AnomalyDimensionDist: 1, AnomalyDimensionLang: 0, AnomalyDimensionGit: 0.8}
+		cur[link] = dimensionScores{AnomalyDimensionDist: 1, AnomalyDimensionLang: float64(i%4) * 0.3, AnomalyDimensionGit: 0.8}
+	}
+	// new projects get their first dist score
+	for i := range 10 {
+		prev[fmt.Sprintf("https://example.com/repo%d", i)][AnomalyDimensionDist] = 0
+	}
+	// a tiny change of an otherwise constant dimension
+	cur["https://example.com/repo50"][AnomalyDimensionGit] = 0.8001
+	// a git score which really collapsed
+	cur["https://example.com/repo60"][AnomalyDimensionGit] = 0.1
+
+	anomalies, compared := findAnomalies(prev, cur, DefaultAnomalyOptions())
+	if compared != 100 {
+		t.Errorf("Expected 100 compared links, got %d", compared)
+	}
+	if len(anomalies) != 1 || *anomalies[0].GitLink != "https://example.com/repo60" {
+		for _, a := range anomalies {
+			t.Errorf("Unexpected anomaly: %s %s %v -> %v", *a.GitLink, *a.Dimension, *a.Previous, *a.Current)
+		}
+		t.Fatalf("Expected only the collapsed git score, got %d anomalies", len(anomalies))
+	}
+}
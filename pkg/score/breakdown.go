@@ -0,0 +1,61 @@
+package score
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+// DimensionScore is the parent name used for the contributions of the three
+// dimensions to the final score.
+const DimensionScore = "score"
+
+// Contribution records how a single metric took part in a score.
+type Contribution struct {
+	// Dimension is the parent of the metric in the score tree,
+	// e.g. gitMetadataScore, or DimensionScore for the dimensions themselves
+	Dimension    string
+	Metric       string
+	Value        float64
+	Normalized   float64
+	Weight       float64
+	Contribution float64
+}
+
+// contribute normalizes value with the threshold or the fitted parameters
+// of the metric and applies its weight from the profile.
+func (c *calculator) contribute(normalization, dimension, metric string, value float64) Contribution {
+	normalized := c.normalize(normalization, dimension, metric, value)
+	weight := c.profile.Weights[dimension][metric]
+	return Contribution{
+		Dimension:    dimension,
+		Metric:       metric,
+		Value:        value,
+		Normalized:   normalized,
+		Weight:       weight,
+		Contribution: weight * normalized,
+	}
+}
+
+// Breakdown returns all contributions of the link score, dimensions first.
+func (linkScore *LinkScore) Breakdown() []Contribution {
+	ret := make([]Contribution, 0)
+	ret = append(ret, linkScore.Contributions...)
+	ret = append(ret, linkScore.GitMetadataScore.Contributions...)
+	ret = append(ret, linkScore.DistScore.Contributions...)
+	ret = append(ret, linkScore.LangEcoScore.Contributions...)
+	return ret
+}
+
+func contributionsToBreakdown(contributions []Contribution) []*repository.ScoreBreakdown {
+	ret := make([]*repository.ScoreBreakdown, 0, len(contributions))
+	for _, c := range contributions {
+		ret = append(ret, &repository.ScoreBreakdown{
+			Dimension:    &c.Dimension,
+			Metric:       &c.Metric,
+			Value:        &c.Value,
+			Normalized:   &c.Normalized,
+			Weight:       &c.Weight,
+			Contribution: &c.Contribution,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,75 @@
+package score
+
+import (
+	"time"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+// calculator holds everything a score is calculated with. The
+// scores-caculator calculates with the package level state, see active,
+// while a simulation brings its own, so it never changes the live state.
+type calculator struct {
+	profile *Profile
+	// parameters of data-driven normalization modes
+	params      FittedParams
+	packageList map[repository.DistType]int
+	// time the time-based metrics are evaluated at, the current time if
+	// zero
+	now time.Time
+	// first error met during the calculation, the calculation goes on
+	// with 0 for the failed value
+	err error
+}
+
+var calculationTime time.Time
+
+// UseCalculationTime sets the time the time-based metrics like
+// created_since are evaluated at in all following calculations, usually
+// the start time of the round, so every link of a round is scored at the
+// same time. The zero time evaluates them at the current time.
+func UseCalculationTime(t time.Time) {
+	calculationTime = t
+}
+
+// active returns a calculator on the active profile, fitted parameters,
+// package list and calculation time.
+func active() *calculator {
+	return &calculator{profile: activeProfile, params: fittedParams, packageList: PackageList, now: calculationTime}
+}
+
+func (c *calculator) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// mustSucceed exits if the calculation failed, the scores-caculator never
+// writes a score calculated with a missing parameter.
+func (c *calculator) mustSucceed() {
+	if c.err != nil {
+		log.Fatalf("Failed to calculate scores: %v", c.err)
+	}
+}
+
+// since returns the months from t to the calculation time.
+func (c *calculator) since(t time.Time) float64 {
+	now := c.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.Sub(t).Hours() / (24 * 30)
+}
+
+// monthsSince is the same as since, but returns 0 if t is unknown.
+func (c *calculator) monthsSince(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return c.since(t)
+}
+
+func (c *calculator) distCoefficient(t repository.DistType) float64 {
+	return c.profile.distCoefficientWith(c.packageList, t)
+}
@@ -1,6 +1,8 @@
 package score
 
 import (
+	"fmt"
+	"iter"
 	"math"
 	"time"
 
@@ -17,9 +19,10 @@ type LinkScore struct {
 	// LangEcosystems   []*repository.LangEcosystem
 	LangEcoScore LangEcoScore
 	// DistDependencies []*repository.DistDependency
-	DistScore DistScore
-	Score     float64
-	Round     int
+	DistScore     DistScore
+	Score         float64
+	Round         int
+	Contributions []Contribution
 }
 
 type GitMetadata struct {
@@ -29,11 +32,24 @@ type GitMetadata struct {
 	ContributorCount int
 	CommitFrequency  float64
 	Org_Count        int
+	// maintainer concentration, zero if not collected yet
+	BusFactor         int
+	TopAuthorShare    float64
+	ActiveMaintainers int
+	// release cadence, zero if not collected yet. LastRelease is the
+	// creation time for repositories without releases.
+	ReleasesLastYear int
+	// nil if unknown, i.e. the repository has fewer than two release
+	// intervals
+	MedianReleaseInterval *float64
+	LastRelease           time.Time
+	SignedReleaseRatio    float64
 }
 
 type GitMetadataScore struct {
 	GitMetrics       []*repository.GitMetric
 	GitMetadataScore float64
+	Contributions    []Contribution
 }
 
 type DistMetadata struct {
@@ -59,6 +75,7 @@ type DistScore struct {
 	downloads_3m     int
 	DistPageRank     float64
 	DistScore        float64
+	Contributions    []Contribution
 }
 
 type LangEcoScore struct {
@@ -66,75 +83,7 @@ type LangEcoScore struct {
 	LangEcoImpact   float64
 	LangEcoPageRank float64
 	LangEcoScore    float64
-}
-
-var SigmoidWeight = 1.2
-
-// Define weights (αi) and max thresholds (Ti)
-var weights = map[string]map[string]float64{
-	"gitMetadataScore": {
-		"created_since":     1,
-		"updated_since":     -1,
-		"contributor_count": 2,
-		"commit_frequency":  1,
-		"org_count":         1,
-		"gitMetadataScore":  0.2,
-	},
-	"distScore": {
-		"dist_impact":   1,
-		"dist_pagerank": 1,
-		"downloads_3m":  0.5,
-		"distScore":     0.5,
-	},
-	"langEcoScore": {
-		"lang_eco_impact":   1,
-		"lang_eco_pagerank": 1,
-		"langEcoScore":      0.3,
-	},
-}
-
-var thresholds = map[string]map[string]map[string]float64{
-	"log": {
-		"gitMetadataScore": {
-			"created_since":     120,
-			"updated_since":     120,
-			"contributor_count": 40000,
-			"commit_frequency":  1000,
-			"org_count":         8400,
-			"gitMetadataScore":  5,
-		},
-		"distScore": {
-			"dist_impact":   22,
-			"dist_pagerank": 3,
-			"distScore":     1.5,
-		},
-		"langEcoScore": {
-			"lang_eco_impact":   1,
-			"lang_eco_pagerank": 0.0002,
-			"langEcoScore":      1.3,
-		},
-	},
-	"sigmoid": {
-		"gitMetadataScore": {
-			"created_since":     120,
-			"updated_since":     120,
-			"contributor_count": 10000,
-			"commit_frequency":  1000,
-			"org_count":         5000,
-			"gitMetadataScore":  5,
-		},
-		"distScore": {
-			"dist_impact":   6,
-			"dist_pagerank": 0.5,
-			"downloads_3m":  1700000,
-			"distScore":     1.5,
-		},
-		"langEcoScore": {
-			"lang_eco_impact":   0.1,
-			"lang_eco_pagerank": 0.0001,
-			"langEcoScore":      1.3,
-		},
-	},
+	Contributions   []Contribution
 }
 
 var PackageList = map[repository.DistType]int{
@@ -151,15 +100,6 @@ var PackageList = map[repository.DistType]int{
 	repository.Centos:   0,
 }
 
-var PackageWeight = map[repository.LangEcosystemType]float64{
-	repository.Npm:   1,
-	repository.Go:    1,
-	repository.Maven: 1,
-	repository.Pypi:  1,
-	repository.NuGet: 1,
-	repository.Cargo: 1,
-}
-
 func (langEcoMetadata *LangEcoMetadata) ParseLangEcoMetadata(langEcosystem *repository.LangEcosystem) {
 	langEcoMetadata.Id = *langEcosystem.ID
 	langEcoMetadata.Type = *langEcosystem.Type
@@ -194,37 +134,117 @@ func (gitMetadata *GitMetadata) ParseMetadata(gitMetic *repository.GitMetric) {
 	if !sqlutil.IsNull(gitMetic.OrgCount) {
 		gitMetadata.Org_Count = **gitMetic.OrgCount
 	}
+	if !sqlutil.IsNull(gitMetic.BusFactor) {
+		gitMetadata.BusFactor = **gitMetic.BusFactor
+	}
+	if !sqlutil.IsNull(gitMetic.TopAuthorShare) {
+		gitMetadata.TopAuthorShare = **gitMetic.TopAuthorShare
+	}
+	if !sqlutil.IsNull(gitMetic.ActiveMaintainers) {
+		gitMetadata.ActiveMaintainers = **gitMetic.ActiveMaintainers
+	}
+	if !sqlutil.IsNull(gitMetic.ReleasesLastYear) {
+		gitMetadata.ReleasesLastYear = **gitMetic.ReleasesLastYear
+		gitMetadata.LastRelease = gitMetadata.CreatedSince
+	}
+	if !sqlutil.IsNull(gitMetic.MedianReleaseInterval) {
+		gitMetadata.MedianReleaseInterval = *gitMetic.MedianReleaseInterval
+	}
+	if !sqlutil.IsNull(gitMetic.LastRelease) {
+		gitMetadata.LastRelease = **gitMetic.LastRelease
+	}
+	if !sqlutil.IsNull(gitMetic.SignedReleaseRatio) {
+		gitMetadata.SignedReleaseRatio = **gitMetic.SignedReleaseRatio
+	}
 }
 
 func (langEcoScore *LangEcoScore) CalculateLangEcoScore(normalization string) {
-	langEcoScore.LangEcoScore = weights["langEcoScore"]["lang_eco_impact"]*PerformOperation(normalization, langEcoScore.LangEcoImpact, thresholds[normalization]["langEcoScore"]["lang_eco_impact"]) + weights["langEcoScore"]["lang_eco_pagerank"]*PerformOperation(normalization, langEcoScore.LangEcoPageRank, thresholds[normalization]["langEcoScore"]["lang_eco_pagerank"])
+	c := active()
+	langEcoScore.calculate(c, normalization)
+	c.mustSucceed()
+}
+
+func (langEcoScore *LangEcoScore) calculate(c *calculator, normalization string) {
+	impact := c.contribute(normalization, "langEcoScore", "lang_eco_impact", langEcoScore.LangEcoImpact)
+	pagerank := c.contribute(normalization, "langEcoScore", "lang_eco_pagerank", langEcoScore.LangEcoPageRank)
+
+	langEcoScore.LangEcoScore = impact.Contribution + pagerank.Contribution
+	langEcoScore.Contributions = []Contribution{impact, pagerank}
 }
 
 func NewLangEcoScore() *LangEcoScore {
 	return &LangEcoScore{}
 }
 
+// metricValues returns the raw value of every git metric used in the score
+// with the profile of the calculator.
+func (gitMetadata *GitMetadata) metricValues(c *calculator) map[string]float64 {
+	values := map[string]float64{
+		"created_since":     c.since(gitMetadata.CreatedSince),
+		"updated_since":     c.since(gitMetadata.UpdatedSince),
+		"contributor_count": float64(gitMetadata.ContributorCount),
+		"commit_frequency":  gitMetadata.CommitFrequency,
+		"org_count":         float64(gitMetadata.Org_Count),
+	}
+	for metric, value := range map[string]float64{
+		"bus_factor":           float64(gitMetadata.BusFactor),
+		"top_author_share":     gitMetadata.TopAuthorShare,
+		"active_maintainers":   float64(gitMetadata.ActiveMaintainers),
+		"releases_last_year":   float64(gitMetadata.ReleasesLastYear),
+		"last_release_since":   c.monthsSince(gitMetadata.LastRelease),
+		"signed_release_ratio": gitMetadata.SignedReleaseRatio,
+	} {
+		if c.profile.usesMetric("gitMetadataScore", metric) {
+			values[metric] = value
+		}
+	}
+	// an unknown interval is skipped rather than scored as zero days
+	if gitMetadata.MedianReleaseInterval != nil && c.profile.usesMetric("gitMetadataScore", "median_release_interval") {
+		values["median_release_interval"] = *gitMetadata.MedianReleaseInterval
+	}
+	return values
+}
+
 func (gitMetadataScore *GitMetadataScore) CalculateGitMetadataScore(gitMetadata *GitMetadata, normalization string) {
+	c := active()
+	gitMetadataScore.calculate(c, gitMetadata, normalization)
+	c.mustSucceed()
+}
+
+func (gitMetadataScore *GitMetadataScore) calculate(c *calculator, gitMetadata *GitMetadata, normalization string) {
 	var score float64
-	var createdSinceScore, updatedSinceScore, contributorCountScore, commitFrequencyScore, orgCountScore float64
+	values := gitMetadata.metricValues(c)
 
-	monthsSinceCreation := time.Since(gitMetadata.CreatedSince).Hours() / (24 * 30)
-	createdSinceScore = weights["gitMetadataScore"]["created_since"] * PerformOperation(normalization, monthsSinceCreation, thresholds[normalization]["gitMetadataScore"]["created_since"])
-	score += createdSinceScore
+	createdSinceScore := c.contribute(normalization, "gitMetadataScore", "created_since", values["created_since"])
+	score += createdSinceScore.Contribution
 
-	monthsSinceUpdate := time.Since(gitMetadata.UpdatedSince).Hours() / (24 * 30)
-	updatedSinceScore = weights["gitMetadataScore"]["updated_since"] * PerformOperation(normalization, monthsSinceUpdate, thresholds[normalization]["gitMetadataScore"]["updated_since"])
-	score += updatedSinceScore
+	updatedSinceScore := c.contribute(normalization, "gitMetadataScore", "updated_since", values["updated_since"])
+	score += updatedSinceScore.Contribution
 
-	contributorCountScore = weights["gitMetadataScore"]["contributor_count"] * PerformOperation(normalization, float64(gitMetadata.ContributorCount), thresholds[normalization]["gitMetadataScore"]["contributor_count"])
-	score += contributorCountScore
+	contributorCountScore := c.contribute(normalization, "gitMetadataScore", "contributor_count", values["contributor_count"])
+	score += contributorCountScore.Contribution
 
-	commitFrequencyScore = weights["gitMetadataScore"]["commit_frequency"] * PerformOperation(normalization, gitMetadata.CommitFrequency, thresholds[normalization]["gitMetadataScore"]["commit_frequency"])
-	score += commitFrequencyScore
+	commitFrequencyScore := c.contribute(normalization, "gitMetadataScore", "commit_frequency", values["commit_frequency"])
+	score += commitFrequencyScore.Contribution
 
-	orgCountScore = weights["gitMetadataScore"]["org_count"] * PerformOperation(normalization, float64(gitMetadata.Org_Count), thresholds[normalization]["gitMetadataScore"]["org_count"])
-	score += orgCountScore
+	orgCountScore := c.contribute(normalization, "gitMetadataScore", "org_count", values["org_count"])
+	score += orgCountScore.Contribution
 
+	gitMetadataScore.Contributions = []Contribution{
+		createdSinceScore,
+		updatedSinceScore,
+		contributorCountScore,
+		commitFrequencyScore,
+		orgCountScore,
+	}
+
+	for _, metric := range optionalMetrics["gitMetadataScore"] {
+		if value, ok := values[metric]; ok {
+			contribution := c.contribute(normalization, "gitMetadataScore", metric, value)
+			score += contribution.Contribution
+			gitMetadataScore.Contributions = append(gitMetadataScore.Contributions, contribution)
+		}
+	}
 	gitMetadataScore.GitMetadataScore = score
 	gitMetadataScore.GitMetrics = []*repository.GitMetric{
 		{
@@ -238,19 +258,45 @@ func NewGitMetadata() *GitMetadata {
 }
 
 func (distScore *DistScore) CalculateDistScore(normalization string) {
-	distScore.DistScore = weights["distScore"]["dist_impact"]*PerformOperation(normalization, distScore.DistImpact, thresholds[normalization]["distScore"]["dist_impact"]) + weights["distScore"]["dist_pagerank"]*PerformOperation(normalization, distScore.DistPageRank, thresholds[normalization]["distScore"]["dist_pagerank"])
+	c := active()
+	distScore.calculate(c, normalization)
+	c.mustSucceed()
+}
+
+func (distScore *DistScore) calculate(c *calculator, normalization string) {
+	impact := c.contribute(normalization, "distScore", "dist_impact", distScore.DistImpact)
+	pagerank := c.contribute(normalization, "distScore", "dist_pagerank", distScore.DistPageRank)
+
+	distScore.DistScore = impact.Contribution + pagerank.Contribution
+	distScore.Contributions = []Contribution{impact, pagerank}
 }
 
 func (linkScore *LinkScore) CalculateScore(normalization string) {
+	c := active()
+	linkScore.calculate(c, normalization)
+	c.mustSucceed()
+}
+
+func (linkScore *LinkScore) calculate(c *calculator, normalization string) {
 	score := 0.0
 
-	score += weights["gitMetadataScore"]["gitMetadataScore"] * PerformOperation(normalization, linkScore.GitMetadataScore.GitMetadataScore, thresholds[normalization]["gitMetadataScore"]["gitMetadataScore"]) * 100
+	gitScore := c.contribute(normalization, "gitMetadataScore", "gitMetadataScore", linkScore.GitMetadataScore.GitMetadataScore)
+	gitScore.Dimension = DimensionScore
+	gitScore.Contribution *= 100
+	score += gitScore.Contribution
 
-	score += weights["langEcoScore"]["langEcoScore"] * PerformOperation(normalization, linkScore.LangEcoScore.LangEcoScore, thresholds[normalization]["langEcoScore"]["langEcoScore"]) * 100
+	langEcoScore := c.contribute(normalization, "langEcoScore", "langEcoScore", linkScore.LangEcoScore.LangEcoScore)
+	langEcoScore.Dimension = DimensionScore
+	langEcoScore.Contribution *= 100
+	score += langEcoScore.Contribution
 
-	score += weights["distScore"]["distScore"] * PerformOperation(normalization, linkScore.DistScore.DistScore, thresholds[normalization]["distScore"]["distScore"]) * 100
+	distScore := c.contribute(normalization, "distScore", "distScore", linkScore.DistScore.DistScore)
+	distScore.Dimension = DimensionScore
+	distScore.Contribution *= 100
+	score += distScore.Contribution
 
 	linkScore.Score = score
+	linkScore.Contributions = []Contribution{gitScore, langEcoScore, distScore}
 }
 
 func NewGitMetadataScore() *GitMetadataScore {
@@ -283,28 +329,53 @@ func LogNormalize(value, threshold float64) float64 {
 }
 
 func Sigmoid(value, threshold float64) float64 {
-	return 1 / (1 + SigmoidWeight*math.Exp(-1*(value-threshold)))
+	return sigmoid(value, threshold, activeProfile.SigmoidWeight)
+}
+
+func sigmoid(value, threshold, weight float64) float64 {
+	return 1 / (1 + weight*math.Exp(-1*(value-threshold)))
 }
 
 func PerformOperation(flag string, value, threshold float64) float64 {
+	ret, err := performOperation(flag, value, threshold, activeProfile.SigmoidWeight)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return ret
+}
+
+func performOperation(flag string, value, threshold, sigmoidWeight float64) (float64, error) {
 	switch flag {
-	case "log":
-		return LogNormalize(value, threshold)
-	case "sigmoid":
-		return Sigmoid(value, threshold)
+	case NormalizationLog:
+		return LogNormalize(value, threshold), nil
+	case NormalizationSigmoid:
+		return sigmoid(value, threshold, sigmoidWeight), nil
 	default:
-		log.Fatalf("Unknown flag: %s", flag)
-		return 0
+		return 0, fmt.Errorf("unknown flag: %s", flag)
 	}
 }
 
 func FetchGitMetrics(ac storage.AppDatabaseContext) map[string]*GitMetadata {
 	repo := repository.NewGitMetricsRepository(ac)
 	linksIter, err := repo.Query()
-	linksMap := make(map[string]*GitMetadata)
 	if err != nil {
 		log.Fatalf("Failed to fetch git links: %v", err)
 	}
+	return collectGitMetrics(linksIter)
+}
+
+// FetchGitMetricsByLinks is the same as FetchGitMetrics, but only for links
+func FetchGitMetricsByLinks(ac storage.AppDatabaseContext, links []string) map[string]*GitMetadata {
+	repo := repository.NewGitMetricsRepository(ac)
+	linksIter, err := repo.QueryByLinks(links)
+	if err != nil {
+		log.Fatalf("Failed to fetch git links: %v", err)
+	}
+	return collectGitMetrics(linksIter)
+}
+
+func collectGitMetrics(linksIter iter.Seq[*repository.GitMetric]) map[string]*GitMetadata {
+	linksMap := make(map[string]*GitMetadata)
 	for link := range linksIter {
 		gitMetadata := NewGitMetadata()
 		gitMetadata.ParseMetadata(link)
@@ -315,20 +386,34 @@ func FetchGitMetrics(ac storage.AppDatabaseContext) map[string]*GitMetadata {
 
 func FetchLangEcoMetadata(ac storage.AppDatabaseContext) map[string]*LangEcoScore {
 	repo := repository.NewLangEcoLinkRepository(ac)
-	LangEcoMap := make(map[string]*LangEcoScore)
 	linksIter, err := repo.Query()
 	if err != nil {
 		log.Fatalf("Failed to fetch lang eco links: %v", err)
 	}
+	return collectLangEcoMetadata(active(), linksIter)
+}
+
+// FetchLangEcoMetadataByLinks is the same as FetchLangEcoMetadata, but only for links
+func FetchLangEcoMetadataByLinks(ac storage.AppDatabaseContext, links []string) map[string]*LangEcoScore {
+	repo := repository.NewLangEcoLinkRepository(ac)
+	linksIter, err := repo.QueryByLinks(links)
+	if err != nil {
+		log.Fatalf("Failed to fetch lang eco links: %v", err)
+	}
+	return collectLangEcoMetadata(active(), linksIter)
+}
+
+func collectLangEcoMetadata(c *calculator, linksIter iter.Seq[*repository.LangEcosystem]) map[string]*LangEcoScore {
+	LangEcoMap := make(map[string]*LangEcoScore)
 	for link := range linksIter {
 		langEcoMetadata := NewLangEcoMetadata()
 		langEcoMetadata.ParseLangEcoMetadata(link)
 		if exists, ok := LangEcoMap[*link.GitLink]; ok && exists != nil {
 			LangEcoMap[*link.GitLink].LangEcosystems = append(LangEcoMap[*link.GitLink].LangEcosystems, link)
-			LangEcoMap[*link.GitLink].LangEcoImpact += langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type]
-			LangEcoMap[*link.GitLink].LangEcoPageRank += langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]
+			LangEcoMap[*link.GitLink].LangEcoImpact += langEcoMetadata.LangEcoImpact * c.profile.packageWeight(langEcoMetadata.Type)
+			LangEcoMap[*link.GitLink].LangEcoPageRank += langEcoMetadata.LangEcoPageRank * c.profile.packageWeight(langEcoMetadata.Type)
 		} else {
-			LangEcoMap[*link.GitLink] = &LangEcoScore{LangEcosystems: []*repository.LangEcosystem{link}, LangEcoImpact: langEcoMetadata.LangEcoImpact * PackageWeight[langEcoMetadata.Type], LangEcoPageRank: langEcoMetadata.LangEcoPageRank * PackageWeight[langEcoMetadata.Type]}
+			LangEcoMap[*link.GitLink] = &LangEcoScore{LangEcosystems: []*repository.LangEcosystem{link}, LangEcoImpact: langEcoMetadata.LangEcoImpact * c.profile.packageWeight(langEcoMetadata.Type), LangEcoPageRank: langEcoMetadata.LangEcoPageRank * c.profile.packageWeight(langEcoMetadata.Type)}
 		}
 	}
 	return LangEcoMap
@@ -336,22 +421,36 @@ func FetchLangEcoMetadata(ac storage.AppDatabaseContext) map[string]*LangEcoScor
 
 func FetchDistMetadata(ac storage.AppDatabaseContext) map[string]*DistScore {
 	repo := repository.NewDistDependencyRepository(ac)
-	distMap := make(map[string]*DistScore)
 	linksIter, err := repo.Query()
 	if err != nil {
 		log.Fatalf("Failed to fetch dist links: %v", err)
 	}
+	return collectDistMetadata(active(), linksIter)
+}
+
+// FetchDistMetadataByLinks is the same as FetchDistMetadata, but only for links
+func FetchDistMetadataByLinks(ac storage.AppDatabaseContext, links []string) map[string]*DistScore {
+	repo := repository.NewDistDependencyRepository(ac)
+	linksIter, err := repo.QueryByLinks(links)
+	if err != nil {
+		log.Fatalf("Failed to fetch dist links: %v", err)
+	}
+	return collectDistMetadata(active(), linksIter)
+}
+
+func collectDistMetadata(c *calculator, linksIter iter.Seq[*repository.DistDependency]) map[string]*DistScore {
+	distMap := make(map[string]*DistScore)
 	for link := range linksIter {
 		distMetadata := NewDistMetadata()
 		distMetadata.PraseDistMetadata(link)
-		coefficient := PackageList[distMetadata.Type] / PackageList[repository.Homebrew]
+		coefficient := c.distCoefficient(distMetadata.Type)
 		if exists, ok := distMap[*link.GitLink]; ok && exists != nil {
 			distMap[*link.GitLink].DistDependencies = append(distMap[*link.GitLink].DistDependencies, link)
-			distMap[*link.GitLink].DistImpact += float64(coefficient) * distMetadata.DepImpact
+			distMap[*link.GitLink].DistImpact += coefficient * distMetadata.DepImpact
 			distMap[*link.GitLink].downloads_3m += distMetadata.downloads_3m
-			distMap[*link.GitLink].DistPageRank += float64(coefficient) * distMetadata.PageRank
+			distMap[*link.GitLink].DistPageRank += coefficient * distMetadata.PageRank
 		} else {
-			distMap[*link.GitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: float64(coefficient) * distMetadata.DepImpact, DistPageRank: float64(coefficient) * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
+			distMap[*link.GitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: coefficient * distMetadata.DepImpact, DistPageRank: coefficient * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
 		}
 	}
 	return distMap
@@ -370,19 +469,69 @@ func FetchGitLink(ac storage.AppDatabaseContext) []string {
 }
 
 func UpdatePackageList(ac storage.AppDatabaseContext) {
+	packageList, err := fetchPackageList(ac)
+	if err != nil {
+		log.Fatalf("Failed to fetch dist links: %v", err)
+	}
+	for distType, count := range packageList {
+		PackageList[distType] = count
+	}
+}
+
+func fetchPackageList(ac storage.AppDatabaseContext) (map[repository.DistType]int, error) {
 	repo := repository.NewDistDependencyRepository(ac)
+	packageList := make(map[repository.DistType]int, len(PackageList))
 	for distType := range PackageList {
 		count, err := repo.QueryDistCountByType(distType)
 		if err != nil {
-			log.Fatalf("Failed to fetch dist links: %v", err)
+			return nil, err
 		}
-		PackageList[distType] = count
+		packageList[distType] = count
+	}
+	return packageList, nil
+}
+
+// CalculateScores calculates the score of every link in the round. Links
+// without distribution or language ecosystem data get zero in these
+// dimensions.
+func CalculateScores(links []string, gitMetrics map[string]*GitMetadata, langEco map[string]*LangEcoScore, dist map[string]*DistScore, normalization string, round int) map[string]*LinkScore {
+	c := active()
+	packageScore := make(map[string]*LinkScore)
+	for _, link := range links {
+		if _, ok := dist[link]; !ok {
+			dist[link] = NewDistScore()
+		}
+		if _, ok := langEco[link]; !ok {
+			langEco[link] = NewLangEcoScore()
+		}
+		packageScore[link] = c.calculateLink(link, gitMetrics[link], langEco[link], dist[link], normalization, round)
+	}
+	c.mustSucceed()
+	return packageScore
+}
+
+// calculateLink calculates the score of a link, langEco and dist are
+// calculated in place and must not be nil.
+func (c *calculator) calculateLink(link string, gitMetadata *GitMetadata, langEco *LangEcoScore, dist *DistScore, normalization string, round int) *LinkScore {
+	dist.calculate(c, normalization)
+	langEco.calculate(c, normalization)
+
+	gitMetadataScore := NewGitMetadataScore()
+	if gitMetadata == nil {
+		log.Debugf("No git metadata for %s", link)
+	} else {
+		gitMetadataScore.calculate(c, gitMetadata, normalization)
 	}
+	linkScore := NewLinkScore(gitMetadataScore, dist, langEco, round)
+	linkScore.calculate(c, normalization)
+	return linkScore
 }
 
 func UpdateScore(ac storage.AppDatabaseContext, packageScore map[string]*LinkScore) {
 	repo := repository.NewScoreRepository(ac)
 	scores := []*repository.Score{}
+	profileName := activeProfile.Name
+	profileHash := activeProfile.Hash()
 	for link, linkScore := range packageScore {
 		score := repository.Score{
 			Score:            &linkScore.Score,
@@ -394,6 +543,10 @@ func UpdateScore(ac storage.AppDatabaseContext, packageScore map[string]*LinkSco
 			LangScore:        &linkScore.LangEcoScore.LangEcoScore,
 			GitScore:         &linkScore.GitMetadataScore.GitMetadataScore,
 			Round:            &linkScore.Round,
+			ProfileName:      &profileName,
+			ProfileHash:      &profileHash,
+			ComputedRound:    &linkScore.Round,
+			Breakdown:        contributionsToBreakdown(linkScore.Breakdown()),
 		}
 		scores = append(scores, &score)
 	}
@@ -419,14 +572,14 @@ func FetchDistMetadataSingle(ac storage.AppDatabaseContext, link string) map[str
 		}
 		distMetadata := NewDistMetadata()
 		distMetadata.PraseDistMetadata(link)
-		coefficient := PackageList[distMetadata.Type] / PackageList[repository.Homebrew]
+		coefficient := activeProfile.distCoefficient(distMetadata.Type)
 		if exists, ok := distMap[*link.GitLink]; ok && exists != nil {
 			distMap[*link.GitLink].DistDependencies = append(distMap[*link.GitLink].DistDependencies, link)
-			distMap[*link.GitLink].DistImpact += float64(coefficient) * distMetadata.DepImpact
-			distMap[*link.GitLink].DistPageRank += float64(coefficient) * distMetadata.PageRank
+			distMap[*link.GitLink].DistImpact += coefficient * distMetadata.DepImpact
+			distMap[*link.GitLink].DistPageRank += coefficient * distMetadata.PageRank
 			distMap[*link.GitLink].downloads_3m += distMetadata.downloads_3m
 		} else {
-			distMap[*link.GitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: float64(coefficient) * distMetadata.DepImpact, DistPageRank: float64(coefficient) * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
+			distMap[*link.GitLink] = &DistScore{DistDependencies: []*repository.DistDependency{link}, DistImpact: coefficient * distMetadata.DepImpact, DistPageRank: coefficient * distMetadata.PageRank, downloads_3m: distMetadata.downloads_3m}
 		}
 	}
 	return distMap
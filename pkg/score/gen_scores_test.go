@@ -3,6 +3,10 @@ package score
 import (
 	"math"
 	"testing"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
 )
 
 func TestCalculateDistScore(t *testing.T) {
@@ -11,7 +15,7 @@ func TestCalculateDistScore(t *testing.T) {
 		DistPageRank: 0.5,
 	}
 
-	expectedScore := (weights["distScore"]["dist_impact"] * distScore.DistImpact) + (weights["distScore"]["dist_pagerank"] * distScore.DistPageRank)
+	expectedScore := (activeProfile.Weights["distScore"]["dist_impact"] * distScore.DistImpact) + (activeProfile.Weights["distScore"]["dist_pagerank"] * distScore.DistPageRank)
 	distScore.CalculateDistScore("log")
 
 	if distScore.DistScore != expectedScore {
@@ -30,3 +34,88 @@ func TestLogNormalize(t *testing.T) {
 		t.Errorf("Expected %v, but got %v", expected, actual)
 	}
 }
+
+func TestCalculateScoreBreakdown(t *testing.T) {
+	distScore := &DistScore{DistImpact: 3, DistPageRank: 0.5}
+	distScore.CalculateDistScore("log")
+	langEcoScore := &LangEcoScore{LangEcoImpact: 0.2, LangEcoPageRank: 0.0001}
+	langEcoScore.CalculateLangEcoScore("log")
+	gitMetadataScore := NewGitMetadataScore()
+	gitMetadataScore.CalculateGitMetadataScore(&GitMetadata{
+		CreatedSince:     time.Now().AddDate(-5, 0, 0),
+		UpdatedSince:     time.Now().AddDate(0, -1, 0),
+		ContributorCount: 100,
+		CommitFrequency:  10,
+		Org_Count:        5,
+	}, "log")
+
+	linkScore := NewLinkScore(gitMetadataScore, distScore, langEcoScore, 1)
+	linkScore.CalculateScore("log")
+
+	sums := make(map[string]float64)
+	for _, c := range linkScore.Breakdown() {
+		sums[c.Dimension] += c.Contribution
+	}
+
+	expected := map[string]float64{
+		DimensionScore:     linkScore.Score,
+		"gitMetadataScore": gitMetadataScore.GitMetadataScore,
+		"distScore":        distScore.DistScore,
+		"langEcoScore":     langEcoScore.LangEcoScore,
+	}
+	for dimension, v := range expected {
+		if math.Abs(sums[dimension]-v) > 1e-9 {
+			t.Errorf("Contributions of %s sum to %v, but score is %v", dimension, sums[dimension], v)
+		}
+	}
+}
+
+func TestReleaseGitMetrics(t *testing.T) {
+	created := time.Now().AddDate(-2, 0, 0)
+	withoutReleases := &GitMetadata{}
+	withoutReleases.ParseMetadata(&repository.GitMetric{
+		ID:               sqlutil.ToData(int64(1)),
+		CreatedSince:     sqlutil.ToNullable(created),
+		ReleasesLastYear: sqlutil.ToNullable(0),
+		LastRelease:      sqlutil.ToData[*time.Time](nil),
+	})
+	if !withoutReleases.LastRelease.Equal(created) {
+		t.Errorf("Expected the creation time as the last release, got %v", withoutReleases.LastRelease)
+	}
+
+	notCollected := &GitMetadata{}
+	notCollected.ParseMetadata(&repository.GitMetric{ID: sqlutil.ToData(int64(2)), CreatedSince: sqlutil.ToNullable(created)})
+	if months := active().monthsSince(notCollected.LastRelease); months != 0 {
+		t.Errorf("Expected 0 months for rows without release metrics, got %v", months)
+	}
+	if months := active().monthsSince(withoutReleases.LastRelease); months < 23 || months > 25 {
+		t.Errorf("Expected about 24 months since the last release, got %v", months)
+	}
+}
+
+func TestUnknownMedianReleaseInterval(t *testing.T) {
+	p := DefaultProfile()
+	p.Weights["gitMetadataScore"]["median_release_interval"] = 1
+	for _, thresholds := range p.Thresholds {
+		thresholds["gitMetadataScore"]["median_release_interval"] = 90
+	}
+	c := &calculator{profile: p, params: FittedParams{}}
+
+	unknown := &GitMetadata{}
+	unknown.ParseMetadata(&repository.GitMetric{
+		ID:                    sqlutil.ToData(int64(1)),
+		MedianReleaseInterval: sqlutil.ToData[*float64](nil),
+	})
+	if _, ok := unknown.metricValues(c)["median_release_interval"]; ok {
+		t.Errorf("Expected an unknown median release interval to be skipped")
+	}
+
+	known := &GitMetadata{}
+	known.ParseMetadata(&repository.GitMetric{
+		ID:                    sqlutil.ToData(int64(2)),
+		MedianReleaseInterval: sqlutil.ToNullable(30.0),
+	})
+	if v, ok := known.metricValues(c)["median_release_interval"]; !ok || v != 30 {
+		t.Errorf("Expected a median release interval of 30 days, got %v", v)
+	}
+}
@@ -0,0 +1,276 @@
+package score
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// PlanIncremental returns the links which have to be recomputed when the
+// next round is derived from prevRound, the scores of all other links can
+// be carried forward. ok is false if the whole round has to be recomputed,
+// e.g. when the profile or the normalization changed, prevRound was not
+// recorded, or the normalization is data-driven.
+//
+// The links are streamed from the database in ascending order each time
+// they are iterated, so the plan never holds the changed links in memory.
+//
+// Metrics like created_since change with the time of the calculation, so
+// if the profile weights them, links whose score was computed longer than
+// maxCarryAge ago are recomputed as well. A zero maxCarryAge disables this.
+//
+// PackageList must be updated before calling this function.
+func PlanIncremental(ac storage.AppDatabaseContext, prevRound int, normalization string, maxCarryAge time.Duration) (links iter.Seq[string], ok bool) {
+	if IsDataDriven(normalization) {
+		log.Infof("Normalization %s depends on the whole population, recompute all links", normalization)
+		return nil, false
+	}
+	prev, err := repository.NewScoreRoundRepository(ac).GetByRound(prevRound)
+	if err != nil || prev == nil {
+		log.Infof("Round %d is not recorded, recompute all links", prevRound)
+		return nil, false
+	}
+	if sqlString(prev.ProfileHash) != activeProfile.Hash() {
+		log.Infof("Profile changed since round %d, recompute all links", prevRound)
+		return nil, false
+	}
+	if sqlString(prev.Normalization) != normalization {
+		log.Infof("Normalization changed since round %d, recompute all links", prevRound)
+		return nil, false
+	}
+	if prev.StartTime == nil {
+		return nil, false
+	}
+	prevPackageList, err := parsePackageList(sqlString(prev.PackageList))
+	if err != nil {
+		log.Warnf("Invalid package list of round %d: %v", prevRound, err)
+		return nil, false
+	}
+
+	since := *prev.StartTime
+	distTypes := changedDistTypes(prevPackageList, PackageList)
+	staleBefore := time.Time{}
+	if maxCarryAge > 0 && activeProfile.usesTimeMetrics() {
+		staleBefore = time.Now().Add(-maxCarryAge)
+	}
+
+	return func(yield func(string) bool) {
+		var changed []iter.Seq[string]
+		collect := func(linksIter iter.Seq[string], err error) {
+			if err != nil {
+				log.Fatalf("Failed to fetch changed links: %v", err)
+			}
+			changed = append(changed, linksIter)
+		}
+		collect(repository.NewGitMetricsRepository(ac).QueryLinksUpdatedSince(since))
+		collect(repository.NewLangEcoLinkRepository(ac).QueryLinksUpdatedSince(since))
+		distRepo := repository.NewDistDependencyRepository(ac)
+		collect(distRepo.QueryLinksUpdatedSince(since))
+		if len(distTypes) > 0 {
+			collect(distRepo.QueryLinksByTypes(distTypes))
+		}
+		scoreRepo := repository.NewScoreRepository(ac)
+		collect(scoreRepo.QueryUnscoredLinks(prevRound))
+		if !staleBefore.IsZero() {
+			collect(scoreRepo.QueryLinksComputedBefore(prevRound, staleBefore))
+		}
+
+		all, err := repository.NewAllGitLinkRepository(ac).QueryOrdered("")
+		if err != nil {
+			log.Fatalf("Failed to fetch git links: %v", err)
+		}
+		for link := range linksIn(all, changed...) {
+			if !yield(link) {
+				return
+			}
+		}
+	}, true
+}
+
+// changedDistTypes returns distributions whose coefficient differs between
+// the two package lists, all links in them have to be recomputed.
+func changedDistTypes(prev, cur map[repository.DistType]int) []repository.DistType {
+	ret := []repository.DistType{}
+	for distType := range cur {
+		if activeProfile.distCoefficientWith(prev, distType) != activeProfile.distCoefficientWith(cur, distType) {
+			ret = append(ret, distType)
+		}
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// CarryForward copies the scores of all links without a score in toRound
+// from fromRound to toRound.
+func CarryForward(ac storage.AppDatabaseContext, fromRound, toRound int) int64 {
+	cnt, err := repository.NewScoreRepository(ac).CarryForward(fromRound, toRound)
+	if err != nil {
+		log.Fatalf("Failed to carry forward scores: %v", err)
+	}
+	return cnt
+}
+
+// StartRound saves the inputs shared by all links of the round, which are
+// used to plan the next incremental round and to resume the round, and the
+// fitted parameters of data-driven normalization modes. The round is
+// blocked until FinishRound, so a partially written round is never
+// published.
+func StartRound(ac storage.AppDatabaseContext, round int, normalization string, incremental bool, startTime time.Time) {
+	packageList, err := formatPackageList(PackageList)
+	if err != nil {
+		log.Fatalf("Failed to format package list: %v", err)
+	}
+	var params *string
+	if IsDataDriven(normalization) {
+		content, err := json.Marshal(fittedParams)
+		if err != nil {
+			log.Fatalf("Failed to format fitted parameters: %v", err)
+		}
+		params = sqlutil.ToData(string(content))
+	}
+	profile, err := json.Marshal(activeProfile)
+	if err != nil {
+		log.Fatalf("Failed to format profile: %v", err)
+	}
+	err = repository.NewScoreRoundRepository(ac).InsertOrUpdate(&repository.ScoreRound{
+		Round:          &round,
+		ProfileName:    &activeProfile.Name,
+		ProfileHash:    sqlutil.ToData(activeProfile.Hash()),
+		Profile:        sqlutil.ToNullable(string(profile)),
+		Normalization:  &normalization,
+		PackageList:    &packageList,
+		FittedParams:   &params,
+		Incremental:    &incremental,
+		Recomputed:     sqlutil.ToData(0),
+		CarriedForward: sqlutil.ToData(0),
+		Checkpoint:     sqlutil.ToData(""),
+		Finished:       sqlutil.ToData(false),
+		Blocked:        sqlutil.ToData(true),
+		StartTime:      &startTime,
+	})
+	if err != nil {
+		log.Fatalf("Failed to record round: %v", err)
+	}
+}
+
+// RoundProfile returns the profile the round is calculated with. Rounds
+// recorded before profiles were stored, and scores calculated before
+// rounds were recorded, only have the built-in default profile restored.
+func RoundProfile(ac storage.AppDatabaseContext, round int) (*Profile, error) {
+	r, err := repository.NewScoreRoundRepository(ac).GetByRound(round)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return DefaultProfile(), nil
+	}
+	if sqlutil.IsNull(r.Profile) {
+		if p := DefaultProfile(); sqlString(r.ProfileHash) == p.Hash() {
+			return p, nil
+		}
+		return nil, fmt.Errorf("profile %s of round %d is not recorded", sqlString(r.ProfileName), round)
+	}
+	p := &Profile{}
+	if err := json.Unmarshal([]byte(**r.Profile), p); err != nil {
+		return nil, fmt.Errorf("invalid profile of round %d: %w", round, err)
+	}
+	return p, nil
+}
+
+// ResumableRound returns the latest round if it is not finished, or nil.
+func ResumableRound(ac storage.AppDatabaseContext) *repository.ScoreRound {
+	r, err := repository.NewScoreRoundRepository(ac).GetLatest()
+	if err != nil {
+		log.Fatalf("Failed to fetch score round: %v", err)
+	}
+	if r == nil || r.Round == nil || r.Finished == nil || *r.Finished {
+		return nil
+	}
+	return r
+}
+
+// ResumeRound restores the inputs recorded by StartRound and the start
+// time the round is calculated at, so the rest of the round is calculated
+// the same way as the written part. The active profile and normalization
+// must be the ones the round is started with.
+func ResumeRound(r *repository.ScoreRound, normalization string) {
+	if sqlString(r.ProfileHash) != activeProfile.Hash() {
+		log.Fatalf("Round %d is started with profile %s (%s), resume it with the same profile",
+			*r.Round, sqlString(r.ProfileName), sqlString(r.ProfileHash))
+	}
+	if sqlString(r.Normalization) != normalization {
+		log.Fatalf("Round %d is started with normalization %s, resume it with the same normalization",
+			*r.Round, sqlString(r.Normalization))
+	}
+	packageList, err := parsePackageList(sqlString(r.PackageList))
+	if err != nil {
+		log.Fatalf("Invalid package list of round %d: %v", *r.Round, err)
+	}
+	for distType, count := range packageList {
+		PackageList[distType] = count
+	}
+	if r.StartTime != nil {
+		UseCalculationTime(*r.StartTime)
+	}
+	if IsDataDriven(normalization) {
+		if sqlutil.IsNull(r.FittedParams) {
+			log.Fatalf("No fitted parameters recorded for round %d", *r.Round)
+		}
+		params := FittedParams{}
+		if err := json.Unmarshal([]byte(**r.FittedParams), &params); err != nil {
+			log.Fatalf("Invalid fitted parameters of round %d: %v", *r.Round, err)
+		}
+		UseFittedParams(params)
+	}
+}
+
+// FinishRound marks the round as completely written. The round stays
+// blocked until it passes the anomaly check, see PublishRound.
+func FinishRound(ac storage.AppDatabaseContext, round int, recomputed, carried int) {
+	err := repository.NewScoreRoundRepository(ac).InsertOrUpdate(&repository.ScoreRound{
+		Round:          &round,
+		Recomputed:     &recomputed,
+		CarriedForward: &carried,
+		Finished:       sqlutil.ToData(true),
+	})
+	if err != nil {
+		log.Fatalf("Failed to record round: %v", err)
+	}
+}
+
+func formatPackageList(packageList map[repository.DistType]int) (string, error) {
+	named := make(map[string]int, len(packageList))
+	for distType, count := range packageList {
+		named[distTypeNames[distType]] = count
+	}
+	content, err := json.Marshal(named)
+	return string(content), err
+}
+
+func parsePackageList(content string) (map[repository.DistType]int, error) {
+	named := make(map[string]int)
+	if err := json.Unmarshal([]byte(content), &named); err != nil {
+		return nil, err
+	}
+	ret := make(map[repository.DistType]int, len(named))
+	for distType, name := range distTypeNames {
+		if count, ok := named[name]; ok {
+			ret[distType] = count
+		}
+	}
+	return ret, nil
+}
+
+func sqlString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,60 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+func TestPackageListRoundTrip(t *testing.T) {
+	packageList := map[repository.DistType]int{
+		repository.Debian:   300,
+		repository.Homebrew: 100,
+	}
+	content, err := formatPackageList(packageList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parsePackageList(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(packageList) {
+		t.Fatalf("Expected %d distributions, got %d", len(packageList), len(parsed))
+	}
+	for distType, count := range packageList {
+		if parsed[distType] != count {
+			t.Errorf("Expected %d packages for %v, got %d", count, distType, parsed[distType])
+		}
+	}
+}
+
+func TestChangedDistTypes(t *testing.T) {
+	prev := map[repository.DistType]int{
+		repository.Debian:   300,
+		repository.Arch:     200,
+		repository.Homebrew: 100,
+	}
+	cur := map[repository.DistType]int{
+		repository.Debian:   300,
+		repository.Arch:     400,
+		repository.Homebrew: 100,
+	}
+
+	changed := changedDistTypes(prev, cur)
+	if len(changed) != 1 || changed[0] != repository.Arch {
+		t.Errorf("Expected only arch to change, got %v", changed)
+	}
+}
+
+func TestUsesTimeMetrics(t *testing.T) {
+	p := DefaultProfile()
+	if !p.usesTimeMetrics() {
+		t.Errorf("Expected the default profile to use time-based metrics")
+	}
+	p.Weights["gitMetadataScore"]["created_since"] = 0
+	p.Weights["gitMetadataScore"]["updated_since"] = 0
+	if p.usesTimeMetrics() {
+		t.Errorf("Expected no time-based metrics with zero weights")
+	}
+}
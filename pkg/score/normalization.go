@@ -0,0 +1,401 @@
+package score
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+const (
+	NormalizationLog        = "log"
+	NormalizationSigmoid    = "sigmoid"
+	NormalizationPercentile = "percentile"
+	NormalizationZScore     = "zscore"
+	NormalizationMinMax     = "minmax"
+)
+
+const (
+	// number of quantiles kept for the percentile mode
+	percentileKnots = 1001
+	// the zscore mode divides MAD by this to estimate the standard deviation
+	madScale              = 0.6744897501960817
+	defaultZScoreClip     = 3
+	defaultMinMaxTrimRate = 0.01
+)
+
+// IsDataDriven reports whether the normalization mode fits its parameters
+// from the population of the round instead of reading thresholds.
+func IsDataDriven(normalization string) bool {
+	switch normalization {
+	case NormalizationPercentile, NormalizationZScore, NormalizationMinMax:
+		return true
+	default:
+		return false
+	}
+}
+
+// FittedParam holds the parameters of a data-driven normalization fitted
+// for a single metric. Only the fields of the fitted mode are set.
+type FittedParam struct {
+	// percentile: evenly spaced quantiles of the population
+	Quantiles []float64 `json:"quantiles,omitempty"`
+	// zscore
+	Median float64 `json:"median,omitempty"`
+	MAD    float64 `json:"mad,omitempty"`
+	Clip   float64 `json:"clip,omitempty"`
+	// minmax: bounds of the trimmed range
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+}
+
+// FittedParams is dimension -> metric -> fitted parameters, the same layout
+// as a normalization in Profile.Thresholds.
+type FittedParams map[string]map[string]*FittedParam
+
+func (f FittedParams) set(dimension, metric string, param *FittedParam) {
+	if _, ok := f[dimension]; !ok {
+		f[dimension] = make(map[string]*FittedParam)
+	}
+	f[dimension][metric] = param
+}
+
+var fittedParams = FittedParams{}
+
+// UseFittedParams sets the parameters used by data-driven normalization
+// modes in all following calculations.
+func UseFittedParams(params FittedParams) {
+	fittedParams = params
+}
+
+// ActiveFittedParams returns the parameters currently used by data-driven
+// normalization modes.
+func ActiveFittedParams() FittedParams {
+	return fittedParams
+}
+
+// Fit computes the parameters of a data-driven normalization mode from the
+// values of one metric over the whole population.
+func Fit(normalization string, values []float64) *FittedParam {
+	return fit(activeProfile, normalization, values)
+}
+
+func fit(p *Profile, normalization string, values []float64) *FittedParam {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if len(sorted) == 0 {
+		sorted = []float64{0}
+	}
+
+	switch normalization {
+	case NormalizationPercentile:
+		knots := min(percentileKnots, len(sorted))
+		quantiles := make([]float64, knots)
+		for i := range quantiles {
+			quantiles[i] = quantile(sorted, float64(i)/float64(max(knots-1, 1)))
+		}
+		return &FittedParam{Quantiles: quantiles}
+	case NormalizationZScore:
+		median, mad := medianAndMAD(sorted)
+		clip := p.ZScoreClip
+		if clip <= 0 {
+			clip = defaultZScoreClip
+		}
+		return &FittedParam{Median: median, MAD: mad, Clip: clip}
+	case NormalizationMinMax:
+		trim := p.MinMaxTrim
+		if trim <= 0 {
+			trim = defaultMinMaxTrimRate
+		}
+		return &FittedParam{Min: quantile(sorted, trim), Max: quantile(sorted, 1-trim)}
+	default:
+		return nil
+	}
+}
+
+// medianAndMAD returns the median and the median absolute deviation of
+// sorted.
+func medianAndMAD(sorted []float64) (float64, float64) {
+	median := quantile(sorted, 0.5)
+	deviations := make([]float64, len(sorted))
+	for i, v := range sorted {
+		deviations[i] = math.Abs(v - median)
+	}
+	slices.Sort(deviations)
+	return median, quantile(deviations, 0.5)
+}
+
+// quantile returns the q-th quantile of sorted with linear interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// Percentile returns the empirical CDF of value in [0, 1]. Values tied with
+// several quantiles get the middle of them, so a large group of equal
+// values, e.g. zeros, does not jump to the top.
+func Percentile(value float64, param *FittedParam) float64 {
+	q := param.Quantiles
+	if len(q) < 2 {
+		return 0.5
+	}
+	n := float64(len(q) - 1)
+	lo := sort.SearchFloat64s(q, value)
+	hi := sort.Search(len(q), func(i int) bool { return q[i] > value })
+	switch {
+	case hi == 0:
+		return 0
+	case lo == len(q):
+		return 1
+	case lo < hi:
+		return (float64(lo+hi-1) / 2) / n
+	default:
+		return (float64(lo-1) + (value-q[lo-1])/(q[lo]-q[lo-1])) / n
+	}
+}
+
+// RobustZScore returns the z-score of value based on median and MAD,
+// clipped to [-Clip, Clip] and mapped to [0, 1].
+func RobustZScore(value float64, param *FittedParam) float64 {
+	if param.MAD == 0 {
+		switch {
+		case value > param.Median:
+			return 1
+		case value < param.Median:
+			return 0
+		default:
+			return 0.5
+		}
+	}
+	z := (value - param.Median) * madScale / param.MAD
+	z = math.Max(-param.Clip, math.Min(param.Clip, z))
+	return (z + param.Clip) / (2 * param.Clip)
+}
+
+// TrimmedMinMax scales value linearly between the trimmed bounds and clamps
+// it to [0, 1].
+func TrimmedMinMax(value float64, param *FittedParam) float64 {
+	if param.Max <= param.Min {
+		if value > param.Min {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, math.Min(1, (value-param.Min)/(param.Max-param.Min)))
+}
+
+func PerformFittedOperation(flag string, value float64, param *FittedParam) float64 {
+	ret, err := performFittedOperation(flag, value, param)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return ret
+}
+
+func performFittedOperation(flag string, value float64, param *FittedParam) (float64, error) {
+	if param == nil {
+		return 0, fmt.Errorf("no fitted parameters for normalization %s", flag)
+	}
+	switch flag {
+	case NormalizationPercentile:
+		return Percentile(value, param), nil
+	case NormalizationZScore:
+		return RobustZScore(value, param), nil
+	case NormalizationMinMax:
+		return TrimmedMinMax(value, param), nil
+	default:
+		return 0, fmt.Errorf("unknown flag: %s", flag)
+	}
+}
+
+// normalize normalizes the value of a metric with the thresholds of the
+// profile, or with the fitted parameters for data-driven modes.
+func (c *calculator) normalize(normalization, dimension, metric string, value float64) float64 {
+	var ret float64
+	var err error
+	if IsDataDriven(normalization) {
+		ret, err = performFittedOperation(normalization, value, c.params[dimension][metric])
+	} else {
+		ret, err = performOperation(normalization, value, c.profile.Thresholds[normalization][dimension][metric], c.profile.SigmoidWeight)
+	}
+	if err != nil {
+		c.fail(fmt.Errorf("%s.%s: %w", dimension, metric, err))
+	}
+	return ret
+}
+
+// linkInput is the input of a single link, nil if the link has no data
+// from the source.
+type linkInput struct {
+	link    string
+	git     *GitMetadata
+	langEco *LangEcoScore
+	dist    *DistScore
+}
+
+// langEcoOrEmpty returns a copy of the language ecosystem score, so it can
+// be calculated without changing the input.
+func (in *linkInput) langEcoOrEmpty() LangEcoScore {
+	if in.langEco != nil {
+		return *in.langEco
+	}
+	return *NewLangEcoScore()
+}
+
+func (in *linkInput) distOrEmpty() DistScore {
+	if in.dist != nil {
+		return *in.dist
+	}
+	return *NewDistScore()
+}
+
+// fitSampleSize is the number of values kept per metric when fitting, so
+// fitting needs bounded memory however many links there are. Fitting is
+// exact for smaller populations.
+const fitSampleSize = 200000
+
+// sample is a reservoir sample of the values of a metric. The random
+// source is seeded with a constant, so the same population always gives
+// the same sample.
+type sample struct {
+	values []float64
+	seen   int
+	rnd    *rand.Rand
+}
+
+func newSample() *sample {
+	return &sample{rnd: rand.New(rand.NewPCG(1, 2))}
+}
+
+func (s *sample) add(v float64) {
+	s.seen++
+	if len(s.values) < fitSampleSize {
+		s.values = append(s.values, v)
+		return
+	}
+	if i := s.rnd.IntN(s.seen); i < fitSampleSize {
+		s.values[i] = v
+	}
+}
+
+// normalizationFitter fits a data-driven normalization in two passes over
+// the population. Metrics are fitted in the first pass, then the dimension
+// scores computed with them in the second, as the final score normalizes
+// the dimension scores again. The parameters are fitted into the
+// calculator.
+type normalizationFitter struct {
+	c             *calculator
+	normalization string
+	params        FittedParams
+	samples       map[string]map[string]*sample
+}
+
+func newNormalizationFitter(c *calculator, normalization string) *normalizationFitter {
+	f := &normalizationFitter{
+		c:             c,
+		normalization: normalization,
+		params:        FittedParams{},
+		samples:       make(map[string]map[string]*sample),
+	}
+	c.params = f.params
+	return f
+}
+
+func (f *normalizationFitter) add(dimension, metric string, value float64) {
+	if _, ok := f.samples[dimension]; !ok {
+		f.samples[dimension] = make(map[string]*sample)
+	}
+	if _, ok := f.samples[dimension][metric]; !ok {
+		f.samples[dimension][metric] = newSample()
+	}
+	f.samples[dimension][metric].add(value)
+}
+
+// fit fits the added values into the parameters of the calculator.
+func (f *normalizationFitter) fit() {
+	for dimension, metrics := range f.samples {
+		for metric, s := range metrics {
+			f.params.set(dimension, metric, fit(f.c.profile, f.normalization, s.values))
+		}
+	}
+	f.samples = make(map[string]map[string]*sample)
+}
+
+func (f *normalizationFitter) addMetrics(in *linkInput) {
+	// links without git metadata are not calculated, so they are not
+	// part of the population of git metrics
+	if in.git != nil {
+		for metric, value := range in.git.metricValues(f.c) {
+			f.add("gitMetadataScore", metric, value)
+		}
+	}
+	langEcoScore := in.langEcoOrEmpty()
+	f.add("langEcoScore", "lang_eco_impact", langEcoScore.LangEcoImpact)
+	f.add("langEcoScore", "lang_eco_pagerank", langEcoScore.LangEcoPageRank)
+	distScore := in.distOrEmpty()
+	f.add("distScore", "dist_impact", distScore.DistImpact)
+	f.add("distScore", "dist_pagerank", distScore.DistPageRank)
+}
+
+func (f *normalizationFitter) addDimensions(in *linkInput) {
+	gitScore := NewGitMetadataScore()
+	if in.git != nil {
+		gitScore.calculate(f.c, in.git, f.normalization)
+	}
+	f.add("gitMetadataScore", "gitMetadataScore", gitScore.GitMetadataScore)
+	langEcoScore := in.langEcoOrEmpty()
+	langEcoScore.calculate(f.c, f.normalization)
+	f.add("langEcoScore", "langEcoScore", langEcoScore.LangEcoScore)
+	distScore := in.distOrEmpty()
+	distScore.calculate(f.c, f.normalization)
+	f.add("distScore", "distScore", distScore.DistScore)
+}
+
+// FitNormalization fits the parameters of a data-driven normalization mode
+// from the population of links and makes them active.
+func FitNormalization(normalization string, links []string, gitMetrics map[string]*GitMetadata, langEco map[string]*LangEcoScore, dist map[string]*DistScore) FittedParams {
+	c := active()
+	f := newNormalizationFitter(c, normalization)
+	UseFittedParams(f.params)
+
+	inputs := make([]*linkInput, 0, len(links))
+	for _, link := range links {
+		inputs = append(inputs, &linkInput{link: link, git: gitMetrics[link], langEco: langEco[link], dist: dist[link]})
+	}
+	for _, in := range inputs {
+		f.addMetrics(in)
+	}
+	f.fit()
+	for _, in := range inputs {
+		f.addDimensions(in)
+	}
+	f.fit()
+	c.mustSucceed()
+	return f.params
+}
+
+// FittedParamsOfRound returns the parameters recorded for a round, so the
+// scores of the round can be reproduced.
+func FittedParamsOfRound(ac storage.AppDatabaseContext, round int) (FittedParams, error) {
+	r, err := repository.NewScoreRoundRepository(ac).GetByRound(round)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil || sqlutil.IsNull(r.FittedParams) {
+		return nil, fmt.Errorf("no fitted parameters recorded for round %d", round)
+	}
+	params := FittedParams{}
+	if err := json.Unmarshal([]byte(**r.FittedParams), &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,98 @@
+package score
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{0, 0, 0, 0, 1, 2, 3, 4, 5, 10}
+	param := Fit(NormalizationPercentile, values)
+
+	tests := []struct {
+		value    float64
+		expected float64
+	}{
+		{-1, 0},
+		{0, 1.5 / 9},
+		{3, 6.0 / 9},
+		{7.5, 8.5 / 9},
+		{10, 1},
+		{100, 1},
+	}
+	for _, tt := range tests {
+		if got := Percentile(tt.value, param); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("Percentile(%v) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestRobustZScore(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 1000}
+	param := Fit(NormalizationZScore, values)
+	if param.Median != 4 || param.MAD != 2 {
+		t.Fatalf("Expected median 4 and MAD 2, got %v and %v", param.Median, param.MAD)
+	}
+
+	if got := RobustZScore(4, param); got != 0.5 {
+		t.Errorf("Expected 0.5 for the median, got %v", got)
+	}
+	if got := RobustZScore(1000, param); got != 1 {
+		t.Errorf("Expected outlier to be clipped to 1, got %v", got)
+	}
+	if got := RobustZScore(-1000, param); got != 0 {
+		t.Errorf("Expected outlier to be clipped to 0, got %v", got)
+	}
+}
+
+func TestTrimmedMinMax(t *testing.T) {
+	values := make([]float64, 101)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	values[100] = 1e9
+	param := Fit(NormalizationMinMax, values)
+
+	if param.Min != 1 {
+		t.Errorf("Expected trimmed min 1, got %v", param.Min)
+	}
+	if got := TrimmedMinMax(0, param); got != 0 {
+		t.Errorf("Expected 0 below the trimmed range, got %v", got)
+	}
+	if got := TrimmedMinMax(1e9, param); got != 1 {
+		t.Errorf("Expected 1 above the trimmed range, got %v", got)
+	}
+}
+
+func TestFitNormalization(t *testing.T) {
+	links := []string{"a", "b", "c"}
+	langEco := map[string]*LangEcoScore{
+		"a": {LangEcoImpact: 1, LangEcoPageRank: 0.1},
+		"b": {LangEcoImpact: 2, LangEcoPageRank: 0.2},
+	}
+	dist := map[string]*DistScore{
+		"c": {DistImpact: 5, DistPageRank: 1},
+	}
+
+	params := FitNormalization(NormalizationPercentile, links, map[string]*GitMetadata{}, langEco, dist)
+	for dimension, metrics := range requiredMetrics {
+		if dimension == "gitMetadataScore" {
+			continue
+		}
+		for _, metric := range metrics {
+			if params[dimension][metric] == nil {
+				t.Errorf("Missing fitted parameters for %s.%s", dimension, metric)
+			}
+		}
+	}
+	if params["gitMetadataScore"]["gitMetadataScore"] == nil {
+		t.Errorf("Missing fitted parameters for the git dimension")
+	}
+
+	linkScore := NewLinkScore(NewGitMetadataScore(), dist["c"], NewLangEcoScore(), 1)
+	linkScore.DistScore.CalculateDistScore(NormalizationPercentile)
+	linkScore.CalculateScore(NormalizationPercentile)
+	if linkScore.Score <= 0 {
+		t.Errorf("Expected positive score, got %v", linkScore.Score)
+	}
+}
@@ -0,0 +1,388 @@
+package score
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named scoring formula. Every weight, threshold and
+// coefficient used by the calculation is read from the active profile,
+// so tuning the formula does not need a rebuild.
+type Profile struct {
+	Name          string  `json:"name" yaml:"name"`
+	Normalization string  `json:"normalization" yaml:"normalization"`
+	SigmoidWeight float64 `json:"sigmoid_weight" yaml:"sigmoid_weight"`
+	// dimension -> metric -> weight
+	Weights map[string]map[string]float64 `json:"weights" yaml:"weights"`
+	// normalization -> dimension -> metric -> threshold
+	Thresholds map[string]map[string]map[string]float64 `json:"thresholds" yaml:"thresholds"`
+	// language ecosystem name -> weight
+	PackageWeight map[string]float64 `json:"package_weight" yaml:"package_weight"`
+	// distribution name -> coefficient, distributions not listed here use
+	// the package count ratio against homebrew
+	DistCoefficient map[string]float64 `json:"dist_coefficient,omitempty" yaml:"dist_coefficient,omitempty"`
+	// clip of the zscore normalization, defaults to 3
+	ZScoreClip float64 `json:"zscore_clip,omitempty" yaml:"zscore_clip,omitempty"`
+	// fraction trimmed from both ends by the minmax normalization,
+	// defaults to 0.01
+	MinMaxTrim float64 `json:"minmax_trim,omitempty" yaml:"minmax_trim,omitempty"`
+}
+
+const DefaultProfileName = "default"
+
+// requiredMetrics lists metrics which must have a weight and a threshold
+// in every profile.
+var requiredMetrics = map[string][]string{
+	"gitMetadataScore": {"created_since", "updated_since", "contributor_count", "commit_frequency", "org_count", "gitMetadataScore"},
+	"distScore":        {"dist_impact", "dist_pagerank", "distScore"},
+	"langEcoScore":     {"lang_eco_impact", "lang_eco_pagerank", "langEcoScore"},
+}
+
+// optionalMetrics lists metrics which are only used if the profile has a
+// weight for them, such metrics then need a threshold as well.
+var optionalMetrics = map[string][]string{
+	"gitMetadataScore": {
+		"bus_factor", "top_author_share", "active_maintainers",
+		"releases_last_year", "median_release_interval", "last_release_since", "signed_release_ratio",
+	},
+}
+
+var distTypeNames = map[repository.DistType]string{
+	repository.Debian:     "debian",
+	repository.Arch:       "arch",
+	repository.Homebrew:   "homebrew",
+	repository.Nix:        "nix",
+	repository.Alpine:     "alpine",
+	repository.Centos:     "centos",
+	repository.Aur:        "aur",
+	repository.Deepin:     "deepin",
+	repository.Fedora:     "fedora",
+	repository.Gentoo:     "gentoo",
+	repository.Ubuntu:     "ubuntu",
+	repository.OpenEuler:  "openeuler",
+	repository.OpenKylin:  "openkylin",
+	repository.OpenCloud:  "opencloud",
+	repository.OpenAnolis: "openanolis",
+}
+
+var langEcoTypeNames = func() map[repository.LangEcosystemType]string {
+	ret := make(map[repository.LangEcosystemType]string)
+	for _, t := range repository.LangEcosystemTypes() {
+		ret[t] = t.String()
+	}
+	return ret
+}()
+
+// DefaultProfile returns the built-in profile, which is used when no
+// profile file is given.
+func DefaultProfile() *Profile {
+	return &Profile{
+		Name:          DefaultProfileName,
+		Normalization: "log",
+		SigmoidWeight: 1.2,
+		Weights: map[string]map[string]float64{
+			"gitMetadataScore": {
+				"created_since":     1,
+				"updated_since":     -1,
+				"contributor_count": 2,
+				"commit_frequency":  1,
+				"org_count":         1,
+				"gitMetadataScore":  0.2,
+			},
+			"distScore": {
+				"dist_impact":   1,
+				"dist_pagerank": 1,
+				"downloads_3m":  0.5,
+				"distScore":     0.5,
+			},
+			"langEcoScore": {
+				"lang_eco_impact":   1,
+				"lang_eco_pagerank": 1,
+				"langEcoScore":      0.3,
+			},
+		},
+		Thresholds: map[string]map[string]map[string]float64{
+			"log": {
+				"gitMetadataScore": {
+					"created_since":     120,
+					"updated_since":     120,
+					"contributor_count": 40000,
+					"commit_frequency":  1000,
+					"org_count":         8400,
+					"gitMetadataScore":  5,
+				},
+				"distScore": {
+					"dist_impact":   22,
+					"dist_pagerank": 3,
+					"distScore":     1.5,
+				},
+				"langEcoScore": {
+					"lang_eco_impact":   1,
+					"lang_eco_pagerank": 0.0002,
+					"langEcoScore":      1.3,
+				},
+			},
+			"sigmoid": {
+				"gitMetadataScore": {
+					"created_since":     120,
+					"updated_since":     120,
+					"contributor_count": 10000,
+					"commit_frequency":  1000,
+					"org_count":         5000,
+					"gitMetadataScore":  5,
+				},
+				"distScore": {
+					"dist_impact":   6,
+					"dist_pagerank": 0.5,
+					"downloads_3m":  1700000,
+					"distScore":     1.5,
+				},
+				"langEcoScore": {
+					"lang_eco_impact":   0.1,
+					"lang_eco_pagerank": 0.0001,
+					"langEcoScore":      1.3,
+				},
+			},
+		},
+		PackageWeight: map[string]float64{
+			"npm":       1,
+			"go":        1,
+			"maven":     1,
+			"pypi":      1,
+			"nuget":     1,
+			"cargo":     1,
+			"rubygems":  1,
+			"packagist": 1,
+			"hackage":   1,
+			"hex":       1,
+			"conan":     1,
+			"conda":     1,
+			"swift":     1,
+		},
+	}
+}
+
+// LoadProfile reads a profile from a yaml or json file and validates it.
+// The format is chosen by the file extension.
+func LoadProfile(path string) (*Profile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Profile{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, p)
+	case ".json":
+		err = json.Unmarshal(content, p)
+	default:
+		return nil, fmt.Errorf("unsupported profile format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+
+	p.fillPackageWeights(path)
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// fillPackageWeights sets the weight of language ecosystems missing in
+// package_weight to the built-in one, so profiles written before an
+// ecosystem was added still load.
+func (p *Profile) fillPackageWeights(path string) {
+	defaults := DefaultProfile().PackageWeight
+	var missing []string
+	for _, name := range langEcoTypeNames {
+		if _, ok := p.PackageWeight[name]; ok {
+			continue
+		}
+		if p.PackageWeight == nil {
+			p.PackageWeight = make(map[string]float64)
+		}
+		p.PackageWeight[name] = defaults[name]
+		missing = append(missing, name)
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		log.Warnf("Profile %s has no package_weight for %s, using the built-in weights", path, strings.Join(missing, ", "))
+	}
+}
+
+// Validate checks that the profile is complete and every value is usable
+// by the normalization functions.
+func (p *Profile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	if !isNormalizationSupported(p.Normalization) {
+		return fmt.Errorf("unknown normalization: %s", p.Normalization)
+	}
+	if p.SigmoidWeight <= 0 {
+		return fmt.Errorf("sigmoid_weight must be positive")
+	}
+
+	for dimension, metrics := range requiredMetrics {
+		for _, metric := range metrics {
+			if _, ok := p.Weights[dimension][metric]; !ok {
+				return fmt.Errorf("missing weight %s.%s", dimension, metric)
+			}
+		}
+	}
+
+	if _, ok := p.Thresholds[p.Normalization]; !ok && !IsDataDriven(p.Normalization) {
+		return fmt.Errorf("missing thresholds for normalization %s", p.Normalization)
+	}
+	for mode, dimensions := range p.Thresholds {
+		if !isNormalizationSupported(mode) || IsDataDriven(mode) {
+			return fmt.Errorf("unknown normalization in thresholds: %s", mode)
+		}
+		for dimension, metrics := range requiredMetrics {
+			for _, metric := range metrics {
+				t, ok := dimensions[dimension][metric]
+				if !ok {
+					return fmt.Errorf("missing threshold %s.%s.%s", mode, dimension, metric)
+				}
+				if t <= 0 {
+					return fmt.Errorf("threshold %s.%s.%s must be positive", mode, dimension, metric)
+				}
+			}
+		}
+		for dimension, metrics := range optionalMetrics {
+			for _, metric := range metrics {
+				if !p.usesMetric(dimension, metric) {
+					continue
+				}
+				if t, ok := dimensions[dimension][metric]; !ok || t <= 0 {
+					return fmt.Errorf("threshold %s.%s.%s must be positive when the metric has a weight", mode, dimension, metric)
+				}
+			}
+		}
+	}
+
+	for name, w := range p.PackageWeight {
+		if !isKnownName(langEcoTypeNames, name) {
+			return fmt.Errorf("unknown language ecosystem in package_weight: %s", name)
+		}
+		if w < 0 {
+			return fmt.Errorf("package_weight %s must not be negative", name)
+		}
+	}
+
+	for name, c := range p.DistCoefficient {
+		if !isKnownName(distTypeNames, name) {
+			return fmt.Errorf("unknown distribution in dist_coefficient: %s", name)
+		}
+		if c < 0 {
+			return fmt.Errorf("dist_coefficient %s must not be negative", name)
+		}
+	}
+
+	if p.ZScoreClip < 0 {
+		return fmt.Errorf("zscore_clip must not be negative")
+	}
+	if p.MinMaxTrim < 0 || p.MinMaxTrim >= 0.5 {
+		return fmt.Errorf("minmax_trim must be in [0, 0.5)")
+	}
+	return nil
+}
+
+// Hash returns the sha256 of the canonical json form of the profile. It
+// identifies the contents of the profile only, scores also depend on the
+// package list for unlisted distributions and on the fitted parameters of
+// data-driven normalization modes.
+func (p *Profile) Hash() string {
+	// json.Marshal sorts map keys, so the output is stable
+	content, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// timeMetrics are the metrics measured against the time of the
+// calculation, so they change without any change of the input data.
+var timeMetrics = map[string][]string{
+	"gitMetadataScore": {"created_since", "updated_since", "last_release_since"},
+}
+
+// usesTimeMetrics reports whether the profile weights any metric in
+// timeMetrics.
+func (p *Profile) usesTimeMetrics() bool {
+	for dimension, metrics := range timeMetrics {
+		for _, metric := range metrics {
+			if p.Weights[dimension][metric] != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// usesMetric reports whether the metric has a weight in the profile.
+func (p *Profile) usesMetric(dimension, metric string) bool {
+	_, ok := p.Weights[dimension][metric]
+	return ok
+}
+
+func (p *Profile) packageWeight(t repository.LangEcosystemType) float64 {
+	return p.PackageWeight[langEcoTypeNames[t]]
+}
+
+func (p *Profile) distCoefficient(t repository.DistType) float64 {
+	return p.distCoefficientWith(PackageList, t)
+}
+
+func (p *Profile) distCoefficientWith(packageList map[repository.DistType]int, t repository.DistType) float64 {
+	if c, ok := p.DistCoefficient[distTypeNames[t]]; ok {
+		return c
+	}
+	if packageList[repository.Homebrew] == 0 {
+		return 0
+	}
+	return float64(packageList[t]) / float64(packageList[repository.Homebrew])
+}
+
+func isNormalizationSupported(mode string) bool {
+	switch mode {
+	case NormalizationLog, NormalizationSigmoid:
+		return true
+	default:
+		return IsDataDriven(mode)
+	}
+}
+
+func isKnownName[T comparable](names map[T]string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+var activeProfile = DefaultProfile()
+
+// UseProfile sets the profile used by all following calculations.
+func UseProfile(p *Profile) {
+	activeProfile = p
+}
+
+// ActiveProfile returns the profile currently used by the calculations.
+func ActiveProfile() *Profile {
+	return activeProfile
+}
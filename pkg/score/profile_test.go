@@ -0,0 +1,169 @@
+package score
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+func TestLoadDefaultProfileFile(t *testing.T) {
+	p, err := LoadProfile("../../cmd/scores-caculator/profiles/default.yaml")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+
+	if p.Hash() != DefaultProfile().Hash() {
+		t.Errorf("profiles/default.yaml differs from the built-in default profile")
+	}
+}
+
+func TestLoadProfileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	content := `{"name": "broken", "normalization": "log", "sigmoid_weight": 1.2}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadProfile(path); err == nil {
+		t.Errorf("Expected error for incomplete profile")
+	}
+}
+
+func TestLoadProfileMissingPackageWeights(t *testing.T) {
+	p := DefaultProfile()
+	for name := range p.PackageWeight {
+		if !slices.Contains([]string{"npm", "go", "maven", "pypi", "nuget", "cargo"}, name) {
+			delete(p.PackageWeight, name)
+		}
+	}
+	p.PackageWeight["npm"] = 2
+	content, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadProfile(path)
+	if err != nil {
+		t.Fatalf("Profile without the newer ecosystems is rejected: %v", err)
+	}
+	if loaded.PackageWeight["npm"] != 2 || loaded.PackageWeight["swift"] != DefaultProfile().PackageWeight["swift"] {
+		t.Errorf("Unexpected package weights: %v", loaded.PackageWeight)
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Profile)
+	}{
+		{"empty name", func(p *Profile) { p.Name = "" }},
+		{"unknown normalization", func(p *Profile) { p.Normalization = "linear" }},
+		{"missing weight", func(p *Profile) { delete(p.Weights["distScore"], "dist_impact") }},
+		{"zero threshold", func(p *Profile) { p.Thresholds["log"]["distScore"]["dist_impact"] = 0 }},
+		{"negative package weight", func(p *Profile) { p.PackageWeight["npm"] = -1 }},
+		{"unknown package weight", func(p *Profile) { p.PackageWeight["cpan"] = 1 }},
+		{"unknown distribution", func(p *Profile) { p.DistCoefficient = map[string]float64{"plan9": 1} }},
+		{"thresholds for data-driven mode", func(p *Profile) { p.Thresholds["zscore"] = p.Thresholds["log"] }},
+		{"minmax trim too large", func(p *Profile) { p.MinMaxTrim = 0.5 }},
+		{"optional metric without threshold", func(p *Profile) { p.Weights["gitMetadataScore"]["bus_factor"] = -1 }},
+	}
+
+	if err := DefaultProfile().Validate(); err != nil {
+		t.Fatalf("Default profile is invalid: %v", err)
+	}
+
+	dataDriven := DefaultProfile()
+	dataDriven.Normalization = NormalizationPercentile
+	delete(dataDriven.Thresholds, "sigmoid")
+	if err := dataDriven.Validate(); err != nil {
+		t.Errorf("Data-driven profile without its thresholds is invalid: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultProfile()
+			tt.modify(p)
+			if err := p.Validate(); err == nil {
+				t.Errorf("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestProfileHash(t *testing.T) {
+	p := DefaultProfile()
+	h := p.Hash()
+	if h != DefaultProfile().Hash() {
+		t.Errorf("Hash is not stable")
+	}
+
+	p.Weights["distScore"]["dist_impact"] = 2
+	if p.Hash() == h {
+		t.Errorf("Hash did not change after modifying weights")
+	}
+
+	q := DefaultProfile()
+	q.Normalization = NormalizationSigmoid
+	if q.Hash() == DefaultProfile().Hash() {
+		t.Errorf("Hash did not change after overriding normalization")
+	}
+}
+
+func TestDistCoefficient(t *testing.T) {
+	p := DefaultProfile()
+	p.DistCoefficient = map[string]float64{"debian": 2}
+	packageList := map[repository.DistType]int{
+		repository.Homebrew: 400,
+		repository.Alpine:   100,
+		repository.Nix:      1000,
+	}
+
+	if c := p.distCoefficientWith(packageList, repository.Debian); c != 2 {
+		t.Errorf("Expected the listed coefficient 2, got %v", c)
+	}
+	if c := p.distCoefficientWith(packageList, repository.Alpine); c != 0.25 {
+		t.Errorf("Expected 0.25 for a distribution smaller than homebrew, got %v", c)
+	}
+	if c := p.distCoefficientWith(packageList, repository.Nix); c != 2.5 {
+		t.Errorf("Expected 2.5, got %v", c)
+	}
+}
+
+func TestOptionalGitMetrics(t *testing.T) {
+	defer UseProfile(ActiveProfile())
+	gitMetadata := &GitMetadata{BusFactor: 2, TopAuthorShare: 0.7, ActiveMaintainers: 4}
+
+	UseProfile(DefaultProfile())
+	score := NewGitMetadataScore()
+	score.CalculateGitMetadataScore(gitMetadata, NormalizationLog)
+	if len(score.Contributions) != 5 {
+		t.Errorf("Expected 5 contributions without optional weights, got %d", len(score.Contributions))
+	}
+
+	p := DefaultProfile()
+	p.Weights["gitMetadataScore"]["bus_factor"] = -1
+	for _, mode := range []string{NormalizationLog, NormalizationSigmoid} {
+		p.Thresholds[mode]["gitMetadataScore"]["bus_factor"] = 10
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Profile with optional metric is invalid: %v", err)
+	}
+	UseProfile(p)
+	score = NewGitMetadataScore()
+	score.CalculateGitMetadataScore(gitMetadata, NormalizationLog)
+	if len(score.Contributions) != 6 {
+		t.Fatalf("Expected 6 contributions, got %d", len(score.Contributions))
+	}
+	c := score.Contributions[5]
+	if c.Metric != "bus_factor" || c.Value != 2 || c.Contribution >= 0 {
+		t.Errorf("Unexpected bus factor contribution: %+v", c)
+	}
+}
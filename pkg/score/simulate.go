@@ -0,0 +1,421 @@
+package score
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+)
+
+// RankChange is the position of a link in the live and the simulated
+// ranking. Ranks start from 1, and 0 means the link is not ranked.
+type RankChange struct {
+	GitLink        string
+	LiveRank       int
+	LiveScore      float64
+	SimulatedRank  int
+	SimulatedScore float64
+	// LiveRank - SimulatedRank, positive if the link moves up, 0 if it is
+	// not ranked in either ranking
+	Delta int
+}
+
+// RankingDiff compares the top N of the live ranking with a simulated one.
+type RankingDiff struct {
+	Round int
+	TopN  int
+	// simulated top N, in simulated order
+	Top []*RankChange
+	// links in the simulated top N but not in the live top N
+	Entered []*RankChange
+	// links in the live top N but not in the simulated top N
+	Left []*RankChange
+	// correlations over the union of both top N, only links ranked in
+	// both rankings are counted
+	KendallTau float64
+	Spearman   float64
+}
+
+// Clone returns a deep copy of the profile.
+func (p *Profile) Clone() *Profile {
+	ret := *p
+	ret.Weights = make(map[string]map[string]float64, len(p.Weights))
+	for dimension, metrics := range p.Weights {
+		ret.Weights[dimension] = maps.Clone(metrics)
+	}
+	ret.Thresholds = make(map[string]map[string]map[string]float64, len(p.Thresholds))
+	for mode, dimensions := range p.Thresholds {
+		ret.Thresholds[mode] = make(map[string]map[string]float64, len(dimensions))
+		for dimension, metrics := range dimensions {
+			ret.Thresholds[mode][dimension] = maps.Clone(metrics)
+		}
+	}
+	ret.PackageWeight = maps.Clone(p.PackageWeight)
+	ret.DistCoefficient = maps.Clone(p.DistCoefficient)
+	return &ret
+}
+
+// ApplyOverrides returns a copy of the profile with the given weights and
+// thresholds replaced. The result is not validated.
+func (p *Profile) ApplyOverrides(weights map[string]map[string]float64, thresholds map[string]map[string]map[string]float64) *Profile {
+	ret := p.Clone()
+	for dimension, metrics := range weights {
+		if ret.Weights[dimension] == nil {
+			ret.Weights[dimension] = make(map[string]float64)
+		}
+		for metric, w := range metrics {
+			ret.Weights[dimension][metric] = w
+		}
+	}
+	for mode, dimensions := range thresholds {
+		if ret.Thresholds[mode] == nil {
+			ret.Thresholds[mode] = make(map[string]map[string]float64)
+		}
+		for dimension, metrics := range dimensions {
+			if ret.Thresholds[mode][dimension] == nil {
+				ret.Thresholds[mode][dimension] = make(map[string]float64)
+			}
+			for metric, t := range metrics {
+				ret.Thresholds[mode][dimension][metric] = t
+			}
+		}
+	}
+	return ret
+}
+
+// LiveRound returns the latest published round, whose ranking a
+// simulation is compared with.
+func LiveRound(ac storage.AppDatabaseContext) (int, error) {
+	return repository.NewScoreRepository(ac).GetPublishedRoundBefore(math.MaxInt32)
+}
+
+// Simulate re-scores the links of round with profile and compares the
+// result with the live ranking of round. Every link is re-scored from the
+// inputs its score in the round is recorded with, at the time it was
+// scored, with the package counts of the round and, for the normalization
+// of the round, its fitted parameters. So the profile of the round gives
+// the live ranking back, and a diff only shows the effect of the profile.
+// Other data-driven normalization modes are fitted on the recorded
+// inputs.
+//
+// Nothing is written to the database, and the package level state of the
+// calculation is not used, so it is safe to simulate while scores are
+// calculated. Inputs are streamed, only the live and the simulated score
+// of every link are kept in memory.
+func Simulate(ac storage.AppDatabaseContext, round int, profile *Profile, normalization string, topN int) (*RankingDiff, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if !isNormalizationSupported(normalization) {
+		return nil, fmt.Errorf("unknown normalization: %s", normalization)
+	}
+	if _, ok := profile.Thresholds[normalization]; !ok && !IsDataDriven(normalization) {
+		return nil, fmt.Errorf("no thresholds for normalization %s", normalization)
+	}
+	if topN <= 0 {
+		return nil, fmt.Errorf("top n must be positive")
+	}
+
+	r, err := repository.NewScoreRoundRepository(ac).GetByRound(round)
+	if err != nil {
+		return nil, err
+	}
+	c := &calculator{profile: profile, params: FittedParams{}}
+	if r != nil && r.PackageList != nil {
+		if c.packageList, err = parsePackageList(*r.PackageList); err != nil {
+			return nil, fmt.Errorf("invalid package list of round %d: %w", round, err)
+		}
+	} else if c.packageList, err = fetchPackageList(ac); err != nil {
+		return nil, err
+	}
+
+	open := func() (iter.Seq[*recordedInput], error) {
+		return recordedInputs(ac, c, round)
+	}
+	if IsDataDriven(normalization) {
+		if r != nil && sqlString(r.Normalization) == normalization {
+			if c.params, err = FittedParamsOfRound(ac, round); err != nil {
+				return nil, err
+			}
+		} else if err := fitStream(c, normalization, func() (iter.Seq[*linkInput], error) {
+			inputs, err := open()
+			return atRecordedTime(c, inputs), err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	inputs, err := open()
+	if err != nil {
+		return nil, err
+	}
+	live, simulated, err := rescore(c, inputs, normalization, round)
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffRankings(live, simulated, topN)
+	diff.Round = round
+	return diff, nil
+}
+
+// recordedInput is the input a score of a round is calculated from.
+type recordedInput struct {
+	*linkInput
+	// the score in the round
+	live float64
+	// time the time-based metrics of the score are evaluated at
+	at time.Time
+}
+
+// recordedInputs streams the inputs of the scores of round, ordered by
+// git_link. Distribution and language ecosystem inputs are weighted with
+// the calculator.
+func recordedInputs(ac storage.AppDatabaseContext, c *calculator, round int) (iter.Seq[*recordedInput], error) {
+	scores, err := repository.NewScoreRepository(ac).QueryByRoundOrdered(round)
+	if err != nil {
+		return nil, err
+	}
+	gitMetrics, err := repository.NewGitMetricsRepository(ac).QueryByScoreRound(round)
+	if err != nil {
+		return nil, err
+	}
+	langEcosystems, err := repository.NewLangEcoLinkRepository(ac).QueryByScoreRound(round)
+	if err != nil {
+		return nil, err
+	}
+	distDependencies, err := repository.NewDistDependencyRepository(ac).QueryByScoreRound(round)
+	if err != nil {
+		return nil, err
+	}
+
+	roundRepo := repository.NewScoreRoundRepository(ac)
+	startTimes := make(map[int]time.Time)
+	// scores are evaluated at the start time of the round they are
+	// computed in, carried scores keep it
+	scoredAt := func(s *repository.Score) time.Time {
+		computed := *s.Round
+		if s.ComputedRound != nil {
+			computed = *s.ComputedRound
+		}
+		if t, ok := startTimes[computed]; ok {
+			return t
+		}
+		t := time.Time{}
+		if r, err := roundRepo.GetByRound(computed); err == nil && r != nil && r.StartTime != nil {
+			t = *r.StartTime
+		} else if s.UpdateTime != nil {
+			// rounds before score_rounds are scored about when written
+			t = *s.UpdateTime
+		}
+		startTimes[computed] = t
+		return t
+	}
+
+	return func(yield func(*recordedInput) bool) {
+		var cur *repository.Score
+		links := func(yield func(string) bool) {
+			for s := range scores {
+				if s.GitLink == nil || s.Score == nil {
+					continue
+				}
+				cur = s
+				if !yield(*s.GitLink) {
+					return
+				}
+			}
+		}
+		for in := range joinInputs(c, links, gitMetrics, langEcosystems, distDependencies) {
+			// joinInputs yields the input of a link before the next link
+			// is read, so cur is the score of in
+			if !yield(&recordedInput{linkInput: in, live: *cur.Score, at: scoredAt(cur)}) {
+				return
+			}
+		}
+	}, nil
+}
+
+// atRecordedTime sets the calculation time of c to the time each input is
+// recorded at before it is yielded.
+func atRecordedTime(c *calculator, inputs iter.Seq[*recordedInput]) iter.Seq[*linkInput] {
+	return func(yield func(*linkInput) bool) {
+		for in := range inputs {
+			c.now = in.at
+			if !yield(in.linkInput) {
+				return
+			}
+		}
+	}
+}
+
+// rescore calculates the score of every recorded input with c, and
+// returns the live and the simulated score of every link.
+func rescore(c *calculator, inputs iter.Seq[*recordedInput], normalization string, round int) (live, simulated map[string]float64, err error) {
+	live = make(map[string]float64)
+	simulated = make(map[string]float64)
+	for in := range inputs {
+		c.now = in.at
+		langEco, dist := in.langEcoOrEmpty(), in.distOrEmpty()
+		live[in.link] = in.live
+		simulated[in.link] = c.calculateLink(in.link, in.git, &langEco, &dist, normalization, round).Score
+	}
+	return live, simulated, c.err
+}
+
+// DiffRankings compares the top N of two rankings given as link -> score.
+// Links with equal scores are ordered by link.
+func DiffRankings(live, simulated map[string]float64, topN int) *RankingDiff {
+	liveRank := rank(live)
+	simulatedRank := rank(simulated)
+	change := func(link string) *RankChange {
+		c := &RankChange{
+			GitLink:        link,
+			LiveRank:       liveRank[link],
+			LiveScore:      live[link],
+			SimulatedRank:  simulatedRank[link],
+			SimulatedScore: simulated[link],
+		}
+		if c.LiveRank > 0 && c.SimulatedRank > 0 {
+			c.Delta = c.LiveRank - c.SimulatedRank
+		}
+		return c
+	}
+
+	diff := &RankingDiff{TopN: topN}
+	union := []string{}
+	for _, link := range ordered(simulated) {
+		if simulatedRank[link] > topN {
+			break
+		}
+		c := change(link)
+		diff.Top = append(diff.Top, c)
+		union = append(union, link)
+		if c.LiveRank == 0 || c.LiveRank > topN {
+			diff.Entered = append(diff.Entered, c)
+		}
+	}
+	for _, link := range ordered(live) {
+		if liveRank[link] > topN {
+			break
+		}
+		if r := simulatedRank[link]; r == 0 || r > topN {
+			diff.Left = append(diff.Left, change(link))
+			union = append(union, link)
+		}
+	}
+
+	x, y := []float64{}, []float64{}
+	for _, link := range union {
+		if liveRank[link] > 0 && simulatedRank[link] > 0 {
+			x = append(x, live[link])
+			y = append(y, simulated[link])
+		}
+	}
+	diff.KendallTau = KendallTau(x, y)
+	diff.Spearman = Spearman(x, y)
+	return diff
+}
+
+func ordered(scores map[string]float64) []string {
+	links := make([]string, 0, len(scores))
+	for link := range scores {
+		links = append(links, link)
+	}
+	slices.SortFunc(links, func(a, b string) int {
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return links
+}
+
+func rank(scores map[string]float64) map[string]int {
+	ret := make(map[string]int, len(scores))
+	for i, link := range ordered(scores) {
+		ret[link] = i + 1
+	}
+	return ret
+}
+
+// KendallTau returns the Kendall tau-b correlation of x and y, which
+// accounts for ties. It returns NaN if either side is constant.
+func KendallTau(x, y []float64) float64 {
+	var concordant, discordant, tiesX, tiesY float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			dx := cmp.Compare(x[i], x[j])
+			dy := cmp.Compare(y[i], y[j])
+			switch {
+			case dx == 0 && dy == 0:
+			case dx == 0:
+				tiesX++
+			case dy == 0:
+				tiesY++
+			case dx == dy:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+	return (concordant - discordant) / math.Sqrt((concordant+discordant+tiesX)*(concordant+discordant+tiesY))
+}
+
+// Spearman returns the Spearman rank correlation of x and y, tied values
+// get their average rank. It returns NaN if either side is constant.
+func Spearman(x, y []float64) float64 {
+	rx, ry := averageRanks(x), averageRanks(y)
+	if len(rx) == 0 {
+		return math.NaN()
+	}
+	var mean float64
+	for _, r := range rx {
+		mean += r
+	}
+	// both sides have the same mean rank
+	mean /= float64(len(rx))
+	var cov, varX, varY float64
+	for i := range rx {
+		cov += (rx[i] - mean) * (ry[i] - mean)
+		varX += (rx[i] - mean) * (rx[i] - mean)
+		varY += (ry[i] - mean) * (ry[i] - mean)
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+func averageRanks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int { return cmp.Compare(values[a], values[b]) })
+	ranks := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = float64(i+j)/2 + 1
+		}
+		i = j + 1
+	}
+	return ranks
+}
+
+// simulationProfileName is the name of profiles built from overrides.
+const simulationProfileName = "simulation"
+
+// SimulationProfile returns a copy of base, usually the profile of the
+// live round, with the given weights and thresholds replaced.
+func SimulationProfile(base *Profile, weights map[string]map[string]float64, thresholds map[string]map[string]map[string]float64) *Profile {
+	p := base.ApplyOverrides(weights, thresholds)
+	p.Name = simulationProfileName
+	return p
+}
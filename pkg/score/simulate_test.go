@@ -0,0 +1,167 @@
+package score
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKendallTauAndSpearman(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     []float64
+		tau, rho float64
+	}{
+		{"same order", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1, 1},
+		{"reversed", []float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1, -1},
+		{"one swap", []float64{1, 2, 3, 4}, []float64{1, 3, 2, 4}, 4.0 / 6, 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KendallTau(tt.x, tt.y); math.Abs(got-tt.tau) > 1e-9 {
+				t.Errorf("KendallTau = %v, expected %v", got, tt.tau)
+			}
+			if got := Spearman(tt.x, tt.y); math.Abs(got-tt.rho) > 1e-9 {
+				t.Errorf("Spearman = %v, expected %v", got, tt.rho)
+			}
+		})
+	}
+
+	if got := KendallTau([]float64{1, 1, 1}, []float64{1, 2, 3}); !math.IsNaN(got) {
+		t.Errorf("Expected NaN for constant input, got %v", got)
+	}
+}
+
+func TestDiffRankings(t *testing.T) {
+	live := map[string]float64{"a": 4, "b": 3, "c": 2, "d": 1}
+	simulated := map[string]float64{"a": 1, "b": 3, "c": 4, "d": 2, "e": 5}
+
+	diff := DiffRankings(live, simulated, 2)
+	if len(diff.Top) != 2 || diff.Top[0].GitLink != "e" || diff.Top[1].GitLink != "c" {
+		t.Fatalf("Unexpected simulated top: %+v", diff.Top)
+	}
+	if c := diff.Top[1]; c.LiveRank != 3 || c.SimulatedRank != 2 || c.Delta != 1 {
+		t.Errorf("Unexpected rank change for c: %+v", c)
+	}
+	if c := diff.Top[0]; c.LiveRank != 0 || c.Delta != 0 {
+		t.Errorf("Expected e to be unranked live: %+v", c)
+	}
+	if len(diff.Entered) != 2 {
+		t.Errorf("Expected 2 links entering, got %d", len(diff.Entered))
+	}
+	if len(diff.Left) != 2 || diff.Left[0].GitLink != "a" || diff.Left[1].GitLink != "b" {
+		t.Errorf("Unexpected links leaving: %+v", diff.Left)
+	}
+	// a, b, c are ranked in both, live order a > b > c, simulated c > b > a
+	if diff.KendallTau != -1 {
+		t.Errorf("Expected Kendall tau -1, got %v", diff.KendallTau)
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	base := DefaultProfile()
+	p := base.ApplyOverrides(
+		map[string]map[string]float64{"distScore": {"dist_impact": 3}},
+		map[string]map[string]map[string]float64{"log": {"langEcoScore": {"lang_eco_impact": 7}}},
+	)
+
+	if p.Weights["distScore"]["dist_impact"] != 3 || p.Thresholds["log"]["langEcoScore"]["lang_eco_impact"] != 7 {
+		t.Errorf("Overrides are not applied")
+	}
+	if base.Weights["distScore"]["dist_impact"] != 1 {
+		t.Errorf("Base profile is modified")
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Profile with overrides is invalid: %v", err)
+	}
+}
+
+func TestCalculatorProfile(t *testing.T) {
+	p := DefaultProfile()
+	p.Weights["distScore"]["dist_impact"] = 10
+	c := &calculator{profile: p, params: FittedParams{}}
+
+	distScore := &DistScore{DistImpact: 3}
+	distScore.calculate(c, NormalizationLog)
+	expected := 10 * LogNormalize(3, p.Thresholds[NormalizationLog]["distScore"]["dist_impact"])
+	if got := distScore.Contributions[0].Contribution; math.Abs(got-expected) > 1e-9 {
+		t.Errorf("Expected contribution %v with the calculator profile, got %v", expected, got)
+	}
+	if c.err != nil {
+		t.Errorf("Unexpected error: %v", c.err)
+	}
+
+	(&DistScore{}).calculate(c, NormalizationPercentile)
+	if c.err == nil {
+		t.Errorf("Expected an error for missing fitted parameters")
+	}
+}
+
+func TestRescoreLiveProfile(t *testing.T) {
+	roundStart := time.Now().AddDate(-1, 0, 0)
+	carriedStart := roundStart.AddDate(0, -3, 0)
+	var inputs []*recordedInput
+	for i := range 30 {
+		inputs = append(inputs, &recordedInput{
+			linkInput: &linkInput{
+				link: fmt.Sprintf("https://example.org/%02d", i),
+				git: &GitMetadata{
+					CreatedSince:     roundStart.AddDate(0, -i*7%40, 0),
+					UpdatedSince:     roundStart.AddDate(0, 0, -i*13%60),
+					ContributorCount: i * 3 % 17,
+					CommitFrequency:  float64(i % 9),
+					Org_Count:        i % 4,
+				},
+				dist: &DistScore{DistImpact: float64(i*11%23) / 23, DistPageRank: float64(i%5) / 5},
+			},
+			at: roundStart,
+		})
+	}
+	// scores carried forward from an older round are scored at its start
+	for _, in := range inputs[:10] {
+		in.at = carriedStart
+	}
+
+	for _, mode := range []string{NormalizationLog, NormalizationPercentile} {
+		t.Run(mode, func(t *testing.T) {
+			p := DefaultProfile()
+			round := &calculator{profile: p, params: FittedParams{}}
+			if IsDataDriven(mode) {
+				err := fitStream(round, mode, func() (iter.Seq[*linkInput], error) {
+					return atRecordedTime(round, slices.Values(inputs)), nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, in := range inputs {
+				round.now = in.at
+				langEco, dist := in.langEcoOrEmpty(), in.distOrEmpty()
+				in.live = round.calculateLink(in.link, in.git, &langEco, &dist, mode, 2).Score
+			}
+
+			// the same profile and the parameters of the round, evaluated
+			// at the recorded times instead of now
+			c := &calculator{profile: p.Clone(), params: round.params}
+			live, simulated, err := rescore(c, slices.Values(inputs), mode, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			diff := DiffRankings(live, simulated, 10)
+			if len(diff.Entered) != 0 || len(diff.Left) != 0 {
+				t.Errorf("Expected no links to enter or leave, got %d and %d", len(diff.Entered), len(diff.Left))
+			}
+			for _, change := range diff.Top {
+				if change.Delta != 0 || change.LiveScore != change.SimulatedScore {
+					t.Errorf("Expected an unchanged rank and score: %+v", change)
+				}
+			}
+			if diff.KendallTau != 1 {
+				t.Errorf("Expected Kendall tau 1, got %v", diff.KendallTau)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package score
+
+import (
+	"iter"
+	"slices"
+
+	log "github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+// cursor reads rows ordered by git_link, and hands them out grouped by
+// link for a merge join.
+type cursor[T any] struct {
+	next func() (*T, bool)
+	stop func()
+	key  func(*T) string
+	head *T
+	ok   bool
+}
+
+func newCursor[T any](seq iter.Seq[*T], key func(*T) string) *cursor[T] {
+	next, stop := iter.Pull(seq)
+	c := &cursor[T]{next: next, stop: stop, key: key}
+	c.head, c.ok = c.next()
+	return c
+}
+
+// take returns the rows of link. Rows before link have no link in the
+// join and are skipped. Links must be given in ascending order.
+func (c *cursor[T]) take(link string) []*T {
+	var ret []*T
+	for c.ok && c.key(c.head) < link {
+		c.head, c.ok = c.next()
+	}
+	for c.ok && c.key(c.head) == link {
+		ret = append(ret, c.head)
+		c.head, c.ok = c.next()
+	}
+	return ret
+}
+
+func gitLinkOf(link *string) string {
+	if link == nil {
+		return ""
+	}
+	return *link
+}
+
+// joinInputs merge joins the sources on git_link. All sequences must be
+// ordered by git_link in the same order as comparing strings in go.
+func joinInputs(
+	c *calculator,
+	links iter.Seq[string],
+	gitMetrics iter.Seq[*repository.GitMetric],
+	langEcosystems iter.Seq[*repository.LangEcosystem],
+	distDependencies iter.Seq[*repository.DistDependency],
+) iter.Seq[*linkInput] {
+	return func(yield func(*linkInput) bool) {
+		git := newCursor(gitMetrics, func(m *repository.GitMetric) string { return gitLinkOf(m.GitLink) })
+		defer git.stop()
+		langEco := newCursor(langEcosystems, func(l *repository.LangEcosystem) string { return gitLinkOf(l.GitLink) })
+		defer langEco.stop()
+		dist := newCursor(distDependencies, func(d *repository.DistDependency) string { return gitLinkOf(d.GitLink) })
+		defer dist.stop()
+
+		for link := range links {
+			in := &linkInput{link: link}
+			if rows := git.take(link); len(rows) > 0 {
+				in.git = collectGitMetrics(slices.Values(rows))[link]
+			}
+			if rows := langEco.take(link); len(rows) > 0 {
+				in.langEco = collectLangEcoMetadata(c, slices.Values(rows))[link]
+			}
+			if rows := dist.take(link); len(rows) > 0 {
+				in.dist = collectDistMetadata(c, slices.Values(rows))[link]
+			}
+			if !yield(in) {
+				return
+			}
+		}
+	}
+}
+
+// linksIn yields the links which are in any of sets. All sequences must be
+// ordered in the same order as comparing strings in go.
+func linksIn(links iter.Seq[string], sets ...iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		cursors := make([]*cursor[string], 0, len(sets))
+		for _, set := range sets {
+			c := newCursor(pointers(set), func(link *string) string { return *link })
+			defer c.stop()
+			cursors = append(cursors, c)
+		}
+		for link := range links {
+			// cursors not taken from skip the link on the next take
+			if !slices.ContainsFunc(cursors, func(c *cursor[string]) bool { return len(c.take(link)) > 0 }) {
+				continue
+			}
+			if !yield(link) {
+				return
+			}
+		}
+	}
+}
+
+func pointers[T any](seq iter.Seq[T]) iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for v := range seq {
+			if !yield(&v) {
+				return
+			}
+		}
+	}
+}
+
+// chunks groups the inputs into chunks of at most size.
+func chunks[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) < size {
+				continue
+			}
+			if !yield(chunk) {
+				return
+			}
+			chunk = make([]T, 0, size)
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// streamInputs streams the input of all links after the given link,
+// ordered by git_link. Distribution and language ecosystem inputs are
+// weighted with the calculator.
+func streamInputs(ac storage.AppDatabaseContext, c *calculator, after string) (iter.Seq[*linkInput], error) {
+	links, err := repository.NewAllGitLinkRepository(ac).QueryOrdered(after)
+	if err != nil {
+		return nil, err
+	}
+	gitMetrics, err := repository.NewGitMetricsRepository(ac).QueryOrdered(after)
+	if err != nil {
+		return nil, err
+	}
+	langEcosystems, err := repository.NewLangEcoLinkRepository(ac).QueryOrdered(after)
+	if err != nil {
+		return nil, err
+	}
+	distDependencies, err := repository.NewDistDependencyRepository(ac).QueryOrdered(after)
+	if err != nil {
+		return nil, err
+	}
+	return joinInputs(c, links, gitMetrics, langEcosystems, distDependencies), nil
+}
+
+// FitNormalizationStream is the same as FitNormalization, but streams the
+// population from the database in two passes.
+func FitNormalizationStream(ac storage.AppDatabaseContext, normalization string) FittedParams {
+	c := active()
+	err := fitStream(c, normalization, func() (iter.Seq[*linkInput], error) {
+		return streamInputs(ac, c, "")
+	})
+	if err != nil {
+		log.Fatalf("Failed to fit %s parameters: %v", normalization, err)
+	}
+	UseFittedParams(c.params)
+	return c.params
+}
+
+// fitStream fits the parameters of a data-driven normalization mode into
+// the calculator. open streams the population, it is called once for each
+// of the two passes.
+func fitStream(c *calculator, normalization string, open func() (iter.Seq[*linkInput], error)) error {
+	f := newNormalizationFitter(c, normalization)
+	for _, add := range []func(*linkInput){f.addMetrics, f.addDimensions} {
+		inputs, err := open()
+		if err != nil {
+			return err
+		}
+		for in := range inputs {
+			add(in)
+		}
+		f.fit()
+	}
+	return c.err
+}
+
+// StreamOptions controls CalculateStream.
+type StreamOptions struct {
+	Round         int
+	Normalization string
+	// number of links written at a time
+	BatchSize int
+	// links up to it are already written, empty to start from the
+	// beginning
+	Checkpoint string
+	// if not nil, only these links are calculated, e.g. the links planned
+	// by PlanIncremental, in ascending order
+	Links iter.Seq[string]
+	// number of links already written
+	Recomputed int
+}
+
+// CalculateStream calculates and writes the scores in chunks ordered by
+// git_link, so memory is bounded by the batch size. After each chunk is
+// written, its last link is recorded as the checkpoint of the round, so a
+// crashed round can be resumed. It returns the total number of written
+// links, including opts.Recomputed.
+func CalculateStream(ac storage.AppDatabaseContext, opts StreamOptions) int {
+	var inputs iter.Seq[[]*linkInput]
+	if opts.Links != nil {
+		inputs = chunksOfLinks(ac, opts.Links, opts.Checkpoint, max(opts.BatchSize, 1))
+	} else {
+		all, err := streamInputs(ac, active(), opts.Checkpoint)
+		if err != nil {
+			log.Fatalf("Failed to fetch score inputs: %v", err)
+		}
+		inputs = chunks(all, max(opts.BatchSize, 1))
+	}
+
+	roundRepo := repository.NewScoreRoundRepository(ac)
+	recomputed := opts.Recomputed
+	for chunk := range inputs {
+		links := make([]string, 0, len(chunk))
+		gitMetrics := make(map[string]*GitMetadata)
+		langEco := make(map[string]*LangEcoScore)
+		dist := make(map[string]*DistScore)
+		for _, in := range chunk {
+			links = append(links, in.link)
+			if in.git != nil {
+				gitMetrics[in.link] = in.git
+			}
+			if in.langEco != nil {
+				langEco[in.link] = in.langEco
+			}
+			if in.dist != nil {
+				dist[in.link] = in.dist
+			}
+		}
+		UpdateScore(ac, CalculateScores(links, gitMetrics, langEco, dist, opts.Normalization, opts.Round))
+
+		recomputed += len(chunk)
+		checkpoint := links[len(links)-1]
+		err := roundRepo.InsertOrUpdate(&repository.ScoreRound{
+			Round:      &opts.Round,
+			Checkpoint: &checkpoint,
+			Recomputed: sqlutil.ToData(recomputed),
+		})
+		if err != nil {
+			log.Fatalf("Failed to record checkpoint: %v", err)
+		}
+		log.Infof("Round %d: %d links written, checkpoint %s", opts.Round, recomputed, checkpoint)
+	}
+	return recomputed
+}
+
+// chunksOfLinks fetches the inputs of the given links after checkpoint
+// chunk by chunk.
+func chunksOfLinks(ac storage.AppDatabaseContext, links iter.Seq[string], checkpoint string, size int) iter.Seq[[]*linkInput] {
+	return func(yield func([]*linkInput) bool) {
+		for chunk := range chunks(links, size) {
+			chunk = slices.DeleteFunc(chunk, func(link string) bool { return link <= checkpoint })
+			if len(chunk) == 0 {
+				continue
+			}
+			gitMetrics := FetchGitMetricsByLinks(ac, chunk)
+			langEco := FetchLangEcoMetadataByLinks(ac, chunk)
+			dist := FetchDistMetadataByLinks(ac, chunk)
+			inputs := make([]*linkInput, 0, len(chunk))
+			for _, link := range chunk {
+				inputs = append(inputs, &linkInput{link: link, git: gitMetrics[link], langEco: langEco[link], dist: dist[link]})
+			}
+			if !yield(inputs) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package score
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+func TestJoinInputs(t *testing.T) {
+	links := []string{"a", "b", "c", "d"}
+	gitMetrics := []*repository.GitMetric{
+		// rows of links not in all_gitlinks are skipped
+		{ID: sqlutil.ToData(int64(1)), GitLink: sqlutil.ToData("0")},
+		{ID: sqlutil.ToData(int64(2)), GitLink: sqlutil.ToData("b")},
+		{ID: sqlutil.ToData(int64(3)), GitLink: sqlutil.ToData("d")},
+	}
+	langEcosystems := []*repository.LangEcosystem{
+		{ID: sqlutil.ToData(int64(4)), GitLink: sqlutil.ToData("a"), Type: sqlutil.ToData(repository.Go),
+			LangEcoImpact: sqlutil.ToData(0.5), Lang_eco_pagerank: sqlutil.ToData(0.1), DepCount: sqlutil.ToData(2)},
+		{ID: sqlutil.ToData(int64(5)), GitLink: sqlutil.ToData("a"), Type: sqlutil.ToData(repository.Npm),
+			LangEcoImpact: sqlutil.ToData(0.25), Lang_eco_pagerank: sqlutil.ToData(0.2), DepCount: sqlutil.ToData(3)},
+		{ID: sqlutil.ToData(int64(6)), GitLink: sqlutil.ToData("bb")},
+	}
+	distDependencies := []*repository.DistDependency{
+		{ID: sqlutil.ToData(int64(7)), GitLink: sqlutil.ToData("c"), Type: sqlutil.ToData(repository.Debian),
+			DepImpact: sqlutil.ToData(0.5), DepCount: sqlutil.ToData(4), PageRank: sqlutil.ToData(0.3), Downloads_3m: sqlutil.ToData(0)},
+	}
+
+	got := []*linkInput{}
+	for in := range joinInputs(active(), slices.Values(links), slices.Values(gitMetrics), slices.Values(langEcosystems), slices.Values(distDependencies)) {
+		got = append(got, in)
+	}
+	if len(got) != len(links) {
+		t.Fatalf("Expected %d inputs, got %d", len(links), len(got))
+	}
+	for i, in := range got {
+		if in.link != links[i] {
+			t.Errorf("Expected link %s at %d, got %s", links[i], i, in.link)
+		}
+	}
+	if got[0].git != nil || got[1].git == nil || got[2].git != nil || got[3].git == nil {
+		t.Errorf("Git metrics joined to wrong links")
+	}
+	if got[0].langEco == nil || len(got[0].langEco.LangEcosystems) != 2 || got[1].langEco != nil {
+		t.Errorf("Language ecosystems joined to wrong links")
+	}
+	if got[0].dist != nil || got[2].dist == nil || got[3].dist != nil {
+		t.Errorf("Distribution dependencies joined to wrong links")
+	}
+}
+
+func TestLinksIn(t *testing.T) {
+	links := []string{"a", "b", "c", "d", "e"}
+	updated := []string{"b", "x"}
+	unscored := []string{"a", "b", "d"}
+
+	got := slices.Collect(linksIn(slices.Values(links), slices.Values(updated), slices.Values(unscored)))
+	if want := []string{"a", "b", "d"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := slices.Collect(linksIn(slices.Values(links))); len(got) != 0 {
+		t.Errorf("Expected no links without sets, got %v", got)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	for _, tc := range []struct {
+		n, size int
+		want    []int
+	}{
+		{0, 3, nil},
+		{3, 3, []int{3}},
+		{7, 3, []int{3, 3, 1}},
+	} {
+		values := make([]int, tc.n)
+		for i := range values {
+			values[i] = i
+		}
+		var sizes []int
+		next := 0
+		for chunk := range chunks(slices.Values(values), tc.size) {
+			sizes = append(sizes, len(chunk))
+			for _, v := range chunk {
+				if v != next {
+					t.Errorf("Expected %d, got %d", next, v)
+				}
+				next++
+			}
+		}
+		if !slices.Equal(sizes, tc.want) {
+			t.Errorf("Chunks of %d by %d: expected sizes %v, got %v", tc.n, tc.size, tc.want, sizes)
+		}
+	}
+}
@@ -101,3 +101,49 @@ func (app *appDatabaseContext) Close() error {
 	}
 	return nil
 }
+
+// txContext runs the statements of a AppDatabaseContext in a transaction.
+type txContext struct {
+	AppDatabaseContext
+	tx *sql.Tx
+}
+
+// Transaction runs fn with a context whose statements are executed in one
+// transaction, which is committed if fn returns nil and rolled back
+// otherwise. The rows returned by Query must be closed before the next
+// statement.
+func Transaction(appDb AppDatabaseContext, fn func(tx AppDatabaseContext) error) error {
+	db, err := appDb.GetDatabaseConnection()
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(&txContext{AppDatabaseContext: appDb, tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *txContext) GetDatabaseConnection() (*sql.DB, error) {
+	return nil, fmt.Errorf("database connection is not available in a transaction")
+}
+
+func (t *txContext) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.Exec(query, args...)
+}
+
+func (t *txContext) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.Query(query, args...)
+}
+
+func (t *txContext) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRow(query, args...)
+}
+
+func (t *txContext) Close() error {
+	return nil
+}
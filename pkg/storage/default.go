@@ -33,3 +33,9 @@ func GetDefaultAppDatabaseContext() AppDatabaseContext {
 
 	return defaultAppDatabase
 }
+
+// SetDefaultAppDatabaseContext replaces the default app database, e.g. by
+// one from NewAppDatabaseWithDb in tests.
+func SetDefaultAppDatabaseContext(appDb AppDatabaseContext) {
+	defaultAppDatabase = appDb
+}
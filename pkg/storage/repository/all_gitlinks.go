@@ -10,6 +10,9 @@ type AllGitLinkRepository interface {
 	/** QUERY **/
 	Query() (iter.Seq[string], error)
 	QueryByLink(search string) (iter.Seq[string], error)
+	// Query links after the given link, ordered by git_link in "C"
+	// collation, which is the same as comparing strings in go
+	QueryOrdered(after string) (iter.Seq[string], error)
 	QueryCache() (iter.Seq[string], error)
 	MakeCache() error
 }
@@ -23,6 +26,12 @@ func (a *allGitLinkRepository) QueryByLink(search string) (iter.Seq[string], err
 	return gitlinksQuery(a.ctx, "SELECT git_link FROM all_gitlinks WHERE git_link LIKE $1", search)
 }
 
+// QueryOrdered implements AllGitLinkRepository.
+func (a *allGitLinkRepository) QueryOrdered(after string) (iter.Seq[string], error) {
+	return gitlinksQuery(a.ctx, `SELECT git_link FROM all_gitlinks
+		WHERE git_link COLLATE "C" > $1 ORDER BY git_link COLLATE "C"`, after)
+}
+
 // MakeCache implements AllGitLinkRepository.
 func (a *allGitLinkRepository) MakeCache() error {
 	_, err := a.ctx.Exec(`DROP TABLE IF EXISTS all_gitlinks_cache;
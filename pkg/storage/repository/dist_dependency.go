@@ -8,6 +8,7 @@ import (
 
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
 	"github.com/samber/lo"
 )
 
@@ -20,6 +21,17 @@ type DistDependencyRepository interface {
 	QueryByType(distType int) (iter.Seq[*DistDependency], error)
 	GetByLink(packageName string, distType int) (*DistDependency, error)
 	QueryDistCountByType(distType DistType) (int, error) // Get the total number of packages in a Distro.
+	QueryByLinks(links []string) (iter.Seq[*DistDependency], error)
+	// Query links after the given link, ordered by git_link in "C"
+	// collation
+	QueryOrdered(after string) (iter.Seq[*DistDependency], error)
+	// Query the dependencies the scores of the round are calculated from,
+	// ordered by git_link in "C" collation
+	QueryByScoreRound(round int) (iter.Seq[*DistDependency], error)
+	// Query links with a dependency updated after t, or of one of the
+	// distributions, ordered by git_link in "C" collation
+	QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error)
+	QueryLinksByTypes(distTypes []DistType) (iter.Seq[string], error)
 
 	/** INSERT/UPDATE **/
 	// update_time will be updated automatically
@@ -74,6 +86,41 @@ func (r *distLinkRepository) Query() (iter.Seq[*DistDependency], error) {
 	return sqlutil.Query[DistDependency](r.ctx, `SELECT DISTINCT ON (git_link, "type") id, git_link, type, dep_impact, dep_count, page_rank, update_time, downloads_3m FROM distribution_dependencies ORDER BY git_link, "type", id DESC`)
 }
 
+// QueryByLinks implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryByLinks(links []string) (iter.Seq[*DistDependency], error) {
+	return sqlutil.Query[DistDependency](r.ctx, `SELECT DISTINCT ON (git_link, "type") id, git_link, type, dep_impact, dep_count, page_rank, update_time, downloads_3m FROM distribution_dependencies WHERE git_link = ANY($1) ORDER BY git_link, "type", id DESC`, pq.Array(links))
+}
+
+// QueryOrdered implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryOrdered(after string) (iter.Seq[*DistDependency], error) {
+	return sqlutil.Query[DistDependency](r.ctx, `SELECT * FROM (SELECT DISTINCT ON (git_link, "type") id, git_link, type, dep_impact, dep_count, page_rank, update_time, downloads_3m FROM distribution_dependencies WHERE git_link COLLATE "C" > $1 ORDER BY git_link, "type", id DESC) t ORDER BY git_link COLLATE "C"`, after)
+}
+
+// QueryByScoreRound implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryByScoreRound(round int) (iter.Seq[*DistDependency], error) {
+	return sqlutil.Query[DistDependency](r.ctx, `SELECT d.id, d.git_link, d.type, d.dep_impact, d.dep_count, d.page_rank, d.update_time, d.downloads_3m
+		FROM distribution_dependencies d
+		JOIN `+ScoreDistTableName+` sd ON sd.distribution_dependencies_id = d.id
+		JOIN `+ScoreTableName+` s ON s.id = sd.score_id
+		WHERE s.round = $1 ORDER BY d.git_link COLLATE "C"`, round)
+}
+
+// QueryLinksUpdatedSince implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error) {
+	return gitlinksQuery(r.ctx, `SELECT DISTINCT git_link COLLATE "C" FROM `+DistDependencyTableName+`
+		WHERE update_time > $1 ORDER BY 1`, t)
+}
+
+// QueryLinksByTypes implements DistributionDependencyRepository.
+func (r *distLinkRepository) QueryLinksByTypes(distTypes []DistType) (iter.Seq[string], error) {
+	types := make([]int64, 0, len(distTypes))
+	for _, t := range distTypes {
+		types = append(types, int64(t))
+	}
+	return gitlinksQuery(r.ctx, `SELECT DISTINCT git_link COLLATE "C" FROM `+DistDependencyTableName+`
+		WHERE type = ANY($1) ORDER BY 1`, pq.Array(types))
+}
+
 // QueryDistCountByType implements DistributionDependencyRepository.
 func (r *distLinkRepository) QueryDistCountByType(distType DistType) (int, error) {
 	var tableName string
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type GitActivityRepository interface {
+	/** QUERY **/
+	// Query the activity of link, ordered by month
+	QueryByLink(link string) (iter.Seq[*GitActivity], error)
+
+	/** INSERT/UPDATE **/
+	// Replace all months of link
+	// NOTE: update_time will be updated automatically
+	Replace(link string, data []*GitActivity) error
+}
+
+// GitActivity is the activity of a repository in a month, see
+// git.MonthlyActivity.
+type GitActivity struct {
+	GitLink         *string    `pk:"true"`
+	Month           *time.Time `pk:"true"`
+	Commits         *int
+	MergeCommits    *int
+	ActiveAuthors   *int
+	NewContributors *int
+	ActiveOrgs      *int
+	UpdateTime      **time.Time
+}
+
+const GitActivityTableName = "git_activity"
+
+type gitActivityRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitActivityRepository = (*gitActivityRepository)(nil)
+
+func NewGitActivityRepository(appDb storage.AppDatabaseContext) GitActivityRepository {
+	return &gitActivityRepository{ctx: appDb}
+}
+
+// QueryByLink implements GitActivityRepository.
+func (g *gitActivityRepository) QueryByLink(link string) (iter.Seq[*GitActivity], error) {
+	return sqlutil.QueryCommon[GitActivity](g.ctx, GitActivityTableName, "WHERE git_link = $1 ORDER BY month", link)
+}
+
+// Replace implements GitActivityRepository.
+func (g *gitActivityRepository) Replace(link string, data []*GitActivity) error {
+	if link == "" {
+		return ErrInvalidInput
+	}
+	_, err := g.ctx.Exec(`DELETE FROM `+GitActivityTableName+` WHERE git_link = $1`, link)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, d := range data {
+		if d.Month == nil {
+			return ErrInvalidInput
+		}
+		d.GitLink = &link
+		d.UpdateTime = sqlutil.ToNullable(now)
+	}
+	return sqlutil.BatchInsert(g.ctx, GitActivityTableName, data)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
+)
+
+type GitHygieneRepository interface {
+	/** QUERY **/
+	QueryByGitMetricID(id int64) (*GitHygiene, error)
+
+	/** INSERT/UPDATE **/
+	// NOTE: update_time will be updated automatically
+	InsertOrUpdate(data *GitHygiene) error
+}
+
+// GitHygiene is the security and maintenance setup of a repository when
+// the row of git_metrics is collected, see git.Hygiene.
+type GitHygiene struct {
+	GitMetricID    *int64 `pk:"true"`
+	SecurityPolicy **bool
+	CodeOwners     **bool
+	Fuzzing        **bool
+	CISystems      **pq.StringArray `column:"ci_systems"`
+	DependencyBots **pq.StringArray
+	// null if no commit or annotated tag is walked
+	SignedCommitRatio **float64
+	SignedTagRatio    **float64
+	UpdateTime        **time.Time
+}
+
+const GitHygieneTableName = "git_hygiene"
+
+type gitHygieneRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitHygieneRepository = (*gitHygieneRepository)(nil)
+
+func NewGitHygieneRepository(appDb storage.AppDatabaseContext) GitHygieneRepository {
+	return &gitHygieneRepository{ctx: appDb}
+}
+
+// QueryByGitMetricID implements GitHygieneRepository.
+func (g *gitHygieneRepository) QueryByGitMetricID(id int64) (*GitHygiene, error) {
+	return sqlutil.QueryCommonFirst[GitHygiene](g.ctx, GitHygieneTableName, "WHERE git_metric_id = $1", id)
+}
+
+// InsertOrUpdate implements GitHygieneRepository.
+func (g *gitHygieneRepository) InsertOrUpdate(data *GitHygiene) error {
+	if data.GitMetricID == nil {
+		return ErrInvalidInput
+	}
+	data.UpdateTime = sqlutil.ToNullable(time.Now())
+	return sqlutil.Upsert(g.ctx, GitHygieneTableName, data)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type GitLogCheckpointRepository interface {
+	/** QUERY **/
+	GetByLink(link string) (*GitLogCheckpoint, error)
+
+	/** INSERT/UPDATE **/
+	// NOTE: update_time will be updated automatically
+	InsertOrUpdate(data *GitLogCheckpoint) error
+}
+
+// GitLogCheckpoint is the encoded git.LogCheckpoint of the last collection
+// of a repository.
+type GitLogCheckpoint struct {
+	GitLink    *string `pk:"true"`
+	Checkpoint *[]byte
+	UpdateTime **time.Time
+}
+
+const GitLogCheckpointTableName = "git_log_checkpoints"
+
+type gitLogCheckpointRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitLogCheckpointRepository = (*gitLogCheckpointRepository)(nil)
+
+func NewGitLogCheckpointRepository(appDb storage.AppDatabaseContext) GitLogCheckpointRepository {
+	return &gitLogCheckpointRepository{ctx: appDb}
+}
+
+// GetByLink implements GitLogCheckpointRepository.
+func (g *gitLogCheckpointRepository) GetByLink(link string) (*GitLogCheckpoint, error) {
+	return sqlutil.QueryCommonFirst[GitLogCheckpoint](g.ctx, GitLogCheckpointTableName, "WHERE git_link = $1", link)
+}
+
+// InsertOrUpdate implements GitLogCheckpointRepository.
+func (g *gitLogCheckpointRepository) InsertOrUpdate(data *GitLogCheckpoint) error {
+	if data.GitLink == nil || *data.GitLink == "" || data.Checkpoint == nil {
+		return ErrInvalidInput
+	}
+	data.UpdateTime = sqlutil.ToNullable(time.Now())
+	return sqlutil.Upsert(g.ctx, GitLogCheckpointTableName, data)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type GitManifestRepository interface {
+	/** QUERY **/
+	// Query the manifests of the latest collected commit of link
+	QueryByLink(link string) (iter.Seq[*GitManifest], error)
+	QueryDependencies(manifestID int64) (iter.Seq[*GitManifestDependency], error)
+	// Query the manifests of the latest collected commit of all links
+	QueryLatest() (iter.Seq[*GitManifest], error)
+	// Query the dependencies of the manifests returned by QueryLatest
+	QueryLatestDependencies() (iter.Seq[*GitManifestDependency], error)
+
+	/** INSERT/UPDATE **/
+	// Replace the manifests of link with the ones of commit, removing the
+	// manifests of all other commits of link
+	// NOTE: update_time will be updated automatically
+	Replace(link string, commit string, data []*GitManifestWithDependencies) error
+}
+
+// GitManifest is the package declared by a manifest or lock file of a
+// repository at a commit.
+type GitManifest struct {
+	ID           *int64 `pk:"true" generated:"true"`
+	GitLink      *string
+	CommitHash   *string
+	ManifestPath *string
+	// directory of the workspace the manifest belongs to, see
+	// git.Manifest
+	Workspace  **string
+	Ecosystem  **string
+	Name       **string
+	Version    **string
+	UpdateTime **time.Time
+}
+
+type GitManifestDependency struct {
+	ID         *int64 `pk:"true" generated:"true"`
+	ManifestID *int64
+	Ecosystem  **string
+	Name       **string
+	// the version constraint in manifests, or the resolved version in lock
+	// files
+	VersionConstraint **string
+}
+
+type GitManifestWithDependencies struct {
+	Manifest     *GitManifest
+	Dependencies []*GitManifestDependency
+}
+
+const GitManifestTableName = "git_manifests"
+const GitManifestDependencyTableName = "git_manifest_dependencies"
+
+type gitManifestRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitManifestRepository = (*gitManifestRepository)(nil)
+
+func NewGitManifestRepository(appDb storage.AppDatabaseContext) GitManifestRepository {
+	return &gitManifestRepository{ctx: appDb}
+}
+
+// QueryByLink implements GitManifestRepository.
+func (g *gitManifestRepository) QueryByLink(link string) (iter.Seq[*GitManifest], error) {
+	return sqlutil.QueryCommon[GitManifest](g.ctx, GitManifestTableName,
+		`WHERE git_link = $1 AND commit_hash = (
+			SELECT commit_hash FROM `+GitManifestTableName+` WHERE git_link = $1 ORDER BY id DESC LIMIT 1)
+		ORDER BY id`, link)
+}
+
+// QueryDependencies implements GitManifestRepository.
+func (g *gitManifestRepository) QueryDependencies(manifestID int64) (iter.Seq[*GitManifestDependency], error) {
+	return sqlutil.QueryCommon[GitManifestDependency](g.ctx, GitManifestDependencyTableName,
+		"WHERE manifest_id = $1 ORDER BY id", manifestID)
+}
+
+// manifests of the latest collected commit of every git link
+const latestGitManifestsQuery = `SELECT id FROM ` + GitManifestTableName + ` WHERE (git_link, commit_hash) IN (
+	SELECT DISTINCT ON (git_link) git_link, commit_hash FROM ` + GitManifestTableName + ` ORDER BY git_link, id DESC)`
+
+// QueryLatest implements GitManifestRepository.
+func (g *gitManifestRepository) QueryLatest() (iter.Seq[*GitManifest], error) {
+	return sqlutil.QueryCommon[GitManifest](g.ctx, GitManifestTableName,
+		`WHERE id IN (`+latestGitManifestsQuery+`) ORDER BY id`)
+}
+
+// QueryLatestDependencies implements GitManifestRepository.
+func (g *gitManifestRepository) QueryLatestDependencies() (iter.Seq[*GitManifestDependency], error) {
+	return sqlutil.QueryCommon[GitManifestDependency](g.ctx, GitManifestDependencyTableName,
+		`WHERE manifest_id IN (`+latestGitManifestsQuery+`) ORDER BY id`)
+}
+
+// Replace implements GitManifestRepository.
+func (g *gitManifestRepository) Replace(link string, commit string, data []*GitManifestWithDependencies) error {
+	if link == "" || commit == "" {
+		return ErrInvalidInput
+	}
+	return storage.Transaction(g.ctx, func(tx storage.AppDatabaseContext) error {
+		// manifests of older commits are deleted as well, so a commit
+		// without manifests does not keep serving the old ones.
+		// Dependencies are deleted in cascade.
+		_, err := tx.Exec(`DELETE FROM `+GitManifestTableName+` WHERE git_link = $1`, link)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, d := range data {
+			d.Manifest.GitLink = &link
+			d.Manifest.CommitHash = &commit
+			d.Manifest.UpdateTime = sqlutil.ToNullable(now)
+			if err := sqlutil.Insert(tx, GitManifestTableName, d.Manifest); err != nil {
+				return err
+			}
+			if len(d.Dependencies) == 0 {
+				continue
+			}
+			if d.Manifest.ID == nil {
+				return ErrInvalidInput
+			}
+			for _, dep := range d.Dependencies {
+				dep.ManifestID = d.Manifest.ID
+			}
+			if err := sqlutil.BatchInsert(tx, GitManifestDependencyTableName, d.Dependencies); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
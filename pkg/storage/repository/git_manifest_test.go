@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/samber/lo"
+)
+
+func newMockManifestRepository(t *testing.T) (GitManifestRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewGitManifestRepository(storage.NewAppDatabaseWithDb(db)), mock
+}
+
+var deleteManifestsOfLink = regexp.QuoteMeta(`DELETE FROM `+GitManifestTableName+` WHERE git_link = $1`) + `$`
+
+func TestReplaceGitManifestsWithoutManifests(t *testing.T) {
+	repo, mock := newMockManifestRepository(t)
+
+	// the manifests of the previous HEAD must be deleted although the new
+	// one declares nothing
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteManifestsOfLink).
+		WithArgs("https://github.com/a/b").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	if err := repo.Replace("https://github.com/a/b", "new-head", nil); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReplaceGitManifestsRollback(t *testing.T) {
+	repo, mock := newMockManifestRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteManifestsOfLink).
+		WithArgs("https://github.com/a/b").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO ` + GitManifestTableName).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec(`INSERT INTO ` + GitManifestDependencyTableName).
+		WillReturnError(fmt.Errorf("connection reset"))
+	mock.ExpectRollback()
+
+	err := repo.Replace("https://github.com/a/b", "new-head", []*GitManifestWithDependencies{{
+		Manifest: &GitManifest{ManifestPath: lo.ToPtr("package.json")},
+		Dependencies: []*GitManifestDependency{
+			{Name: lo.ToPtr(lo.ToPtr("left-pad"))},
+		},
+	}})
+	if err == nil {
+		t.Fatal("Replace() error = nil, want the insert error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -16,6 +16,17 @@ type GitMetricsRepository interface {
 	/** QUERY **/
 	Query() (iter.Seq[*GitMetric], error)
 	QueryByLink(link string) (*GitMetric, error)
+	// Query the latest metrics of each link in links
+	QueryByLinks(links []string) (iter.Seq[*GitMetric], error)
+	// Query the latest metric of links after the given link, ordered by
+	// git_link in "C" collation
+	QueryOrdered(after string) (iter.Seq[*GitMetric], error)
+	// Query the metrics the scores of the round are calculated from,
+	// ordered by git_link in "C" collation
+	QueryByScoreRound(round int) (iter.Seq[*GitMetric], error)
+	// Query links with a metric updated after t, ordered by git_link in
+	// "C" collation
+	QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error)
 
 	/** INSERT/UPDATE **/
 	// NOTE: update_time will be updated automatically
@@ -44,10 +55,19 @@ type GitMetric struct {
 	ContributorCount **int
 	CommitFrequency  **float64
 	OrgCount         **int
-	License          **pq.StringArray
-	Language         **pq.StringArray
-	CloneValid       **bool
-	UpdateTime       **time.Time
+	// see git.Repo
+	BusFactor             **int
+	TopAuthorShare        **float64
+	ActiveMaintainers     **int
+	ReleasesLastYear      **int
+	MedianReleaseInterval **float64
+	LastRelease           **time.Time
+	SignedReleaseRatio    **float64
+	License               **pq.StringArray
+	LicenseExpression     **string
+	Language              **pq.StringArray
+	CloneValid            **bool
+	UpdateTime            **time.Time
 }
 
 type GitFile struct {
@@ -123,6 +143,41 @@ func (g *gitmetricsRepository) QueryByLink(link string) (*GitMetric, error) {
 	return sqlutil.QueryCommonFirst[GitMetric](g.ctx, GitMetricTableName, "WHERE git_link = $1 ORDER BY id DESC", link)
 }
 
+// QueryByLinks implements GitMetricsRepository.
+func (g *gitmetricsRepository) QueryByLinks(links []string) (iter.Seq[*GitMetric], error) {
+	subQuery := fmt.Sprintf(`(SELECT DISTINCT ON (git_link)
+	 *
+	FROM %s
+	WHERE git_link = ANY($1)
+	ORDER BY git_link, id DESC)`, GitMetricTableName)
+	return sqlutil.QueryCommon[GitMetric](g.ctx, subQuery, "", pq.Array(links))
+}
+
+// QueryOrdered implements GitMetricsRepository.
+func (g *gitmetricsRepository) QueryOrdered(after string) (iter.Seq[*GitMetric], error) {
+	subQuery := fmt.Sprintf(`(SELECT DISTINCT ON (git_link)
+	 *
+	FROM %s
+	WHERE git_link COLLATE "C" > $1
+	ORDER BY git_link, id DESC) t`, GitMetricTableName)
+	return sqlutil.QueryCommon[GitMetric](g.ctx, subQuery, `ORDER BY git_link COLLATE "C"`, after)
+}
+
+// QueryByScoreRound implements GitMetricsRepository.
+func (g *gitmetricsRepository) QueryByScoreRound(round int) (iter.Seq[*GitMetric], error) {
+	subQuery := fmt.Sprintf(`(SELECT g.* FROM %s g
+	JOIN %s sg ON sg.git_metrics_id = g.id
+	JOIN %s s ON s.id = sg.score_id
+	WHERE s.round = $1) t`, GitMetricTableName, ScoreGitTableName, ScoreTableName)
+	return sqlutil.QueryCommon[GitMetric](g.ctx, subQuery, `ORDER BY git_link COLLATE "C"`, round)
+}
+
+// QueryLinksUpdatedSince implements GitMetricsRepository.
+func (g *gitmetricsRepository) QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error) {
+	return gitlinksQuery(g.ctx, `SELECT DISTINCT git_link COLLATE "C" FROM `+GitMetricTableName+`
+		WHERE update_time > $1 ORDER BY 1`, t)
+}
+
 // DeleteGitFile implements GitMetricsRepository.
 func (g *gitmetricsRepository) DeleteGitFile(link string) error {
 	return sqlutil.Delete(g.ctx, GitFilesTableName, &GitFile{GitLink: &link})
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"iter"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/samber/lo"
+)
+
+type GitRelationshipRepository interface {
+	/** QUERY **/
+	QueryBySource(source string) (iter.Seq[*GitRelationship], error)
+	// Query the repositories embedded by link, ordered by path
+	QueryEmbeds(link string) (iter.Seq[*GitRelationship], error)
+
+	/** INSERT/UPDATE **/
+	// Replace all relationships of the source
+	ReplaceBySource(source string, data []*GitRelationship) error
+	// Replace the repositories embedded by link
+	ReplaceEmbeds(link string, data []*GitRelationship) error
+}
+
+// GitRelationship is a dependency from a git link to another one.
+type GitRelationship struct {
+	Fromgitlink *string
+	Togitlink   *string
+	// distribution, or the lang ecosystem of the manifests, e.g. npm, or
+	// submodule or vendored for embeds
+	Source *string
+	// depends, or embeds if the code of Togitlink is in Fromgitlink
+	Kind *string
+	// path and version of embedded copies
+	Path    **string
+	Version **string
+}
+
+const GitRelationshipTableName = "git_relationships"
+
+// GitRelationshipSourceDistribution is the source of relationships
+// projected from the packages of distributions.
+const GitRelationshipSourceDistribution = "distribution"
+
+const (
+	GitRelationshipKindDepends = "depends"
+	GitRelationshipKindEmbeds  = "embeds"
+)
+
+type gitRelationshipRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ GitRelationshipRepository = (*gitRelationshipRepository)(nil)
+
+func NewGitRelationshipRepository(appDb storage.AppDatabaseContext) GitRelationshipRepository {
+	return &gitRelationshipRepository{ctx: appDb}
+}
+
+// QueryBySource implements GitRelationshipRepository.
+func (g *gitRelationshipRepository) QueryBySource(source string) (iter.Seq[*GitRelationship], error) {
+	return sqlutil.QueryCommon[GitRelationship](g.ctx, GitRelationshipTableName, "WHERE source = $1", source)
+}
+
+// ReplaceBySource implements GitRelationshipRepository.
+func (g *gitRelationshipRepository) ReplaceBySource(source string, data []*GitRelationship) error {
+	if source == "" {
+		return ErrInvalidInput
+	}
+	for _, d := range data {
+		if d.Fromgitlink == nil || d.Togitlink == nil {
+			return ErrInvalidInput
+		}
+		d.Source = &source
+		if d.Kind == nil {
+			d.Kind = lo.ToPtr(GitRelationshipKindDepends)
+		}
+	}
+
+	return storage.Transaction(g.ctx, func(tx storage.AppDatabaseContext) error {
+		_, err := tx.Exec(`DELETE FROM `+GitRelationshipTableName+` WHERE source = $1`, source)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return sqlutil.BatchInsert(tx, GitRelationshipTableName, data)
+	})
+}
+
+// QueryEmbeds implements GitRelationshipRepository.
+func (g *gitRelationshipRepository) QueryEmbeds(link string) (iter.Seq[*GitRelationship], error) {
+	return sqlutil.QueryCommon[GitRelationship](g.ctx, GitRelationshipTableName,
+		"WHERE fromgitlink = $1 AND kind = $2 ORDER BY path", link, GitRelationshipKindEmbeds)
+}
+
+// ReplaceEmbeds implements GitRelationshipRepository.
+func (g *gitRelationshipRepository) ReplaceEmbeds(link string, data []*GitRelationship) error {
+	if link == "" {
+		return ErrInvalidInput
+	}
+	for _, d := range data {
+		if d.Togitlink == nil || d.Source == nil {
+			return ErrInvalidInput
+		}
+		d.Fromgitlink = &link
+		d.Kind = lo.ToPtr(GitRelationshipKindEmbeds)
+	}
+
+	return storage.Transaction(g.ctx, func(tx storage.AppDatabaseContext) error {
+		_, err := tx.Exec(`DELETE FROM `+GitRelationshipTableName+` WHERE fromgitlink = $1 AND kind = $2`,
+			link, GitRelationshipKindEmbeds)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return sqlutil.BatchInsert(tx, GitRelationshipTableName, data)
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/samber/lo"
+)
+
+func TestReplaceBySourceRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	defer db.Close()
+	repo := NewGitRelationshipRepository(storage.NewAppDatabaseWithDb(db))
+
+	// the edges of the last run must survive a failed insert
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM ` + GitRelationshipTableName + ` WHERE source = $1`)).
+		WithArgs("npm").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO ` + GitRelationshipTableName).
+		WillReturnError(fmt.Errorf("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.ReplaceBySource("npm", []*GitRelationship{
+		{Fromgitlink: lo.ToPtr("https://github.com/a/app"), Togitlink: lo.ToPtr("https://github.com/a/left-pad")},
+	})
+	if err == nil {
+		t.Fatal("ReplaceBySource() error = nil, want the insert error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/HUSTSecLab/OpenSift/pkg/storage"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+	"github.com/lib/pq"
 	"github.com/samber/lo"
 )
 
@@ -14,6 +15,19 @@ type LangEcoLinkRepository interface {
 	QueryByLink(link string) (iter.Seq[*LangEcosystem], error)
 	GetByLinkAndType(link string, typ LangEcosystemType) (*LangEcosystem, error)
 	Query() (iter.Seq[*LangEcosystem], error) // Get all LangEcosystem Information in order to calculate the score.
+	QueryByLinks(links []string) (iter.Seq[*LangEcosystem], error)
+	// Query links after the given link, ordered by git_link in "C"
+	// collation
+	QueryOrdered(after string) (iter.Seq[*LangEcosystem], error)
+	// Query the ecosystems the scores of the round are calculated from,
+	// ordered by git_link in "C" collation
+	QueryByScoreRound(round int) (iter.Seq[*LangEcosystem], error)
+	// Query links with an ecosystem updated after t, ordered by git_link
+	// in "C" collation
+	QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error)
+	// Query links with an ecosystem of typ written by the collectors of
+	// registries, i.e. without a source
+	QueryRegistryLinks(typ LangEcosystemType) (iter.Seq[string], error)
 
 	/** INSERT/UPDATE **/
 	// NOTE: update_time will be updated automatically
@@ -33,8 +47,47 @@ const (
 	NuGet
 	Cargo
 	Others
+	// registries below are added after Others, so the values stored in
+	// lang_ecosystems do not change
+	RubyGems
+	Packagist
+	Hackage
+	Hex
+	Conan
+	Conda
+	Swift
 )
 
+var langEcosystemTypeNames = map[LangEcosystemType]string{
+	Npm:       "npm",
+	Go:        "go",
+	Maven:     "maven",
+	Pypi:      "pypi",
+	NuGet:     "nuget",
+	Cargo:     "cargo",
+	Others:    "others",
+	RubyGems:  "rubygems",
+	Packagist: "packagist",
+	Hackage:   "hackage",
+	Hex:       "hex",
+	Conan:     "conan",
+	Conda:     "conda",
+	Swift:     "swift",
+}
+
+// String returns the lowercase name of the registry.
+func (t LangEcosystemType) String() string {
+	if name, ok := langEcosystemTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// LangEcosystemTypes returns all registries, except Others.
+func LangEcosystemTypes() []LangEcosystemType {
+	return []LangEcosystemType{Npm, Go, Maven, Pypi, NuGet, Cargo, RubyGems, Packagist, Hackage, Hex, Conan, Conda, Swift}
+}
+
 type langEcoLinkRepository struct {
 	appDb storage.AppDatabaseContext
 }
@@ -47,10 +100,16 @@ type LangEcosystem struct {
 	Lang_eco_pagerank *float64
 	DepCount          *int
 	UpdateTime        *time.Time
+	// null for the collectors of registries, see LangEcosystemSourceGitGraph
+	Source **string
 }
 
 const LangEcosystemTableName = "lang_ecosystems"
 
+// LangEcosystemSourceGitGraph is the source of the metrics computed from
+// the git dependency graph.
+const LangEcosystemSourceGitGraph = "gitgraph"
+
 var _ LangEcoLinkRepository = (*langEcoLinkRepository)(nil)
 
 func NewLangEcoLinkRepository(appDb storage.AppDatabaseContext) LangEcoLinkRepository {
@@ -66,6 +125,43 @@ func (l *langEcoLinkRepository) Query() (iter.Seq[*LangEcosystem], error) {
 		FROM lang_ecosystems ORDER BY git_link, type, id DESC`)
 }
 
+// QueryByLinks implements LangEcoLinkRepository.
+func (l *langEcoLinkRepository) QueryByLinks(links []string) (iter.Seq[*LangEcosystem], error) {
+	return sqlutil.Query[LangEcosystem](l.appDb, `SELECT DISTINCT ON (git_link, type)
+		id, git_link, type, lang_eco_impact, lang_eco_pagerank, dep_count, update_time
+		FROM lang_ecosystems WHERE git_link = ANY($1) ORDER BY git_link, type, id DESC`, pq.Array(links))
+}
+
+// QueryOrdered implements LangEcoLinkRepository.
+func (l *langEcoLinkRepository) QueryOrdered(after string) (iter.Seq[*LangEcosystem], error) {
+	return sqlutil.Query[LangEcosystem](l.appDb, `SELECT * FROM (SELECT DISTINCT ON (git_link, type)
+		id, git_link, type, lang_eco_impact, lang_eco_pagerank, dep_count, update_time
+		FROM lang_ecosystems WHERE git_link COLLATE "C" > $1 ORDER BY git_link, type, id DESC) t
+		ORDER BY git_link COLLATE "C"`, after)
+}
+
+// QueryByScoreRound implements LangEcoLinkRepository.
+func (l *langEcoLinkRepository) QueryByScoreRound(round int) (iter.Seq[*LangEcosystem], error) {
+	return sqlutil.Query[LangEcosystem](l.appDb, `SELECT
+		l.id, l.git_link, l.type, l.lang_eco_impact, l.lang_eco_pagerank, l.dep_count, l.update_time
+		FROM lang_ecosystems l
+		JOIN `+ScoreLangTableName+` sl ON sl.lang_ecosystems_id = l.id
+		JOIN `+ScoreTableName+` s ON s.id = sl.score_id
+		WHERE s.round = $1 ORDER BY l.git_link COLLATE "C"`, round)
+}
+
+// QueryLinksUpdatedSince implements LangEcoLinkRepository.
+func (l *langEcoLinkRepository) QueryLinksUpdatedSince(t time.Time) (iter.Seq[string], error) {
+	return gitlinksQuery(l.appDb, `SELECT DISTINCT git_link COLLATE "C" FROM `+LangEcosystemTableName+`
+		WHERE update_time > $1 ORDER BY 1`, t)
+}
+
+// QueryRegistryLinks implements LangEcoLinkRepository.
+func (l *langEcoLinkRepository) QueryRegistryLinks(typ LangEcosystemType) (iter.Seq[string], error) {
+	return gitlinksQuery(l.appDb, `SELECT DISTINCT git_link FROM `+LangEcosystemTableName+`
+		WHERE type = $1 AND source IS NULL`, typ)
+}
+
 // BatchInsertOrUpdate implements LangEcoLinkRepository.
 func (l *langEcoLinkRepository) BatchInsertOrUpdate(data []*LangEcosystem) error {
 	for _, d := range data {
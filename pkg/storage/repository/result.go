@@ -19,6 +19,7 @@ type ResultRepository interface {
 	QueryGitDetailsByScoreID(scoreID int) (iter.Seq[*ResultGitDetail], error)
 	QueryLangDetailsByScoreID(scoreID int) (iter.Seq[*ResultLangDetail], error)
 	QueryDistDetailsByScoreID(scoreID int) (iter.Seq[*ResultDistDetail], error)
+	QueryBreakdownByScoreID(scoreID int) (iter.Seq[*ScoreBreakdown], error)
 	QueryRankingCache(skip int, take int) (iter.Seq[*RankingResult], error)
 	MakeRankingCache() error
 }
@@ -31,6 +32,8 @@ type Result struct {
 	GitScore   **float64
 	Score      **float64
 	UpdateTime **time.Time
+	// SPDX expression of the git metrics of the score
+	License **string
 }
 
 type RankingResult struct {
@@ -41,25 +44,43 @@ type RankingResult struct {
 	GitScore   **float64
 	Score      **float64
 	UpdateTime **time.Time
+	License    **string
 	Ranking    *int
 }
 
 type ResultGitDetail struct {
-	License          **pq.StringArray
-	Language         **pq.StringArray
-	CommitFrequency  **float64
-	CreatedSince     **time.Time
-	UpdatedSince     **time.Time
-	OrgCount         **int
-	ContributorCount **int
-	UpdateTime       **time.Time
+	License               **pq.StringArray
+	LicenseExpression     **string
+	Language              **pq.StringArray
+	CommitFrequency       **float64
+	CreatedSince          **time.Time
+	UpdatedSince          **time.Time
+	OrgCount              **int
+	ContributorCount      **int
+	BusFactor             **int
+	TopAuthorShare        **float64
+	ActiveMaintainers     **int
+	ReleasesLastYear      **int
+	MedianReleaseInterval **float64
+	LastRelease           **time.Time
+	SignedReleaseRatio    **float64
+	UpdateTime            **time.Time
+	// see GitHygiene, all null if not collected
+	SecurityPolicy    **bool
+	CodeOwners        **bool
+	Fuzzing           **bool
+	CISystems         **pq.StringArray `column:"ci_systems"`
+	DependencyBots    **pq.StringArray
+	SignedCommitRatio **float64
+	SignedTagRatio    **float64
 }
 
 type ResultLangDetail struct {
-	Type          **int
-	LangEcoImpact **float64
-	DepCount      **int
-	UpdateTime    **time.Time
+	Type            **int
+	LangEcoImpact   **float64
+	LangEcoPageRank **float64
+	DepCount        **int
+	UpdateTime      **time.Time
 }
 
 type ResultDistDetail struct {
@@ -70,6 +91,14 @@ type ResultDistDetail struct {
 	UpdateTime **time.Time
 }
 
+// scoreLicenseQuery selects the license expression of the git metrics of
+// the score s.
+const scoreLicenseQuery = `select gm.license_expression
+	from scores_git sg
+	join git_metrics gm on sg.git_metrics_id = gm.id
+	where sg.score_id = s.id
+	limit 1`
+
 type resultRepository struct {
 	ctx storage.AppDatabaseContext
 }
@@ -98,9 +127,11 @@ func (r *resultRepository) QueryHistoriesByLink(link string, skip int, take int)
 		s.lang_score as lang_score,
 		s.git_score as git_score,
 		s.score as score,
-		s.update_time as update_time
+		s.update_time as update_time,
+		(`+scoreLicenseQuery+`) as license
 	from all_gitlinks_cache ag
 	left join scores s on ag.git_link = s.git_link
+		and not exists (select 1 from score_rounds sr where sr.round = s.round and sr.blocked)
 	where ag.git_link = $1 order by s.id desc limit $2 offset $3
 	`, link, take, skip)
 	return rows, err
@@ -116,7 +147,8 @@ func (r *resultRepository) CountByLink(search string) (int, error) {
 
 // CountHistoriesByLink implements ResultRepository.
 func (r *resultRepository) CountHistoriesByLink(link string) (int, error) {
-	row := r.ctx.QueryRow(`select count(*) from scores where git_link = $1`, link)
+	row := r.ctx.QueryRow(`select count(*) from scores s where git_link = $1
+		and not exists (select 1 from score_rounds sr where sr.round = s.round and sr.blocked)`, link)
 	var count int
 	err := row.Scan(&count)
 	return count, err
@@ -139,15 +171,31 @@ func (r *resultRepository) QueryDistDetailsByScoreID(scoreID int) (iter.Seq[*Res
 func (r *resultRepository) QueryGitDetailsByScoreID(scoreID int) (iter.Seq[*ResultGitDetail], error) {
 	return sqlutil.Query[ResultGitDetail](r.ctx, `select
 		gm.license as license,
+		gm.license_expression as license_expression,
 		gm.language as language,
 		gm.commit_frequency as commit_frequency,
 		gm.created_since as created_since,
 		gm.updated_since as updated_since,
 		gm.org_count as org_count,
 		gm.contributor_count as contributor_count,
-		gm.update_time as update_time
+		gm.bus_factor as bus_factor,
+		gm.top_author_share as top_author_share,
+		gm.active_maintainers as active_maintainers,
+		gm.releases_last_year as releases_last_year,
+		gm.median_release_interval as median_release_interval,
+		gm.last_release as last_release,
+		gm.signed_release_ratio as signed_release_ratio,
+		gm.update_time as update_time,
+		gh.security_policy as security_policy,
+		gh.code_owners as code_owners,
+		gh.fuzzing as fuzzing,
+		gh.ci_systems as ci_systems,
+		gh.dependency_bots as dependency_bots,
+		gh.signed_commit_ratio as signed_commit_ratio,
+		gh.signed_tag_ratio as signed_tag_ratio
 	from scores_git sg
 	left join git_metrics gm on sg.git_metrics_id = gm.id
+	left join git_hygiene gh on gh.git_metric_id = gm.id
 	where sg.score_id = $1`, scoreID)
 }
 
@@ -156,6 +204,7 @@ func (r *resultRepository) QueryLangDetailsByScoreID(scoreID int) (iter.Seq[*Res
 	return sqlutil.Query[ResultLangDetail](r.ctx, `select
 		le.type as type,
 		le.lang_eco_impact as lang_eco_impact,
+		le.lang_eco_pagerank as lang_eco_page_rank,
 		le.dep_count as dep_count,
 		le.update_time as update_time
 	from scores_lang sl
@@ -163,6 +212,12 @@ func (r *resultRepository) QueryLangDetailsByScoreID(scoreID int) (iter.Seq[*Res
 	where sl.score_id = $1`, scoreID)
 }
 
+// QueryBreakdownByScoreID implements ResultRepository.
+func (r *resultRepository) QueryBreakdownByScoreID(scoreID int) (iter.Seq[*ScoreBreakdown], error) {
+	return sqlutil.QueryCommon[ScoreBreakdown](r.ctx, ScoreBreakdownTableName,
+		"WHERE score_id = $1", scoreID)
+}
+
 // QueryWithCountByLink implements ResultRepository.
 func (r *resultRepository) QueryByLink(search string, skip int, take int) (iter.Seq[*Result], error) {
 	rows, err := sqlutil.Query[Result](r.ctx, `select * from (
@@ -173,9 +228,11 @@ func (r *resultRepository) QueryByLink(search string, skip int, take int) (iter.
 			s.lang_score as lang_score,
 			s.git_score as git_score,
 			s.score as score,
-			s.update_time as update_time
+			s.update_time as update_time,
+			(`+scoreLicenseQuery+`) as license
 		from all_gitlinks_cache ag
 		left join scores s on ag.git_link = s.git_link
+			and not exists (select 1 from score_rounds sr where sr.round = s.round and sr.blocked)
 		where ag.git_link like $1
 		order by ag.git_link, s.id desc) as t
 	order by score desc nulls last
@@ -192,7 +249,8 @@ func (r *resultRepository) GetByScoreID(scoreID int) (*Result, error) {
 		s.lang_score as lang_score,
 		s.git_score as git_score,
 		s.score as score,
-		s.update_time as update_time
+		s.update_time as update_time,
+		(`+scoreLicenseQuery+`) as license
 	from all_gitlinks_cache ag
 	left join scores s on ag.git_link = s.git_link
 	where s.id = $1
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"iter"
+	"strings"
 	"time"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/logger"
@@ -17,6 +18,20 @@ type ScoreRepository interface {
 	Query() (iter.Seq[*Score], error)
 	GetByGitLink(distID int64) (*Score, error)
 	GetRound() (int, error)
+	// Get the latest round before the given round which is finished and
+	// not blocked, or 0 if there is none. Rounds not in score_rounds are
+	// published.
+	GetPublishedRoundBefore(round int) (int, error)
+	QueryByRound(round int) (iter.Seq[*Score], error)
+	// Query the scores of the round ordered by git_link in "C" collation
+	QueryByRoundOrdered(round int) (iter.Seq[*Score], error)
+	// Query links in all_gitlinks which have no score in the round, ordered
+	// by git_link in "C" collation
+	QueryUnscoredLinks(round int) (iter.Seq[string], error)
+	// Query links whose score in the round was computed in a round started
+	// before the given time, or in a round which is not recorded, ordered
+	// by git_link in "C" collation
+	QueryLinksComputedBefore(round int, t time.Time) (iter.Seq[string], error)
 
 	/** INSERT/UPDATE **/
 
@@ -34,6 +49,17 @@ type ScoreRepository interface {
 	// NOTE: This function only observe thd id field in DistDependencies,
 	//       LangEcosystems, GitMetrics
 	BatchInsertOrUpdate(scores []*Score) error
+	// Copy scores of fromRound, with their details and breakdown, into
+	// toRound in one transaction. Links which already have a score in
+	// toRound and links no longer in all_gitlinks are skipped. Returns the
+	// number of copied scores.
+	CarryForward(fromRound int, toRound int) (int64, error)
+
+	/** DELETE **/
+
+	// Delete scores of the round after the given link in "C" collation,
+	// with their details and breakdown. Returns the number of deleted scores.
+	DeleteByRoundAfter(round int, after string) (int64, error)
 }
 
 type Score struct {
@@ -48,12 +74,32 @@ type Score struct {
 	Score            *float64
 	UpdateTime       *time.Time
 	Round            *int
+	ProfileName      *string
+	ProfileHash      *string
+	// round in which the score was computed, which is before Round if the
+	// score is carried forward
+	ComputedRound *int
+	Breakdown     []*ScoreBreakdown `ignore:"true"`
+}
+
+// ScoreBreakdown is the normalized value and weighted contribution of
+// one metric in a score. Dimension is the parent node of the metric,
+// which is "score" for the three dimension scores.
+type ScoreBreakdown struct {
+	ScoreID      *int64  `pk:"true"`
+	Dimension    *string `pk:"true"`
+	Metric       *string `pk:"true"`
+	Value        *float64
+	Normalized   *float64
+	Weight       *float64
+	Contribution *float64
 }
 
 const ScoreTableName = "scores"
 const ScoreDistTableName = "scores_dist"
 const ScoreLangTableName = "scores_lang"
 const ScoreGitTableName = "scores_git"
+const ScoreBreakdownTableName = "score_breakdowns"
 
 var _ ScoreRepository = (*scoreRepository)(nil)
 
@@ -196,6 +242,17 @@ func (s *scoreRepository) InsertOrUpdate(score *Score) error {
 		}
 	}
 
+	// Insert Breakdown
+	if len(score.Breakdown) != 0 {
+		for _, b := range score.Breakdown {
+			b.ScoreID = &id
+		}
+		err := sqlutil.BatchInsert(s.ctx, ScoreBreakdownTableName, score.Breakdown)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -205,6 +262,16 @@ func (s *scoreRepository) Query() (iter.Seq[*Score], error) {
 	return sqlutil.QueryCommon[Score](s.ctx, subQuery, "")
 }
 
+// QueryByRound implements ScoreRepository.
+func (s *scoreRepository) QueryByRound(round int) (iter.Seq[*Score], error) {
+	return sqlutil.QueryCommon[Score](s.ctx, ScoreTableName, "WHERE round = $1", round)
+}
+
+// QueryByRoundOrdered implements ScoreRepository.
+func (s *scoreRepository) QueryByRoundOrdered(round int) (iter.Seq[*Score], error) {
+	return sqlutil.QueryCommon[Score](s.ctx, ScoreTableName, `WHERE round = $1 ORDER BY git_link COLLATE "C"`, round)
+}
+
 // GetRound implements ScoreRepository.
 func (s *scoreRepository) GetRound() (int, error) {
 	var result int
@@ -213,8 +280,125 @@ func (s *scoreRepository) GetRound() (int, error) {
 	return result, err
 }
 
+// GetPublishedRoundBefore implements ScoreRepository.
+func (s *scoreRepository) GetPublishedRoundBefore(round int) (int, error) {
+	var result *int
+	row := s.ctx.QueryRow(`SELECT MAX(round) FROM `+ScoreTableName+` s
+		WHERE s.round < $1 AND NOT EXISTS (SELECT 1 FROM `+ScoreRoundTableName+` sr
+			WHERE sr.round = s.round AND (sr.blocked IS TRUE OR sr.finished IS FALSE))`, round)
+	if err := row.Scan(&result); err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, nil
+	}
+	return *result, nil
+}
+
 func NewScoreRepository(appDb storage.AppDatabaseContext) ScoreRepository {
 	return &scoreRepository{
 		ctx: appDb,
 	}
 }
+
+// QueryUnscoredLinks implements ScoreRepository.
+func (s *scoreRepository) QueryUnscoredLinks(round int) (iter.Seq[string], error) {
+	return gitlinksQuery(s.ctx, `SELECT git_link FROM (SELECT git_link FROM all_gitlinks
+		EXCEPT SELECT git_link FROM `+ScoreTableName+` WHERE round = $1) t
+		ORDER BY git_link COLLATE "C"`, round)
+}
+
+// QueryLinksComputedBefore implements ScoreRepository.
+func (s *scoreRepository) QueryLinksComputedBefore(round int, t time.Time) (iter.Seq[string], error) {
+	return gitlinksQuery(s.ctx, `SELECT s.git_link FROM `+ScoreTableName+` s
+		LEFT JOIN `+ScoreRoundTableName+` r ON r.round = COALESCE(s.computed_round, s.round)
+		WHERE s.round = $1 AND (r.start_time IS NULL OR r.start_time < $2)
+		ORDER BY s.git_link COLLATE "C"`, round, t)
+}
+
+// CarryForward implements ScoreRepository.
+func (s *scoreRepository) CarryForward(fromRound int, toRound int) (int64, error) {
+	db, err := s.ctx.GetDatabaseConnection()
+	if err != nil {
+		return 0, err
+	}
+	// a resumed round skips links which already have a score, so the
+	// scores must never be visible without their details
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(`INSERT INTO `+ScoreTableName+`
+		(git_link, dist_score, lang_score, git_score, score, update_time, round, profile_name, profile_hash, computed_round)
+	SELECT o.git_link, o.dist_score, o.lang_score, o.git_score, o.score, now(), $2, o.profile_name, o.profile_hash, COALESCE(o.computed_round, o.round)
+	FROM `+ScoreTableName+` o
+	WHERE o.round = $1
+		AND o.git_link IN (SELECT git_link FROM all_gitlinks)
+		AND NOT EXISTS (SELECT 1 FROM `+ScoreTableName+` n WHERE n.round = $2 AND n.git_link = o.git_link)`,
+		fromRound, toRound)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// old and new score of a link are matched by git_link, as a link only
+	// has one score in a round. Recomputed scores are computed in toRound,
+	// only the carried ones are computed before.
+	detailTables := []struct {
+		table   string
+		columns string
+	}{
+		{ScoreDistTableName, "distribution_dependencies_id"},
+		{ScoreLangTableName, "lang_ecosystems_id"},
+		{ScoreGitTableName, "git_metrics_id"},
+		{ScoreBreakdownTableName, "dimension, metric, value, normalized, weight, contribution"},
+	}
+	for _, t := range detailTables {
+		_, err := tx.Exec(`INSERT INTO `+t.table+` (score_id, `+t.columns+`)
+		SELECT n.id, `+prefixColumns("d", t.columns)+`
+		FROM `+ScoreTableName+` o
+		JOIN `+ScoreTableName+` n ON n.git_link = o.git_link AND n.round = $2 AND n.computed_round < $2
+		JOIN `+t.table+` d ON d.score_id = o.id
+		WHERE o.round = $1`, fromRound, toRound)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DeleteByRoundAfter implements ScoreRepository.
+func (s *scoreRepository) DeleteByRoundAfter(round int, after string) (int64, error) {
+	for _, table := range []string{ScoreDistTableName, ScoreLangTableName, ScoreGitTableName, ScoreBreakdownTableName} {
+		_, err := s.ctx.Exec(`DELETE FROM `+table+` WHERE score_id IN
+			(SELECT id FROM `+ScoreTableName+` WHERE round = $1 AND git_link COLLATE "C" > $2)`, round, after)
+		if err != nil {
+			return 0, err
+		}
+	}
+	result, err := s.ctx.Exec(`DELETE FROM `+ScoreTableName+`
+		WHERE round = $1 AND git_link COLLATE "C" > $2`, round, after)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func prefixColumns(prefix string, columns string) string {
+	cols := strings.Split(columns, ",")
+	for i, c := range cols {
+		cols[i] = prefix + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"iter"
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type ScoreAnomalyRepository interface {
+	/** QUERY **/
+	// Query anomalies of the round, dimension is ignored if empty
+	QueryByRound(round int, dimension string, skip int, take int) (iter.Seq[*ScoreAnomaly], error)
+	CountByRound(round int, dimension string) (int, error)
+
+	/** INSERT/UPDATE **/
+	BatchInsert(data []*ScoreAnomaly) error
+	DeleteByRound(round int) error
+}
+
+// ScoreAnomaly is an abnormal change of a dimension score of a link
+// between a round and the previous one.
+type ScoreAnomaly struct {
+	ID         *int64 `pk:"true" generated:"true"`
+	Round      *int
+	GitLink    *string
+	Dimension  *string
+	Previous   *float64
+	Current    *float64
+	Delta      *float64
+	ZScore     *float64
+	CreateTime *time.Time
+}
+
+const ScoreAnomalyTableName = "score_anomalies"
+
+type scoreAnomalyRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ ScoreAnomalyRepository = (*scoreAnomalyRepository)(nil)
+
+func NewScoreAnomalyRepository(appDb storage.AppDatabaseContext) ScoreAnomalyRepository {
+	return &scoreAnomalyRepository{ctx: appDb}
+}
+
+// QueryByRound implements ScoreAnomalyRepository.
+func (s *scoreAnomalyRepository) QueryByRound(round int, dimension string, skip int, take int) (iter.Seq[*ScoreAnomaly], error) {
+	return sqlutil.QueryCommon[ScoreAnomaly](s.ctx, ScoreAnomalyTableName,
+		`WHERE round = $1 AND ($2::varchar = '' OR dimension = $2)
+		ORDER BY abs(z_score) DESC, id LIMIT $3 OFFSET $4`,
+		round, dimension, take, skip)
+}
+
+// CountByRound implements ScoreAnomalyRepository.
+func (s *scoreAnomalyRepository) CountByRound(round int, dimension string) (int, error) {
+	row := s.ctx.QueryRow(`SELECT COUNT(*) FROM `+ScoreAnomalyTableName+`
+		WHERE round = $1 AND ($2::varchar = '' OR dimension = $2)`, round, dimension)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// BatchInsert implements ScoreAnomalyRepository.
+func (s *scoreAnomalyRepository) BatchInsert(data []*ScoreAnomaly) error {
+	if len(data) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, d := range data {
+		d.CreateTime = &now
+	}
+	return sqlutil.BatchInsert(s.ctx, ScoreAnomalyTableName, data)
+}
+
+// DeleteByRound implements ScoreAnomalyRepository.
+func (s *scoreAnomalyRepository) DeleteByRound(round int) error {
+	_, err := s.ctx.Exec(`DELETE FROM `+ScoreAnomalyTableName+` WHERE round = $1`, round)
+	return err
+}
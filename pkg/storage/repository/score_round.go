@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
+)
+
+type ScoreRoundRepository interface {
+	/** QUERY **/
+	GetByRound(round int) (*ScoreRound, error)
+	GetLatest() (*ScoreRound, error)
+
+	/** INSERT/UPDATE **/
+	InsertOrUpdate(data *ScoreRound) error
+}
+
+// ScoreRound records the inputs of a scores-caculator run which are
+// shared by all links, so a round can be reproduced and the next round
+// can tell what has changed.
+type ScoreRound struct {
+	Round       *int `pk:"true"`
+	ProfileName *string
+	ProfileHash *string
+	// the profile in json
+	Profile       **string
+	Normalization *string
+	// json object, distribution name -> package count
+	PackageList *string
+	// json object, parameters of data-driven normalization modes fitted
+	// from the population of the round
+	FittedParams   **string
+	Incremental    *bool
+	Recomputed     *int
+	CarriedForward *int
+	// links scored in both this and the previous round, and links with
+	// at least one anomaly, see ScoreAnomaly
+	ComparedLinks  *int
+	AnomalousLinks *int
+	// blocked rounds are not published
+	Blocked *bool
+	// the round is written in chunks ordered by git_link, Checkpoint is
+	// the last link of the last written chunk until the round is finished
+	Checkpoint *string
+	Finished   *bool
+	// time when the input data is loaded, input rows updated after this
+	// time are not included in the round
+	StartTime  *time.Time
+	UpdateTime *time.Time
+}
+
+const ScoreRoundTableName = "score_rounds"
+
+type scoreRoundRepository struct {
+	ctx storage.AppDatabaseContext
+}
+
+var _ ScoreRoundRepository = (*scoreRoundRepository)(nil)
+
+func NewScoreRoundRepository(appDb storage.AppDatabaseContext) ScoreRoundRepository {
+	return &scoreRoundRepository{ctx: appDb}
+}
+
+// GetByRound implements ScoreRoundRepository.
+func (s *scoreRoundRepository) GetByRound(round int) (*ScoreRound, error) {
+	return sqlutil.QueryCommonFirst[ScoreRound](s.ctx, ScoreRoundTableName, "WHERE round = $1", round)
+}
+
+// GetLatest implements ScoreRoundRepository.
+func (s *scoreRoundRepository) GetLatest() (*ScoreRound, error) {
+	return sqlutil.QueryCommonFirst[ScoreRound](s.ctx, ScoreRoundTableName, "ORDER BY round DESC")
+}
+
+// InsertOrUpdate implements ScoreRoundRepository.
+func (s *scoreRoundRepository) InsertOrUpdate(data *ScoreRound) error {
+	if data.Round == nil {
+		return ErrInvalidInput
+	}
+	data.UpdateTime = sqlutil.ToData(time.Now())
+	return sqlutil.Upsert(s.ctx, ScoreRoundTableName, data)
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"log"
 	"sync"
 
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/alpine"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/archlinux"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/aur"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/centos"
+	"github.com/HUSTSecLab/OpenSift/pkg/collector/deb822"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/debian"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/deepin"
 	"github.com/HUSTSecLab/OpenSift/pkg/collector/fedora"
@@ -28,12 +30,18 @@ var (
 	workerCount = pflag.Int("worker", 1, "number of workers")
 	batchSize   = pflag.Int("batch", 1000, "batch size")
 	downloadDir = pflag.String("downloadDir", "./download", "download directory")
+	debWeights  = pflag.StringToString("deb-weights", nil, "weights of the relationships of the Debian family between 0 and 1, e.g. recommends=0.5,suggests=0")
 )
 
 func main() {
 	config.RegistCommonFlags(pflag.CommandLine)
 	config.ParseFlags(pflag.CommandLine)
 
+	weights, err := deb822.ParseWeights(*debWeights)
+	if err != nil {
+		log.Fatalf("Invalid --deb-weights: %v", err)
+	}
+
 	if *flagType == "" {
 		var wg sync.WaitGroup
 		wg.Add(11)
@@ -44,15 +52,15 @@ func main() {
 		}()
 		go func() {
 			defer wg.Done()
-			debian.NewDebianCollector().Collect(*flagGenDot)
+			debian.NewDebianCollector(weights).Collect(*flagGenDot)
 		}()
 		go func() {
 			defer wg.Done()
-			deepin.NewDeepinCollector().Collect(*flagGenDot)
+			deepin.NewDeepinCollector(weights).Collect(*flagGenDot)
 		}()
 		go func() {
 			defer wg.Done()
-			ubuntu.NewUbuntuCollector().Collect(*flagGenDot)
+			ubuntu.NewUbuntuCollector(weights).Collect(*flagGenDot)
 		}()
 		// go func() {
 		// 	defer wg.Done()
@@ -88,7 +96,7 @@ func main() {
 		}()
 		go func() {
 			defer wg.Done()
-			openkylin.NewOpenKylinCollector().Collect(*flagGenDot)
+			openkylin.NewOpenKylinCollector(weights).Collect(*flagGenDot)
 		}()
 		go func() {
 			defer wg.Done()
@@ -105,11 +113,11 @@ func main() {
 		case "archlinux":
 			archlinux.NewArchLinuxCollector().Collect(*flagGenDot)
 		case "debian":
-			debian.NewDebianCollector().Collect(*flagGenDot)
+			debian.NewDebianCollector(weights).Collect(*flagGenDot)
 		case "deepin":
-			deepin.NewDeepinCollector().Collect(*flagGenDot)
+			deepin.NewDeepinCollector(weights).Collect(*flagGenDot)
 		case "ubuntu":
-			ubuntu.NewUbuntuCollector().Collect(*flagGenDot)
+			ubuntu.NewUbuntuCollector(weights).Collect(*flagGenDot)
 		case "nix":
 			nix.NewNixCollector().Collect(*workerCount, *batchSize, *flagGenDot)
 		case "homebrew":
@@ -127,7 +135,7 @@ func main() {
 		case "openeuler":
 			openeuler.NewOpenEulerCollector().Collect(*flagGenDot)
 		case "openkylin":
-			openkylin.NewOpenKylinCollector().Collect(*flagGenDot)
+			openkylin.NewOpenKylinCollector(weights).Collect(*flagGenDot)
 		case "opencloud":
 			opencloud.NewOpenCloudCollector().Collect(*flagGenDot)
 		case "opemanolis":
@@ -35,8 +35,14 @@ func fix(ctx storage.AppDatabaseContext, link string) {
 		return
 	}
 
+	b, err := git.NewBackend(r)
+	if err != nil {
+		logger.Errorf("Open %s failed: %s", link, err)
+		return
+	}
+
 	result := git.NewRepo()
-	err = result.WalkRepo(r)
+	err = result.WalkRepo(b)
 
 	if err != nil {
 		logger.Errorf("WalkRepo %s failed: %s", link, err)
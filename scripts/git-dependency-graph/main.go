@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/gitgraph"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
+	"github.com/spf13/pflag"
+)
+
+var (
+	flagRegistry = pflag.StringToString("registry", nil,
+		"text output of the link enumerator of a lang ecosystem, e.g. cargo=cargo.txt,npm=npm.txt")
+	flagTypes = pflag.StringSlice("type", nil, "lang ecosystems to update, all lang ecosystems if empty")
+	flagDists = pflag.StringSlice("dist", []string{"debian", "ubuntu", "fedora", "arch", "alpine"},
+		"distros whose package tables resolve native dependencies, in order of preference, native relationships are not updated if empty")
+)
+
+func parseType(name string) repository.LangEcosystemType {
+	for _, t := range repository.LangEcosystemTypes() {
+		if t.String() == name {
+			return t
+		}
+	}
+	logger.Fatalf("Unknown lang ecosystem %s", name)
+	return repository.Others
+}
+
+func main() {
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+
+	ac := storage.GetDefaultAppDatabaseContext()
+	resolver := gitgraph.NewResolver()
+
+	for name, path := range *flagRegistry {
+		t := parseType(name)
+		f, err := os.Open(path)
+		if err != nil {
+			logger.Fatalf("Failed to open %s: %v", path, err)
+		}
+		links, err := gitgraph.ReadRegistryLinks(f)
+		f.Close()
+		if err != nil {
+			logger.Fatalf("Failed to read %s: %v", path, err)
+		}
+		for pkg, link := range links {
+			resolver.AddRegistryLink(t, pkg, link)
+		}
+		logger.Infof("%d %s registry links read", len(links), t)
+	}
+
+	types := repository.LangEcosystemTypes()
+	if len(*flagTypes) > 0 {
+		types = types[:0:0]
+		for _, name := range *flagTypes {
+			types = append(types, parseType(name))
+		}
+	}
+
+	deps, native, err := gitgraph.Load(ac, resolver)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := gitgraph.Collect(ac, gitgraph.Build(resolver, deps), types); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	if len(*flagDists) == 0 {
+		return
+	}
+	nativeResolver := gitgraph.NewNativeResolver()
+	dists := make([]repository.DistPackageTablePrefix, 0, len(*flagDists))
+	for _, name := range *flagDists {
+		dists = append(dists, repository.DistPackageTablePrefix(name))
+	}
+	if err := gitgraph.LoadDistPackages(ac, nativeResolver, dists); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := gitgraph.CollectNative(ac, gitgraph.BuildNative(nativeResolver, native)); err != nil {
+		logger.Fatalf("%v", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/repository"
 	"github.com/HUSTSecLab/OpenSift/pkg/storage/sqlutil"
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/lib/pq"
 	"github.com/spf13/pflag"
 )
 
@@ -86,12 +87,21 @@ func main() {
 
 			// repo.Show()
 			gitMetric := &repository.GitMetric{
-				GitLink:          &link,
-				CommitFrequency:  sqlutil.ToNullable(repo.CommitFrequency),
-				ContributorCount: sqlutil.ToNullable(repo.ContributorCount),
-				CreatedSince:     sqlutil.ToNullable(repo.CreatedSince),
-				UpdatedSince:     sqlutil.ToNullable(repo.UpdatedSince),
-				OrgCount:         sqlutil.ToNullable(repo.OrgCount),
+				GitLink:               &link,
+				CommitFrequency:       sqlutil.ToNullable(repo.CommitFrequency),
+				ContributorCount:      sqlutil.ToNullable(repo.ContributorCount),
+				CreatedSince:          sqlutil.ToNullable(repo.CreatedSince),
+				UpdatedSince:          sqlutil.ToNullable(repo.UpdatedSince),
+				OrgCount:              sqlutil.ToNullable(repo.OrgCount),
+				BusFactor:             sqlutil.ToNullable(repo.BusFactor),
+				TopAuthorShare:        sqlutil.ToNullable(repo.TopAuthorShare),
+				ActiveMaintainers:     sqlutil.ToNullable(repo.ActiveMaintainers),
+				ReleasesLastYear:      sqlutil.ToNullable(repo.ReleasesLastYear),
+				MedianReleaseInterval: sqlutil.ToNullable(repo.MedianReleaseInterval),
+				LastRelease:           sqlutil.ToData(repo.LastReleaseTime()),
+				SignedReleaseRatio:    sqlutil.ToNullable(repo.SignedReleaseRatio),
+				License:               sqlutil.ToNullable(pq.StringArray(repo.License)),
+				LicenseExpression:     sqlutil.ToData(repo.SPDXExpression()),
 			}
 
 			mu.Lock()
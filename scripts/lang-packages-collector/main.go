@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/HUSTSecLab/OpenSift/pkg/config"
+	"github.com/HUSTSecLab/OpenSift/pkg/langcollector"
+	"github.com/HUSTSecLab/OpenSift/pkg/logger"
+	"github.com/HUSTSecLab/OpenSift/pkg/storage"
+	"github.com/spf13/pflag"
+)
+
+var (
+	flagType    = pflag.String("type", "", "type of the language registry, all registries if empty")
+	workerCount = pflag.Int("worker", 8, "number of workers")
+	downloadDir = pflag.String("downloadDir", "./download", "download directory")
+)
+
+func registries() map[string]langcollector.Registry {
+	return map[string]langcollector.Registry{
+		"rubygems":  langcollector.NewRubyGemsRegistry(*workerCount),
+		"packagist": langcollector.NewPackagistRegistry(*workerCount),
+		"hackage":   langcollector.NewHackageRegistry(*workerCount),
+		"hex":       langcollector.NewHexRegistry(*workerCount),
+		"conan":     langcollector.NewConanRegistry(*downloadDir),
+		"conda":     langcollector.NewCondaRegistry(),
+		"swift":     langcollector.NewSwiftRegistry(*workerCount),
+	}
+}
+
+func main() {
+	config.RegistCommonFlags(pflag.CommandLine)
+	config.ParseFlags(pflag.CommandLine)
+
+	ac := storage.GetDefaultAppDatabaseContext()
+	all := registries()
+
+	if *flagType != "" {
+		r, ok := all[*flagType]
+		if !ok {
+			logger.Fatalf("Unknown registry %s", *flagType)
+		}
+		if err := langcollector.Collect(ac, r); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		return
+	}
+
+	for name, r := range all {
+		if err := langcollector.Collect(ac, r); err != nil {
+			logger.Errorf("Failed to collect %s: %v", name, err)
+		}
+	}
+}